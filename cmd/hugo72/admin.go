@@ -0,0 +1,158 @@
+package main
+
+import (
+	"cmp"
+	"crypto/subtle"
+	_ "embed"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// adminHTML je šablona stránky administrace textů webu.
+//
+//go:embed admin.html
+var adminHTML string
+
+var adminTemplate = template.Must(template.New("admin").Parse(adminHTML))
+
+// Nejdelší texty, které administrace přijme (ve znacích).
+const (
+	maxNadpis = 200
+	maxZprava = 5000
+)
+
+// defaultAdminUser je uživatel administrace bez server.adminUser v konfiguraci.
+const defaultAdminUser = "admin"
+
+// adminPage jsou data šablony administrace.
+type adminPage struct {
+	Nadpis    string
+	Zprava    string
+	Override  bool // Texty jsou upravené v administraci, ne převzaté z Excel souboru
+	UpdatedAt time.Time
+	UpdatedBy string
+	Notice    string
+	Error     string
+	Running   *runStatus
+	LastRun   *runStatus
+}
+
+// adminHandler obsluhuje stránku /admin, na které pořadatel upraví nadpis
+// a zprávu webu a tlačítkem Publikovat spustí převod, sestavení a nasazení.
+// Uložené texty při převodu nahradí texty z hlavičky Excel souboru; tlačítko
+// "Použít texty z Excelu" úpravy zruší.
+func adminHandler(cfg *config.Config, status *statusTracker, r *runner) http.Handler {
+	messagesFile := convert.MessagesFile(cfg)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		messages, err := convert.ReadMessages(messagesFile)
+		page := adminPage{
+			Nadpis:    messages.Nadpis,
+			Zprava:    messages.Zprava,
+			Override:  messages.Nadpis != "" || messages.Zprava != "",
+			UpdatedAt: messages.UpdatedAt,
+			UpdatedBy: messages.UpdatedBy,
+		}
+		if err != nil {
+			page.Error = err.Error()
+		}
+		if cfg.Phase1 != nil {
+			if converted, ok := readConverted(cfg.Phase1.OutputFile); ok {
+				page.Nadpis = cmp.Or(page.Nadpis, converted.Info.Nadpis)
+				page.Zprava = cmp.Or(page.Zprava, converted.Info.Zprava)
+			}
+		}
+		switch req.URL.Query().Get("done") {
+		case "publish":
+			page.Notice = i18n.T("Texty jsou uložené, publikace webu běží.")
+		case "reset":
+			page.Notice = i18n.T("Web znovu používá texty z Excel souboru, publikace webu běží.")
+		}
+
+		if req.Method == http.MethodPost {
+			if !sameOrigin(req) {
+				http.Error(w, i18n.T("Požadavek z cizí stránky byl odmítnut."), http.StatusForbidden)
+				return
+			}
+			user, _, _ := req.BasicAuth()
+			updated := convert.Messages{UpdatedAt: time.Now(), UpdatedBy: user}
+			action := req.PostFormValue("action")
+			if action == "publish" {
+				updated.Nadpis = strings.TrimSpace(req.PostFormValue("nadpis"))
+				updated.Zprava = strings.TrimSpace(req.PostFormValue("zprava"))
+				page.Nadpis, page.Zprava = updated.Nadpis, updated.Zprava
+			}
+			switch {
+			case action != "publish" && action != "reset":
+				page.Error = i18n.T("Neznámá akce.")
+			case utf8.RuneCountInString(updated.Nadpis) > maxNadpis:
+				page.Error = i18n.T("Nadpis je příliš dlouhý.")
+			case utf8.RuneCountInString(updated.Zprava) > maxZprava:
+				page.Error = i18n.T("Zpráva je příliš dlouhá.")
+			default:
+				if err := convert.WriteMessages(messagesFile, updated); err != nil {
+					page.Error = err.Error()
+					break
+				}
+				logging.Phase("admin").Info("Texty webu upraveny v administraci.", "action", action, "user", user, "remote", req.RemoteAddr)
+				r.trigger(runRequest{from: "convert", to: "deploy"})
+				http.Redirect(w, req, "/admin?done="+action, http.StatusSeeOther)
+				return
+			}
+		}
+
+		snap := status.snapshot()
+		page.Running, page.LastRun = snap.Running, snap.LastRun
+		code := http.StatusOK
+		if page.Error != "" && req.Method == http.MethodPost {
+			code = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(code)
+		adminTemplate.Execute(w, page)
+	})
+}
+
+// requireAdmin propustí jen požadavky přihlášené jménem a heslem administrace
+// (HTTP Basic), aby se do ní pořadatel přihlásil přímo v prohlížeči.
+func requireAdmin(server *config.Server, next http.Handler) http.Handler {
+	wantUser := cmp.Or(server.AdminUser, defaultAdminUser)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		user, password, ok := req.BasicAuth()
+		userOK := subtle.ConstantTimeCompare([]byte(user), []byte(wantUser))
+		passwordOK := subtle.ConstantTimeCompare([]byte(password), []byte(server.AdminPassword))
+		if !ok || userOK&passwordOK != 1 {
+			if ok {
+				logging.Phase("admin").Warn("Neúspěšné přihlášení do administrace.", "user", user, "remote", req.RemoteAddr)
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="hugo72", charset="UTF-8"`)
+			http.Error(w, i18n.T("Pro vstup do administrace se přihlaste."), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// sameOrigin ověří, že formulář byl odeslán ze stránky tohoto serveru. Prohlížeč
+// jinak přihlašovací údaje připojí i k požadavku z cizí stránky.
+func sameOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		origin = req.Referer()
+	}
+	if origin == "" {
+		// Požadavek mimo prohlížeč (např. curl) hlavičky neposílá.
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == req.Host
+}
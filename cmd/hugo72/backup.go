@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"hugo72/internal/backup"
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/output"
+	"hugo72/internal/problems"
+	"hugo72/internal/runid"
+	"hugo72/pkg/build"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// Výchozí nastavení záloh, pokud je konfigurace neuvádí.
+const (
+	defaultBackupDir  = ".hugo72/backups"
+	defaultBackupKeep = 20
+)
+
+// backupOptions vrací nastavení záloh z konfigurace, nebo false, pokud jsou
+// zálohy vypnuté.
+func backupOptions(cfg *config.Config) (backup.Options, bool) {
+	opts := backup.Options{Dir: defaultBackupDir, Keep: defaultBackupKeep}
+	if cfg.Backup == nil {
+		return opts, true
+	}
+	if cfg.Backup.Dir != "" {
+		opts.Dir = cfg.Backup.Dir
+	}
+	if cfg.Backup.Keep > 0 {
+		opts.Keep = cfg.Backup.Keep
+	}
+	// Hodnota je ověřená při načtení konfigurace.
+	opts.MaxAge, _ = time.ParseDuration(cfg.Backup.MaxAge)
+	return opts, !cfg.Backup.Disabled
+}
+
+// backupItems vrací soubory, které běh čte a přepisuje: konfiguraci, vstupy
+// a výstup převodu a seznam souborů sestaveného webu.
+func backupItems(cfg *config.Config) []backup.Item {
+	items := []backup.Item{{Kind: "config", Path: *configPath}}
+	if cfg.Phase1 != nil {
+		items = append(items,
+			backup.Item{Kind: "input", Path: cfg.Phase1.InputFile},
+			backup.Item{Kind: "input", Path: convert.MessagesFile(cfg)},
+			backup.Item{Kind: "input", Path: convert.ResponsesFile(cfg)},
+			backup.Item{Kind: "output", Path: cfg.Phase1.OutputFile},
+		)
+	}
+	if cfg.Phase1 != nil || cfg.Phase2 != nil {
+		items = append(items, backup.Item{Kind: "public", Path: filepath.Join(build.SiteDir(cfg), "public"), Manifest: true})
+	}
+	return items
+}
+
+// snapshotRun uloží před během příkazu command zálohu souborů, které běh
+// změní (viz backup). Záloha nese identifikátor běhu, aby šlo podle historie
+// běhů dohledat stav před kterýmkoli z nich. Selhání zálohy běh nezastaví,
+// zaznamená se jako varování. Zkušební běh nic nezálohuje.
+func snapshotRun(cfg *config.Config, command string) {
+	opts, enabled := backupOptions(cfg)
+	if !enabled || dryrun.Enabled() {
+		return
+	}
+	logger := logging.Phase("backup")
+	archive, err := backup.Create(opts, runid.Current(), command, backupItems(cfg))
+	switch {
+	case err != nil:
+		logger.Warn("Zálohu před během se nepodařilo uložit.", "error", err)
+		problems.Warn("backup", opts.Dir, err)
+	case archive == "":
+		logger.Debug("Soubory se od poslední zálohy nezměnily, zálohu neukládám.")
+	default:
+		logger.Info("Záloha před během uložena.", "file", archive)
+	}
+}
+
+// runBackups pracuje se zálohami ukládanými před každým během:
+//
+//	backups [list]
+//	backups restore <identifikátor>
+//
+// Výpis list ukazuje nejnovější zálohy nahoře. Identifikátor zálohy je
+// identifikátor běhu, před kterým vznikla (viz history). Obnova vrátí
+// konfiguraci, vstupy a výstup převodu do stavu před tímto během; předtím
+// zálohuje jejich současný stav, takže i obnovu lze vrátit. Sestavený web
+// se poté obnoví během run (nebo run --from build).
+func runBackups(_ context.Context, cfg *config.Config, args []string) error {
+	opts, _ := backupOptions(cfg)
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+
+	switch sub {
+	case "list":
+		infos, err := backup.List(opts.Dir)
+		if err != nil {
+			return err
+		}
+		if output.Enabled() {
+			if infos == nil {
+				infos = []backup.Info{}
+			}
+			output.Result(infos)
+			return nil
+		}
+		listBackups(os.Stdout, infos)
+		return nil
+
+	case "restore":
+		if len(args) != 1 {
+			return exitcode.With(exitcode.Usage, errors.New(i18n.T("použití: backups restore <identifikátor>")))
+		}
+		return restoreBackup(cfg, opts, args[0])
+	}
+	return exitcode.With(exitcode.Usage, i18n.Errorf("neznámý příkaz: backups %s", sub))
+}
+
+// restoreBackup obnoví soubory ze zálohy id. Drží přitom zámek běhu, aby
+// obnovované soubory nepřepisoval souběžný běh.
+func restoreBackup(cfg *config.Config, opts backup.Options, id string) error {
+	info, err := backup.Find(opts.Dir, id)
+	if err != nil {
+		return exitcode.With(exitcode.Usage, err)
+	}
+	logger := logging.Phase("backup")
+	if dryrun.Enabled() {
+		for _, f := range info.Files {
+			if !f.Manifest {
+				logger.Info("Zkušební běh, soubor by se obnovil.", "file", f.Path)
+			}
+		}
+		output.Result(info)
+		return nil
+	}
+
+	lock, err := acquireRunLock(context.Background(), runLockFile, "restore", false)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	current := backup.Options{Dir: opts.Dir} // Záloha současného stavu nesmí smazat obnovovanou zálohu.
+	if archive, err := backup.Create(current, runid.New(), "restore", backupItems(cfg)); err != nil {
+		return i18n.Errorf("současný stav se nepodařilo zálohovat, obnova se neprovedla: %w", err)
+	} else if archive != "" {
+		logger.Info("Současný stav zálohován.", "file", archive)
+	}
+
+	restored, err := backup.Restore(info)
+	for _, f := range restored {
+		logger.Info("Soubor obnoven ze zálohy.", "file", f.Path, "backup", info.ID)
+	}
+	if err != nil {
+		return err
+	}
+	output.Result(map[string]any{"backup": info.ID, "restored": restored})
+	if !output.Enabled() {
+		fmt.Printf(i18n.T("Obnoveno souborů: %d ze zálohy %s. Web obnoví hugo72 run.\n"), len(restored), info.ID)
+	}
+	return nil
+}
+
+// listBackups vypíše zálohy jako tabulku, nejnovější záloha nahoře.
+func listBackups(w io.Writer, infos []backup.Info) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T("ZÁLOHA\tČAS\tPŘÍKAZ\tVELIKOST\tSOUBORY"))
+	for i := len(infos) - 1; i >= 0; i-- {
+		info := infos[i]
+		var names []string
+		for _, f := range info.Files {
+			names = append(names, filepath.Base(f.Path))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d B\t%s\n",
+			info.ID, info.CreatedAt.Local().Format(time.DateTime), info.Command, info.Size, strings.Join(names, ", "))
+	}
+	tw.Flush()
+}
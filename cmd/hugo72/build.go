@@ -0,0 +1,70 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/pkg/build"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// runBuild sestaví web Hugem a případně zabalí výstup do artefaktu:
+//
+//	build [--artifact build.tar.gz]
+//
+// Adresář webu je phase2.siteDir z konfigurace. S phase2.badges se souběžně
+// se sestavením vytvoří stránka se jmenovkami přihlášených, kteří přijdou;
+// QR kód jmenovky nese osobní odkaz z pozvánky (s mailer.rsvpUrl), jinak
+// e-mail, takže ho přečte checkin scan. Při zkušebním běhu (--dry-run)
+// jen ověří předpoklady a vypíše příkaz hugo.
+func runBuild(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	artifact := fs.String("artifact", "", i18n.T("po sestavení zabalit public/ do tohoto souboru tar.gz"))
+	fs.Parse(args)
+
+	opts := build.Options{
+		SiteDir:  build.SiteDir(cfg),
+		Artifact: *artifact,
+		DryRun:   dryrun.Enabled(),
+	}
+	if cfg.Phase2 != nil && cfg.Phase2.Badges != "" {
+		opts.Badges = cfg.Phase2.Badges
+		if !opts.DryRun {
+			converted, ok := readConverted(cfg.Phase1.OutputFile)
+			if !ok {
+				return exitcode.With(exitcode.Data, i18n.Errorf("výstup převodu '%s' nelze načíst, spusťte nejdřív convert", cfg.Phase1.OutputFile))
+			}
+			opts.BadgeTitle, opts.Attendees = converted.Info.Nadpis, badgeAttendees(cfg.Mailer, converted.Users)
+		}
+	}
+	result, err := build.Build(ctx, opts)
+	if err != nil {
+		return err
+	}
+	output.Result(result)
+	return nil
+}
+
+// badgeAttendees vrací jmenovky přihlášených, kteří přijdou. QR kód nese osobní
+// odkaz na formulář (viz rsvpLink), a pokud adresa formuláře není nastavená, e-mail;
+// účastník bez e-mailu má v QR kódu jméno.
+func badgeAttendees(mc *config.Mailer, users []convert.User) []build.Attendee {
+	var attendees []build.Attendee
+	for _, user := range users {
+		if user.Prijde != convert.Ano {
+			continue
+		}
+		code := cmp.Or(user.Email, user.Jmeno)
+		if mc != nil && mc.RSVPURL != "" && user.Email != "" {
+			code = rsvpLink(mc, user)
+		}
+		attendees = append(attendees, build.Attendee{Name: user.Jmeno, Code: code})
+	}
+	return attendees
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// checkinFile je záznam příchodů na akci. Soubor je ve formátu JSON Lines
+// a záznamy se do něj jen připisují; zrušený příchod je další záznam.
+const checkinFile = ".hugo72/checkin.jsonl"
+
+// checkinEntry je jeden záznam příchodu účastníka.
+type checkinEntry struct {
+	Email     string    `json:"email"`
+	Jmeno     string    `json:"jmeno"`
+	At        time.Time `json:"at"`
+	Cancelled bool      `json:"cancelled,omitempty"` // Zrušení dříve zapsaného příchodu
+}
+
+// attendance je docházka jednoho účastníka ve výpisu checkin export.
+type attendance struct {
+	Jmeno     string     `json:"jmeno"`
+	Email     string     `json:"email"`
+	Prijde    string     `json:"prijde"`
+	Present   bool       `json:"present"`
+	ArrivedAt *time.Time `json:"arrivedAt,omitempty"`
+}
+
+// runCheckin eviduje příchody účastníků v den akce:
+//
+//	checkin [scan]
+//	checkin status
+//	checkin export [--format csv|json] [--output soubor]
+//
+// Režim scan čte ze standardního vstupu po řádcích e-mail účastníka, jeho
+// jméno nebo odkaz z QR kódu pozvánky (s parametrem email, viz příkaz mail);
+// čtečka QR kódů se chová jako klávesnice, takže stačí skenovat. Řádek
+// začínající "-" příchod zruší. Po každém příchodu se vypíše počet přítomných
+// z očekávaných (přihlášených, kteří přijdou). Příkaz status vypíše jen počty,
+// export docházku všech účastníků.
+func runCheckin(_ context.Context, cfg *config.Config, args []string) error {
+	sub := "scan"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub, args = args[0], args[1:]
+	}
+	if cfg.Phase1 == nil {
+		return exitcode.With(exitcode.Config, errors.New(i18n.T("konfigurace neobsahuje sekci phase1")))
+	}
+	converted, ok := readConverted(cfg.Phase1.OutputFile)
+	if !ok {
+		return exitcode.With(exitcode.Data, i18n.Errorf("výstup převodu '%s' nelze načíst, spusťte nejdřív convert", cfg.Phase1.OutputFile))
+	}
+	present, err := readCheckins(checkinFile)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "scan":
+		fs := flag.NewFlagSet("checkin scan", flag.ExitOnError)
+		fs.Parse(args)
+		return scanCheckins(os.Stdin, os.Stdout, converted.Users, present)
+
+	case "status":
+		fs := flag.NewFlagSet("checkin status", flag.ExitOnError)
+		fs.Parse(args)
+		arrived, expected := checkinCounts(converted.Users, present)
+		if output.Enabled() {
+			output.Result(map[string]any{"arrived": arrived, "expected": expected, "total": len(converted.Users)})
+			return nil
+		}
+		fmt.Printf(i18n.T("Přítomno %d z %d očekávaných (přihlášeno %d).\n"), arrived, expected, len(converted.Users))
+		return nil
+
+	case "export":
+		fs := flag.NewFlagSet("checkin export", flag.ExitOnError)
+		format := fs.String("format", "csv", i18n.T("formát výpisu: csv nebo json"))
+		outputFile := fs.String("output", "", i18n.T("soubor pro výpis (výchozí standardní výstup)"))
+		fs.Parse(args)
+		if *format != "csv" && *format != "json" {
+			return exitcode.With(exitcode.Usage, i18n.Errorf("neznámý formát '%s', povoleno je csv nebo json", *format))
+		}
+
+		list := attendanceOf(converted.Users, present)
+		if output.Enabled() && *outputFile == "" {
+			output.Result(list)
+			return nil
+		}
+		w := io.Writer(os.Stdout)
+		if *outputFile != "" {
+			file, err := os.Create(*outputFile)
+			if err != nil {
+				return i18n.Errorf("chyba při vytváření souboru '%s': %w", *outputFile, err)
+			}
+			defer file.Close()
+			w = file
+		}
+		if err := writeAttendance(w, *format, list); err != nil {
+			return i18n.Errorf("chyba při zápisu docházky: %w", err)
+		}
+		if *outputFile != "" {
+			arrived, expected := checkinCounts(converted.Users, present)
+			output.Result(map[string]any{"file": *outputFile, "arrived": arrived, "expected": expected, "total": len(converted.Users)})
+		}
+		return nil
+	}
+	return exitcode.With(exitcode.Usage, i18n.Errorf("neznámý příkaz: checkin %s", sub))
+}
+
+// scanCheckins čte řádky ze vstupu, dohledá k nim účastníky a zapíše jejich
+// příchod (nebo zrušení). Skončí na konci vstupu (Ctrl-D).
+func scanCheckins(in io.Reader, out io.Writer, users []convert.User, present map[string]checkinEntry) error {
+	arrived, expected := checkinCounts(users, present)
+	fmt.Fprintf(out, i18n.T("Přítomno %d z %d. Zadejte e-mail, jméno nebo naskenujte QR kód (\"-\" na začátku příchod zruší, Ctrl-D ukončí).\n"), arrived, expected)
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		query, cancel := strings.CutPrefix(line, "-")
+		user, problem := findAttendee(users, strings.TrimSpace(query))
+		if problem != "" {
+			fmt.Fprintln(out, problem)
+			continue
+		}
+
+		key := strings.ToLower(user.Email)
+		previous, wasPresent := present[key]
+		entry := checkinEntry{Email: user.Email, Jmeno: user.Jmeno, At: time.Now(), Cancelled: cancel}
+		switch {
+		case cancel && !wasPresent:
+			fmt.Fprintf(out, i18n.T("%s není zapsán(a) jako přítomný(á).\n"), user.Jmeno)
+			continue
+		case !cancel && wasPresent:
+			fmt.Fprintf(out, i18n.T("%s už je přítomen/přítomna od %s.\n"), user.Jmeno, previous.At.Local().Format(time.TimeOnly))
+			continue
+		}
+		if !dryrun.Enabled() {
+			if err := appendCheckin(checkinFile, entry); err != nil {
+				return err
+			}
+		}
+		if cancel {
+			delete(present, key)
+		} else {
+			present[key] = entry
+		}
+
+		arrived, expected = checkinCounts(users, present)
+		note := ""
+		if user.Prijde != convert.Ano {
+			note = i18n.T(" (nebyl(a) přihlášen(a))")
+		}
+		if cancel {
+			fmt.Fprintf(out, i18n.T("Zrušeno: %s. Přítomno %d z %d.\n"), user.Jmeno, arrived, expected)
+		} else {
+			fmt.Fprintf(out, i18n.T("Vítejte, %s%s. Přítomno %d z %d.\n"), user.Jmeno, note, arrived, expected)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return i18n.Errorf("chyba při čtení vstupu: %w", err)
+	}
+	arrived, expected = checkinCounts(users, present)
+	output.Result(map[string]any{"arrived": arrived, "expected": expected, "total": len(users)})
+	return nil
+}
+
+// findAttendee najde účastníka podle e-mailu, odkazu s parametrem email nebo
+// celého jména (bez ohledu na velikost písmen). Jméno musí být jednoznačné.
+// Pokud účastníka nelze určit, vrací hlášení pro obsluhu.
+func findAttendee(users []convert.User, query string) (convert.User, string) {
+	if u, err := url.Parse(query); err == nil && u.Scheme != "" && u.Query().Has("email") {
+		query = u.Query().Get("email")
+	}
+	var byName []convert.User
+	for _, user := range users {
+		if user.Email != "" && strings.EqualFold(user.Email, query) {
+			return user, ""
+		}
+		if strings.EqualFold(user.Jmeno, query) {
+			byName = append(byName, user)
+		}
+	}
+	switch {
+	case len(byName) == 1 && byName[0].Email != "":
+		return byName[0], ""
+	case len(byName) > 1:
+		return convert.User{}, fmt.Sprintf(i18n.T("Jméno '%s' má %d účastníků, zadejte e-mail."), query, len(byName))
+	case len(byName) == 1:
+		return convert.User{}, fmt.Sprintf(i18n.T("Účastník '%s' nemá e-mail, příchod nelze zapsat."), query)
+	}
+	return convert.User{}, fmt.Sprintf(i18n.T("Účastník '%s' v seznamu není."), query)
+}
+
+// checkinCounts vrací počet přítomných a očekávaných účastníků (kteří přijdou).
+func checkinCounts(users []convert.User, present map[string]checkinEntry) (arrived, expected int) {
+	for _, user := range users {
+		if user.Prijde == convert.Ano {
+			expected++
+		}
+	}
+	return len(present), expected
+}
+
+// attendanceOf sestaví docházku všech účastníků ze seznamu.
+func attendanceOf(users []convert.User, present map[string]checkinEntry) []attendance {
+	list := []attendance{}
+	for _, user := range users {
+		a := attendance{Jmeno: user.Jmeno, Email: user.Email, Prijde: string(user.Prijde)}
+		if entry, ok := present[strings.ToLower(user.Email)]; ok && user.Email != "" {
+			a.Present, a.ArrivedAt = true, &entry.At
+		}
+		list = append(list, a)
+	}
+	return list
+}
+
+// writeAttendance zapíše docházku jako CSV (pro tabulkový procesor) nebo JSON.
+func writeAttendance(w io.Writer, format string, list []attendance) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	}
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Jméno", "E-mail", "Přijde", "Přítomen", "Příchod"})
+	for _, a := range list {
+		present, arrivedAt := "Ne", ""
+		if a.Present {
+			present, arrivedAt = "Ano", a.ArrivedAt.Local().Format(time.DateTime)
+		}
+		cw.Write([]string{a.Jmeno, a.Email, a.Prijde, present, arrivedAt})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readCheckins načte záznam příchodů a vrátí přítomné účastníky podle
+// e-mailu malými písmeny. Chybějící soubor znamená, že zatím nikdo nepřišel.
+func readCheckins(filePath string) (map[string]checkinEntry, error) {
+	present := map[string]checkinEntry{}
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return present, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba při otevírání záznamu příchodů '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry checkinEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, i18n.Errorf("chyba v záznamu příchodů '%s' na řádku %d: %w", filePath, line, err)
+		}
+		key := strings.ToLower(entry.Email)
+		if entry.Cancelled {
+			delete(present, key)
+		} else if _, ok := present[key]; !ok {
+			present[key] = entry
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.Errorf("chyba při čtení záznamu příchodů '%s': %w", filePath, err)
+	}
+	return present, nil
+}
+
+// appendCheckin připíše záznam na konec záznamu příchodů.
+func appendCheckin(filePath string, entry checkinEntry) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře '%s': %w", filepath.Dir(filePath), err)
+	}
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return i18n.Errorf("chyba při otevírání záznamu příchodů '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return i18n.Errorf("chyba při zápisu záznamu příchodů '%s': %w", filePath, err)
+	}
+	return nil
+}
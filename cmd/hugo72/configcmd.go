@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// runConfig pracuje s konfiguračním souborem:
+//
+//	config validate    ověří konfiguraci a předpoklady pro spuštění, nic neprovádí
+//	config schema      vypíše JSON Schema konfigurace
+//
+// Konfiguraci si načítá sám, aby mohl vypsat všechny problémy a ne skončit u první chyby.
+func runConfig(_ context.Context, _ *config.Config, args []string) error {
+	if len(args) == 0 {
+		return exitcode.With(exitcode.Usage, errors.New(i18n.T("chybí příkaz: config validate nebo config schema")))
+	}
+
+	switch args[0] {
+	case "validate":
+		fs := flag.NewFlagSet("config validate", flag.ExitOnError)
+		offline := fs.Bool("offline", false, i18n.T("neověřovat překlad adres FTP serverů v DNS"))
+		fs.Parse(args[1:])
+		return validateConfig(*configPath, *env, *offline)
+	case "schema":
+		if output.Enabled() {
+			output.Result(json.RawMessage(config.Schema))
+			return nil
+		}
+		_, err := os.Stdout.Write(config.Schema)
+		return err
+	default:
+		return exitcode.With(exitcode.Usage, i18n.Errorf("neznámý příkaz: config %s", args[0]))
+	}
+}
+
+// diagnostics sbírá výsledky kontroly. Chyby brání spuštění, varování ne.
+// S výpisem --json se nic nevypisuje a výsledek kontroly obsahuje oba seznamy.
+type diagnostics struct {
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// error zaznamená a vypíše chybu hodnoty na zadané cestě.
+func (d *diagnostics) error(path, format string, args ...any) {
+	msg := path + ": " + fmt.Sprintf(i18n.T(format), args...)
+	d.Errors = append(d.Errors, msg)
+	if !output.Enabled() {
+		fmt.Printf(i18n.T("CHYBA: %s\n"), msg)
+	}
+}
+
+// warn zaznamená a vypíše varování k hodnotě na zadané cestě.
+func (d *diagnostics) warn(path, format string, args ...any) {
+	msg := path + ": " + fmt.Sprintf(i18n.T(format), args...)
+	d.Warnings = append(d.Warnings, msg)
+	if !output.Enabled() {
+		fmt.Printf(i18n.T("VAROVÁNÍ: %s\n"), msg)
+	}
+}
+
+// validateConfig ověří konfiguraci proti schématu a hodnoty v ní, a poté
+// předpoklady jednotlivých fází: programy, soubory a adresy serverů.
+// Nic nepřevádí, nesestavuje ani se nepřipojuje k serveru.
+func validateConfig(configPath, profile string, offline bool) error {
+	d := diagnostics{Errors: []string{}, Warnings: []string{}}
+	defer output.Result(&d)
+
+	cfg, err := config.Check(configPath, profile)
+	if err != nil {
+		if output.Enabled() {
+			// Víceřádková chyba má na prvním řádku jen nadpis, na dalších jednotlivé problémy.
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) > 1 {
+				lines = lines[1:]
+			}
+			d.Errors = append(d.Errors, lines...)
+		} else {
+			fmt.Println(err)
+		}
+		return exitcode.With(exitcode.Config, i18n.Errorf("konfigurace '%s' neprošla kontrolou", configPath))
+	}
+	if cfg.Language != "" {
+		i18n.Set(cfg.Language)
+	}
+
+	if cfg.Phase1 != nil || cfg.Phase2 != nil {
+		checkPhase2(&d, cfg)
+	}
+	if cfg.Phase3 != nil {
+		checkPhase3(&d, cfg.Phase3, offline)
+	}
+
+	switch {
+	case len(d.Errors) > 0:
+		return exitcode.With(exitcode.Config, i18n.Errorf("konfigurace '%s' je platná, ale chybí předpoklady pro spuštění (chyby: %d)", configPath, len(d.Errors)))
+	case output.Enabled():
+	case len(d.Warnings) > 0:
+		fmt.Printf(i18n.T("Konfigurace '%s' je v pořádku (varování: %d).\n"), configPath, len(d.Warnings))
+	default:
+		fmt.Printf(i18n.T("Konfigurace '%s' je v pořádku.\n"), configPath)
+	}
+	return nil
+}
+
+// checkPhase2 ověří, že je k dispozici Hugo a adresář webu.
+func checkPhase2(d *diagnostics, cfg *config.Config) {
+	if _, err := exec.LookPath("hugo"); err != nil {
+		d.error("phase2", "program hugo nebyl nalezen v PATH, nainstalujte ho z https://gohugo.io")
+	}
+	if cfg.Phase2 == nil || cfg.Phase2.SiteDir == "" {
+		if info, err := os.Stat("phase2"); err != nil || !info.IsDir() {
+			d.error("phase2.siteDir", "výchozí adresář webu 'phase2' neexistuje, nastavte phase2.siteDir")
+		}
+	}
+}
+
+// checkPhase3 ověří soubory k nahrání a předpoklady připojení ke všem cílům.
+func checkPhase3(d *diagnostics, phase3 *config.Phase3, offline bool) {
+	for i, file := range phase3.FilesToUpload {
+		if _, err := os.Stat(file); err != nil {
+			d.error(fmt.Sprintf("phase3.files_to_upload[%d]", i), "soubor '%s' nelze použít: %v", file, err)
+		}
+	}
+	if phase3.MaintenancePage != "" {
+		if _, err := os.Stat(phase3.MaintenancePage); err != nil {
+			d.error("phase3.maintenancePage", "soubor '%s' nelze použít: %v", phase3.MaintenancePage, err)
+		}
+	}
+
+	if len(phase3.Targets) == 0 || phase3.FtpHost != "" {
+		checkTarget(d, "phase3", phase3.Target, offline)
+	}
+	for _, name := range slices.Sorted(maps.Keys(phase3.Targets)) {
+		checkTarget(d, "phase3.targets."+name, phase3.Targets[name], offline)
+	}
+}
+
+// checkTarget ověří předpoklady připojení k jednomu cíli, aniž by se k němu připojil.
+func checkTarget(d *diagnostics, path string, target config.Target, offline bool) {
+	switch {
+	case target.Protocol == "sftp":
+		if target.HostKey == "" && target.KnownHosts == "" {
+			d.warn(path+".hostKey", "klíč serveru se ověří podle ~/.ssh/known_hosts; na jiném počítači (např. v CI) nastavte hostKey")
+		}
+	case target.FtpPassword == "":
+		d.warn(path+".ftpPassword", "heslo je prázdné, server nejspíš přihlášení odmítne")
+	}
+	switch {
+	case target.Protocol == "sftp":
+	case target.TLS == "":
+		d.warn(path+".tls", "spojení není šifrované, heslo se posílá čitelně; pokud to server umí, nastavte \"explicit\"")
+	case target.InsecureSkipVerify:
+		d.warn(path+".insecureSkipVerify", "ověřování certifikátu je vypnuté, nastavte raději caFile nebo certFingerprint")
+	}
+
+	if target.Quirks.NoSize {
+		d.warn(path+".quirks.noSize", "volba je zastaralá a nemá účinek, nasazení příkaz SIZE neposílá; lze ji odstranit")
+	}
+
+	if offline {
+		return
+	}
+	host := target.FtpHost
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		d.error(path+".ftpHost", "adresu '%s' nelze přeložit: %v", host, err)
+	}
+}
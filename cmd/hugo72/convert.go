@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// runConvert převede Excel soubor ze sekce phase1 konfigurace na JSON:
+//
+//	convert
+//
+// Při zkušebním běhu (--dry-run) Excel soubor jen přečte a vypíše, co by zapsal.
+func runConvert(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	fs.Parse(args)
+
+	opts, err := convert.OptionsFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	opts.DryRun = dryrun.Enabled()
+	result, err := convert.Convert(ctx, opts)
+	if err != nil {
+		return err
+	}
+	output.Result(result)
+	return nil
+}
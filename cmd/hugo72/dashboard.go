@@ -0,0 +1,174 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"hugo72/internal/logging"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// dashboardPage je stránka přehledu pro sdílenou obrazovku pořadatelů.
+// Data si každých 30 sekund načítá z GET /dashboard.json.
+//
+//go:embed dashboard.html
+var dashboardPage []byte
+
+// Počty záznamů, které přehled zobrazuje.
+const (
+	dashboardTrendPoints = 200 // Nejvyšší počet bodů grafu přihlášek
+	dashboardErrors      = 10  // Počet posledních chyb
+)
+
+// dashboardData je odpověď GET /dashboard.json.
+type dashboardData struct {
+	Title      string           `json:"title"`
+	LastUpdate string           `json:"lastUpdate,omitempty"` // Čas poslední změny seznamu podle Excel souboru
+	Attendees  *attendeeStats   `json:"attendees,omitempty"`
+	Trend      []trendPoint     `json:"trend"`
+	Phases     []dashboardPhase `json:"phases"`
+	Running    *runStatus       `json:"running,omitempty"`
+	Errors     []dashboardError `json:"errors"`
+}
+
+// attendeeStats jsou počty přihlášených podle odpovědi.
+type attendeeStats struct {
+	Total      int  `json:"total"`
+	Attending  int  `json:"attending"`
+	Declined   int  `json:"declined"`
+	Unanswered int  `json:"unanswered"`
+	CheckedIn  *int `json:"checkedIn,omitempty"` // Přítomní podle příkazu checkin, jakmile někdo přišel
+}
+
+// trendPoint je počet přihlášek po jednom převodu.
+type trendPoint struct {
+	Time      time.Time `json:"time"`
+	Records   int64     `json:"records"`
+	Attending int64     `json:"attending"`
+}
+
+// dashboardPhase je poslední běh fáze podle historie běhů.
+type dashboardPhase struct {
+	Name            string    `json:"name"`
+	OK              bool      `json:"ok"`
+	At              time.Time `json:"at"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// dashboardError je chyba jednoho z posledních běhů.
+type dashboardError struct {
+	Time  time.Time `json:"time"`
+	Phase string    `json:"phase,omitempty"`
+	Error string    `json:"error"`
+}
+
+// dashboardHandler odpoví na GET / stránkou přehledu. Stránka sama žádná data
+// neobsahuje, proto ji lze otevřít bez tokenu; token předá datům z odkazu
+// ve tvaru http://127.0.0.1:8072/#token=...
+func dashboardHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(dashboardPage)
+	})
+}
+
+// dashboardDataHandler odpoví na GET /dashboard.json počty přihlášených
+// z výstupu převodu, vývojem přihlášek, posledním během každé fáze
+// a posledními chybami z historie běhů.
+func dashboardDataHandler(cfg *config.Config, status *statusTracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		data := dashboardData{Trend: []trendPoint{}, Phases: []dashboardPhase{}, Errors: []dashboardError{}}
+		if cfg.Phase1 != nil {
+			readAttendees(cfg.Phase1.OutputFile, &data)
+		}
+		data.Running = status.snapshot().Running
+
+		entries, err := readRunHistory(runHistoryFile)
+		if err != nil {
+			logging.Phase("dashboard").Warn("Historii běhů nelze načíst.", "error", err)
+		}
+		seen := map[string]bool{}
+		for i := len(entries) - 1; i >= 0; i-- {
+			e := entries[i]
+			for _, p := range e.Phases {
+				if p.Skipped || seen[p.Name] {
+					continue
+				}
+				seen[p.Name] = true
+				data.Phases = append(data.Phases, dashboardPhase{Name: p.Name, OK: p.OK, At: e.StartedAt, DurationSeconds: p.DurationSeconds, Error: p.Error})
+			}
+			if !e.OK && len(data.Errors) < dashboardErrors {
+				dErr := dashboardError{Time: e.StartedAt, Error: e.Error}
+				for _, p := range e.Phases {
+					if !p.OK {
+						dErr.Phase, dErr.Error = p.Name, p.Error
+					}
+				}
+				data.Errors = append(data.Errors, dErr)
+			}
+		}
+		slices.SortFunc(data.Phases, func(a, b dashboardPhase) int { return phaseIndex(a.Name) - phaseIndex(b.Name) })
+
+		for _, e := range entries {
+			if e.Records == nil {
+				continue
+			}
+			point := trendPoint{Time: e.StartedAt, Records: *e.Records}
+			if e.Attending != nil {
+				point.Attending = *e.Attending
+			}
+			// Body beze změny se vynechají, graf ukazuje jen změny počtu.
+			if n := len(data.Trend); n > 0 && data.Trend[n-1].Records == point.Records && data.Trend[n-1].Attending == point.Attending {
+				continue
+			}
+			data.Trend = append(data.Trend, point)
+		}
+		if len(data.Trend) > dashboardTrendPoints {
+			data.Trend = data.Trend[len(data.Trend)-dashboardTrendPoints:]
+		}
+		writeJSON(w, http.StatusOK, data)
+	})
+}
+
+// readConverted načte výstup převodu. Vrací false, pokud převod ještě neproběhl.
+func readConverted(filePath string) (convert.Data72, bool) {
+	var converted convert.Data72
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return converted, false
+	}
+	return converted, json.Unmarshal(content, &converted) == nil
+}
+
+// readAttendees doplní do přehledu nadpis a počty přihlášených z výstupu převodu.
+// Pokud převod ještě neproběhl, počty zůstanou prázdné.
+func readAttendees(filePath string, data *dashboardData) {
+	converted, ok := readConverted(filePath)
+	if !ok {
+		return
+	}
+	data.Title, data.LastUpdate = converted.Info.Nadpis, converted.Info.LastUpdate
+
+	stats := &attendeeStats{Total: len(converted.Users)}
+	for _, user := range converted.Users {
+		switch user.Prijde {
+		case convert.Ano:
+			stats.Attending++
+		case convert.Ne:
+			stats.Declined++
+		default:
+			stats.Unanswered++
+		}
+	}
+	if present, err := readCheckins(checkinFile); err == nil && len(present) > 0 {
+		arrived, _ := checkinCounts(converted.Users, present)
+		stats.CheckedIn = &arrived
+	}
+	data.Attendees = stats
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/internal/runid"
+	"hugo72/pkg/config"
+	"hugo72/pkg/deploy"
+)
+
+// runDeploy nasadí soubory z konfigurace nebo z artefaktu:
+//
+//	deploy [--target název,...|--all] [--force] [--accept-new] [--artifact build.tar.gz] [--only vzor] [vzor...]
+//
+// Se vzory nasadí jen odpovídající soubory, na více cílů nasazuje souběžně.
+// S --accept-new se při prvním připojení k SFTP serveru uloží jeho klíč do
+// known_hosts; klíč, který se od uloženého liší, se nepřijme.
+func runDeploy(ctx context.Context, cfg *config.Config, args []string) error {
+	f := newDeployFlags(flag.ExitOnError)
+	f.fs.Parse(args)
+
+	// Vzory lze zadat přepínačem --only i jako samostatné argumenty.
+	result, err := deploy.Deploy(ctx, cfg, deploy.Options{
+		Targets:  f.targets,
+		All:      *f.all,
+		Force:    *f.force,
+		Artifact: *f.artifact,
+		Only:     append(f.only, f.fs.Args()...),
+		DryRun:   dryrun.Enabled(),
+		RunID:    runid.Current(),
+
+		AcceptNewHostKey: *f.acceptNew,
+	})
+	if result != nil {
+		output.Result(result.Value())
+	}
+	return err
+}
+
+// deployFlags jsou přepínače příkazu deploy. Kromě runDeploy je čte i otisk
+// fáze deploy pro run --resume, který musí znát nasazovaný artefakt.
+type deployFlags struct {
+	fs        *flag.FlagSet
+	targets   stringList
+	all       *bool
+	force     *bool
+	acceptNew *bool
+	artifact  *string
+	only      stringList
+}
+
+// newDeployFlags vrací přepínače příkazu deploy se sadou fs připravenou ke čtení argumentů.
+func newDeployFlags(handling flag.ErrorHandling) *deployFlags {
+	f := &deployFlags{fs: flag.NewFlagSet("deploy", handling)}
+	f.fs.Var(&f.targets, "target", i18n.T("název cíle nasazení z konfigurace, lze zadat opakovaně (výchozí \"default\")"))
+	f.all = f.fs.Bool("all", false, i18n.T("nasadit souběžně na všechny cíle z konfigurace"))
+	f.force = f.fs.Bool("force", false, i18n.T("přepsat existující zámek nasazení na serveru"))
+	f.acceptNew = f.fs.Bool("accept-new", false, i18n.T("uložit do known_hosts klíč SFTP serveru, ke kterému se připojuje poprvé"))
+	f.artifact = f.fs.String("artifact", "", i18n.T("nasadit obsah sestaveného artefaktu tar.gz místo souborů z konfigurace"))
+	f.fs.Var(&f.only, "only", i18n.T("nasadit jen soubory odpovídající vzoru (např. \"data/\"), lze zadat opakovaně"))
+	return f
+}
+
+// deployArtifact vrací artefakt zadaný v přepínačích fáze deploy (--artifact),
+// nebo "", pokud žádný zadaný není nebo přepínače nejdou přečíst.
+func deployArtifact(args []string) string {
+	f := newDeployFlags(flag.ContinueOnError)
+	f.fs.SetOutput(io.Discard)
+	if err := f.fs.Parse(args); err != nil {
+		return ""
+	}
+	return *f.artifact
+}
+
+// runPromote znovu nasadí artefakt ověřený na jiném cíli:
+//
+//	promote [--from staging] [--to production] [--force] [--accept-new]
+func runPromote(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("promote", flag.ExitOnError)
+	from := fs.String("from", "staging", i18n.T("cíl, jehož poslední úspěšné nasazení se má povýšit"))
+	to := fs.String("to", "production", i18n.T("cíl, na který se artefakt nasadí"))
+	force := fs.Bool("force", false, i18n.T("přepsat existující zámek nasazení na serveru"))
+	acceptNew := fs.Bool("accept-new", false, i18n.T("uložit do known_hosts klíč SFTP serveru, ke kterému se připojuje poprvé"))
+	fs.Parse(args)
+
+	result, err := deploy.Promote(ctx, cfg, deploy.PromoteOptions{
+		From:   *from,
+		To:     *to,
+		Force:  *force,
+		DryRun: dryrun.Enabled(),
+		RunID:  runid.Current(),
+
+		AcceptNewHostKey: *acceptNew,
+	})
+	if result != nil {
+		output.Result(result.Value())
+	}
+	return err
+}
+
+// runDeploys vypíše historii nasazení:
+//
+//	deploys list
+func runDeploys(_ context.Context, cfg *config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "list" {
+		return exitcode.With(exitcode.Usage, i18n.Errorf("neznámý příkaz: deploys %s", strings.Join(args, " ")))
+	}
+
+	entries, err := deploy.History(cfg)
+	if err != nil {
+		return err
+	}
+	if output.Enabled() {
+		if entries == nil {
+			entries = []deploy.HistoryEntry{}
+		}
+		output.Result(entries)
+		return nil
+	}
+	deploy.WriteHistory(os.Stdout, entries)
+	return nil
+}
+
+// stringList je přepínač, který lze zadat opakovaně nebo s hodnotami oddělenými čárkou.
+type stringList []string
+
+// String vrací hodnoty přepínače oddělené čárkou.
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set přidá hodnoty z jednoho výskytu přepínače.
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/pkg/build"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+	"hugo72/pkg/deploy"
+)
+
+// Stavy výsledku kontroly příkazu doctor.
+const (
+	doctorOK      = "ok"
+	doctorWarning = "warning"
+	doctorError   = "error"
+)
+
+// doctorCheck je výsledek jedné kontroly příkazu doctor. Neúspěšná kontrola
+// má radu, jak problém odstranit.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok, warning nebo error
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// runDoctor ověří, že je připraveno vše, co běh potřebuje, a vypíše přehled
+// kontrol s radami k opravě:
+//
+//	doctor [--offline]
+//
+// Na rozdíl od config validate skutečně otevře Excel soubor, spustí Hugo,
+// zkusí zápis do výstupních adresářů a přihlásí se ke každému cíli nasazení
+// (s --offline se k serverům nepřipojuje). Nic nepřevádí, nesestavuje ani
+// nenahrává. Konfiguraci si načítá sám, aby chyba v ní byla jen jednou z kontrol.
+func runDoctor(ctx context.Context, _ *config.Config, args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	offline := flags.Bool("offline", false, i18n.T("nepřipojovat se k cílům nasazení"))
+	flags.Parse(args)
+
+	checks := doctorChecks(ctx, *offline)
+
+	var failed int
+	for _, c := range checks {
+		if c.Status == doctorError {
+			failed++
+		}
+	}
+	output.Result(map[string]any{"checks": checks})
+	if !output.Enabled() {
+		printDoctor(checks)
+	}
+	if failed > 0 {
+		return exitcode.With(exitcode.Config, i18n.Errorf("kontrola prostředí zjistila chyby: %d", failed))
+	}
+	return nil
+}
+
+// doctorChecks provede všechny kontroly v pořadí fází běhu.
+func doctorChecks(ctx context.Context, offline bool) []doctorCheck {
+	var checks []doctorCheck
+
+	cfg, err := config.Check(*configPath, *env)
+	if err != nil {
+		checks = append(checks, doctorCheck{
+			Name:   i18n.T("konfigurace"),
+			Status: doctorError,
+			Detail: err.Error(),
+			Hint:   i18n.T("Opravte uvedené hodnoty; podrobnosti vypíše hugo72 config validate."),
+		})
+	} else {
+		if cfg.Language != "" {
+			i18n.Set(cfg.Language)
+		}
+		checks = append(checks, doctorCheck{Name: i18n.T("konfigurace"), Status: doctorOK, Detail: *configPath})
+	}
+
+	hugo := doctorCheck{Name: "hugo", Status: doctorOK}
+	if c := checkHugo(ctx); c.OK {
+		hugo.Detail = c.Detail
+	} else {
+		hugo.Status, hugo.Detail = doctorError, c.Detail
+		hugo.Hint = i18n.T("Nainstalujte Hugo z https://gohugo.io a ověřte, že je v PATH.")
+	}
+	checks = append(checks, hugo)
+
+	// Bez platné konfigurace nejsou další kontroly k čemu vztáhnout.
+	if cfg == nil {
+		return checks
+	}
+
+	if cfg.Phase1 != nil {
+		checks = append(checks, checkWorkbook(cfg.Phase1.InputFile))
+	}
+	if cfg.Phase1 != nil || cfg.Phase2 != nil {
+		checks = append(checks, checkSite(build.SiteDir(cfg)))
+	}
+	for _, dir := range outputDirs(cfg) {
+		checks = append(checks, checkWritable(dir))
+	}
+	if cfg.Phase3 != nil && !offline {
+		for _, name := range targetNames(cfg.Phase3) {
+			checks = append(checks, checkDeployTarget(ctx, cfg, name))
+		}
+	}
+	return checks
+}
+
+// checkWorkbook ověří, že vstupní Excel soubor jde otevřít a přečíst.
+func checkWorkbook(inputFile string) doctorCheck {
+	c := doctorCheck{Name: i18n.T("vstupní Excel soubor")}
+	records, err := convert.Check(inputFile)
+	if err != nil {
+		c.Status, c.Detail = doctorError, err.Error()
+		c.Hint = i18n.T("Zkontrolujte cestu phase1.inputFile; soubor musí být ve formátu .xlsx a nesmí být zamčený jiným programem.")
+		return c
+	}
+	c.Status, c.Detail = doctorOK, fmt.Sprintf(i18n.T("%s, přihlášek: %d"), inputFile, records)
+	return c
+}
+
+// checkSite ověří, že adresář webu obsahuje konfiguraci Huga.
+func checkSite(siteDir string) doctorCheck {
+	c := doctorCheck{Name: i18n.T("web Hugo")}
+	// Chybějící Hugo hlásí už jeho vlastní kontrola.
+	if _, err := build.Check(siteDir); err != nil && !errors.Is(err, exec.ErrNotFound) {
+		c.Status, c.Detail = doctorError, err.Error()
+		c.Hint = i18n.T("Nastavte phase2.siteDir na adresář webu s hugo.toml; nový web připraví hugo72 init.")
+		return c
+	}
+	c.Status, c.Detail = doctorOK, siteDir
+	return c
+}
+
+// outputDirs vrací adresáře, do kterých běh zapisuje, bez opakování.
+func outputDirs(cfg *config.Config) []string {
+	dirs := []string{filepath.Dir(stateFile)}
+	if cfg.Phase1 != nil {
+		dirs = append(dirs, filepath.Dir(cfg.Phase1.OutputFile))
+	}
+	if cfg.Phase1 != nil || cfg.Phase2 != nil {
+		dirs = append(dirs, filepath.Join(build.SiteDir(cfg), "public"))
+	}
+	dirs = append(dirs, deploy.LocalDirs(cfg)...)
+	if opts, enabled := backupOptions(cfg); enabled {
+		dirs = append(dirs, opts.Dir)
+	}
+
+	var unique []string
+	for _, dir := range dirs {
+		if dir = filepath.Clean(dir); !slices.Contains(unique, dir) {
+			unique = append(unique, dir)
+		}
+	}
+	return unique
+}
+
+// checkWritable ověří, že do adresáře jde zapsat, zkušebním souborem.
+// Adresář, který ještě neexistuje, je v pořádku, pokud ho jde vytvořit:
+// zkouší se pak zápis do nejbližšího existujícího nadřazeného adresáře.
+func checkWritable(dir string) doctorCheck {
+	c := doctorCheck{Name: fmt.Sprintf(i18n.T("zápis do %s"), dir), Status: doctorOK}
+
+	existing := dir
+	for {
+		_, err := os.Stat(existing)
+		if err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if !errors.Is(err, fs.ErrNotExist) || parent == existing {
+			c.Status, c.Detail = doctorError, err.Error()
+			c.Hint = i18n.T("Ověřte, že cesta je platná a uživatel, pod kterým běh poběží, k ní má přístup.")
+			return c
+		}
+		existing = parent
+	}
+
+	f, err := os.CreateTemp(existing, ".hugo72-doctor-*")
+	if err != nil {
+		c.Status, c.Detail = doctorError, err.Error()
+		c.Hint = i18n.T("Nastavte oprávnění k zápisu pro uživatele, pod kterým běh poběží, a ověřte volné místo na disku.")
+		return c
+	}
+	f.Close()
+	os.Remove(f.Name())
+	if existing != dir {
+		c.Detail = i18n.T("adresář neexistuje, běh ho vytvoří")
+	}
+	return c
+}
+
+// targetNames vrací názvy cílů nasazení: výchozí cíl, pokud je nastavený
+// nebo jiný cíl není, a pojmenované cíle podle abecedy.
+func targetNames(phase3 *config.Phase3) []string {
+	var names []string
+	if len(phase3.Targets) == 0 || phase3.FtpHost != "" {
+		names = append(names, "default")
+	}
+	return append(names, slices.Sorted(maps.Keys(phase3.Targets))...)
+}
+
+// checkDeployTarget se připojí k cíli nasazení a podle kroku, na kterém
+// připojení selhalo, poradí, co opravit.
+func checkDeployTarget(ctx context.Context, cfg *config.Config, name string) doctorCheck {
+	c := doctorCheck{Name: fmt.Sprintf(i18n.T("cíl %s"), name), Status: doctorError}
+	check, err := deploy.CheckTarget(ctx, cfg, name, nil)
+	switch {
+	case check == nil:
+		c.Detail = err.Error()
+		c.Hint = i18n.T("Opravte nastavení cíle v sekci phase3.")
+	case !check.Reachable && check.Protocol == "sftp":
+		c.Detail = err.Error()
+		c.Hint = i18n.T("Ověřte ftpHost včetně portu (host:22), že SSH server běží a že spojení nezablokuje firewall.")
+	case !check.Reachable:
+		c.Detail = err.Error()
+		c.Hint = i18n.T("Ověřte ftpHost včetně portu (host:21), že server běží a že spojení nezablokuje firewall.")
+	case !check.LoggedIn && check.Protocol == "sftp":
+		c.Detail = err.Error()
+		c.Hint = i18n.T("Ověřte ftpUser, ftpPassword nebo keyFile a klíč serveru (hostKey, knownHosts); neznámý klíč po ověření otisku uloží deploy --accept-new.")
+	case !check.LoggedIn:
+		c.Detail = err.Error()
+		c.Hint = i18n.T("Ověřte ftpUser a ftpPassword, u šifrovaného spojení také tls, caFile nebo certFingerprint.")
+	case err != nil:
+		c.Detail = err.Error()
+		c.Hint = i18n.T("Ověřte, že uživatel smí číst vzdálený adresář remoteDir.")
+	case check.Lock != "":
+		c.Status = doctorWarning
+		c.Detail = fmt.Sprintf(i18n.T("%s: adresář %s je zamčený nasazením (%s)"), check.Host, check.RemoteDir, check.Lock)
+		c.Hint = i18n.T("Pokud žádné nasazení neběží, zámek přepíše deploy --force.")
+	case !check.DirExists:
+		c.Status = doctorWarning
+		c.Detail = fmt.Sprintf(i18n.T("%s: adresář %s neexistuje, nasazení ho vytvoří"), check.Host, check.RemoteDir)
+		c.Hint = i18n.T("Pokud má adresář existovat, zkontrolujte remoteDir.")
+	default:
+		c.Status = doctorOK
+		c.Detail = fmt.Sprintf(i18n.T("%s: přihlášení v pořádku, %s obsahuje položek: %d"), check.Host, check.RemoteDir, check.Entries)
+	}
+	return c
+}
+
+// printDoctor vypíše přehled kontrol jako tabulku s radami pod neúspěšnými
+// kontrolami a souhrn na konci.
+func printDoctor(checks []doctorCheck) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T("STAV\tKONTROLA\tVÝSLEDEK"))
+	counts := map[string]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+		status := "OK"
+		switch c.Status {
+		case doctorWarning:
+			status = i18n.T("VAROVÁNÍ")
+		case doctorError:
+			status = i18n.T("CHYBA")
+		}
+		// Víceřádková chyba (např. z kontroly konfigurace) by rozbila tabulku.
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", status, c.Name, strings.ReplaceAll(c.Detail, "\n", "; "))
+		if c.Hint != "" {
+			fmt.Fprintf(tw, "\t\t→ %s\n", c.Hint)
+		}
+	}
+	tw.Flush()
+	fmt.Printf(i18n.T("\nV pořádku: %d, varování: %d, chyby: %d.\n"), counts[doctorOK], counts[doctorWarning], counts[doctorError])
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"hugo72/internal/defaults"
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// defaultFile je výchozí soubor vložený v programu.
+type defaultFile struct {
+	name        string
+	description string
+	data        []byte
+}
+
+// defaultFiles jsou soubory, které zapíše příkaz export-defaults.
+var defaultFiles = []defaultFile{
+	{name: "config.json", description: "šablona konfigurace", data: defaults.Config},
+	{name: "config.schema.json", description: "JSON Schema konfigurace, odkazuje na něj šablona konfigurace", data: config.Schema},
+	{name: defaults.InvitationName, description: "šablona pozvánky pro příkaz mail", data: defaults.Invitation},
+}
+
+// runExportDefaults zapíše výchozí soubory vložené v programu na disk, kde je lze upravit:
+//
+//	export-defaults [--dir .] [--force] [--list] [soubor...]
+//
+// Bez názvů souborů zapíše všechny. Existující soubory přepíše jen s --force;
+// jinak nezapíše nic, aby se úpravy nepřepsaly jen zčásti.
+func runExportDefaults(_ context.Context, _ *config.Config, args []string) error {
+	fs := flag.NewFlagSet("export-defaults", flag.ExitOnError)
+	dir := fs.String("dir", ".", i18n.T("adresář, do kterého se soubory zapíšou"))
+	force := fs.Bool("force", false, i18n.T("přepsat existující soubory"))
+	list := fs.Bool("list", false, i18n.T("jen vypsat vložené soubory"))
+	fs.Parse(args)
+
+	if *list {
+		names := []string{}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, f := range defaultFiles {
+			names = append(names, f.name)
+			if !output.Enabled() {
+				fmt.Fprintf(tw, "%s\t%s\n", f.name, i18n.T(f.description))
+			}
+		}
+		tw.Flush()
+		output.Result(map[string]any{"files": names})
+		return nil
+	}
+
+	selected := defaultFiles
+	if fs.NArg() > 0 {
+		selected = nil
+		for _, name := range fs.Args() {
+			i := slices.IndexFunc(defaultFiles, func(f defaultFile) bool { return f.name == name })
+			if i < 0 {
+				return exitcode.With(exitcode.Usage, i18n.Errorf("neznámý soubor '%s', vložené soubory vypíše export-defaults --list", name))
+			}
+			selected = append(selected, defaultFiles[i])
+		}
+	}
+
+	var existing []string
+	for _, f := range selected {
+		if _, err := os.Stat(filepath.Join(*dir, f.name)); err == nil {
+			existing = append(existing, f.name)
+		}
+	}
+	if len(existing) > 0 && !*force {
+		return i18n.Errorf("soubory již existují (%s), pro přepsání použijte --force", strings.Join(existing, ", "))
+	}
+
+	logger := logging.Phase("export-defaults")
+	written := []string{}
+	for _, f := range selected {
+		path := filepath.Join(*dir, f.name)
+		written = append(written, path)
+		if dryrun.Enabled() {
+			logger.Info("Zkušební běh, soubor se nezapíše.", "file", path)
+			continue
+		}
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			return i18n.Errorf("chyba při vytváření adresáře '%s': %w", *dir, err)
+		}
+		if err := os.WriteFile(path, f.data, 0o644); err != nil {
+			return i18n.Errorf("chyba při zápisu souboru '%s': %w", path, err)
+		}
+		logger.Info("Soubor zapsán.", "file", path)
+	}
+	output.Result(map[string]any{"files": written, "dryRun": dryrun.Enabled()})
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/pkg/config"
+)
+
+// healthCheck je výsledek jedné kontroly připravenosti.
+type healthCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// healthHandler odpoví na GET /healthz. Odpověď znamená jen to, že proces běží
+// a obsluhuje požadavky; podle ní lze démona restartovat, když přestane odpovídat.
+func healthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+}
+
+// readyHandler odpoví na GET /readyz výsledkem kontrol: konfigurace je načtená,
+// Hugo jde spustit a poslední úspěšný běh není starší než server.staleAfter.
+// Pokud některá kontrola selže, odpoví 503 Service Unavailable.
+func readyHandler(cfg *config.Config, status *statusTracker) http.Handler {
+	var staleAfter time.Duration
+	if cfg.Server != nil && cfg.Server.StaleAfter != "" {
+		// Hodnota je ověřená při načtení konfigurace.
+		staleAfter, _ = time.ParseDuration(cfg.Server.StaleAfter)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		checks := []healthCheck{
+			{Name: "config", OK: cfg != nil},
+			checkHugo(req.Context()),
+		}
+		if staleAfter > 0 {
+			checks = append(checks, checkStale(status, staleAfter))
+		}
+
+		code, result := http.StatusOK, "ok"
+		for _, c := range checks {
+			if !c.OK {
+				code, result = http.StatusServiceUnavailable, "fail"
+			}
+		}
+		writeJSON(w, code, map[string]any{"status": result, "checks": checks})
+	})
+}
+
+// checkHugo ověří, že Hugo je nainstalovaný a jde spustit.
+func checkHugo(ctx context.Context) healthCheck {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "hugo", "version").Output()
+	if err != nil {
+		return healthCheck{Name: "hugo", Detail: err.Error()}
+	}
+	return healthCheck{Name: "hugo", OK: true, Detail: strings.TrimSpace(string(out))}
+}
+
+// checkStale ověří, že poslední úspěšný běh (nebo spuštění démona, pokud žádný
+// ještě neproběhl) není starší než staleAfter.
+func checkStale(status *statusTracker, staleAfter time.Duration) healthCheck {
+	status.mu.Lock()
+	last := status.lastSuccess
+	if last.IsZero() {
+		last = status.since
+	}
+	status.mu.Unlock()
+
+	age := time.Since(last).Round(time.Second)
+	if age > staleAfter {
+		return healthCheck{Name: "lastRun", Detail: fmt.Sprintf(i18n.T("poslední úspěšný běh před %s, limit je %s"), age, staleAfter)}
+	}
+	return healthCheck{Name: "lastRun", OK: true, Detail: fmt.Sprintf(i18n.T("poslední úspěšný běh před %s"), age)}
+}
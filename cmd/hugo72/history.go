@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// runHistoryFile je historie běhů pipeline. Soubor je ve formátu JSON Lines
+// a záznamy se do něj jen připisují, slouží tedy i jako auditní log.
+const runHistoryFile = ".hugo72/runs.jsonl"
+
+// runEntry je jeden záznam historie běhů: kdo a kde běh spustil, které fáze
+// proběhly, jak dlouho trvaly, s jakým výsledkem a co se nasadilo.
+type runEntry struct {
+	ID              int             `json:"id,omitempty"`    // Pořadí záznamu v historii; doplní se při čtení
+	RunID           string          `json:"runId,omitempty"` // Identifikátor běhu (viz runid)
+	StartedAt       time.Time       `json:"startedAt"`
+	DurationSeconds float64         `json:"durationSeconds"`
+	Trigger         string          `json:"trigger"` // Co běh spustilo: manual, change, schedule, retry nebo request
+	User            string          `json:"user"`
+	Host            string          `json:"host"`
+	Dir             string          `json:"dir"` // Pracovní adresář
+	Profile         string          `json:"profile,omitempty"`
+	OK              bool            `json:"ok"`
+	Error           string          `json:"error,omitempty"`
+	Phases          []runPhaseEntry `json:"phases"`
+	Records         *int64          `json:"records,omitempty"`   // Počet přihlášek z převodu
+	Attending       *int64          `json:"attending,omitempty"` // Z toho účastníků, kteří přijdou
+	FilesUploaded   int             `json:"filesUploaded"`
+	BytesUploaded   int64           `json:"bytesUploaded"`
+	Commit          string          `json:"commit,omitempty"` // Commit nasazeného obsahu
+}
+
+// runPhaseEntry je výsledek jedné fáze v záznamu historie.
+type runPhaseEntry struct {
+	Name            string  `json:"name"`
+	OK              bool    `json:"ok"`
+	Skipped         bool    `json:"skipped,omitempty"`
+	Error           string  `json:"error,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// newRunEntry sestaví záznam historie běhu id z doby a výsledků fází.
+func newRunEntry(id, trigger string, started time.Time, timings []phaseTiming, err error) runEntry {
+	entry := runEntry{
+		RunID:           id,
+		StartedAt:       started,
+		DurationSeconds: time.Since(started).Seconds(),
+		Trigger:         trigger,
+		Profile:         *env,
+		OK:              err == nil,
+		Phases:          []runPhaseEntry{},
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	entry.Host, _ = os.Hostname()
+	entry.Dir, _ = os.Getwd()
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	for _, t := range timings {
+		phase := runPhaseEntry{Name: t.name, OK: t.err == nil, Skipped: t.skipped, DurationSeconds: t.duration.Seconds()}
+		if t.err != nil {
+			phase.Error = t.err.Error()
+		}
+		entry.Phases = append(entry.Phases, phase)
+
+		counts := countsOf(t.result)
+		if t.name == "convert" && counts.Records != nil {
+			entry.Records, entry.Attending = counts.Records, counts.Attending
+		}
+		if counts.Commit != "" {
+			entry.Commit = counts.Commit
+		}
+		for _, target := range counts.Targets {
+			entry.FilesUploaded += target.Summary.Uploaded
+			entry.BytesUploaded += target.Summary.BytesUploaded
+		}
+	}
+	return entry
+}
+
+// appendRunHistory připíše záznam na konec historie běhů.
+func appendRunHistory(filePath string, entry runEntry) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře '%s': %w", filepath.Dir(filePath), err)
+	}
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return i18n.Errorf("chyba při otevírání historie běhů '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return i18n.Errorf("chyba při zápisu historie běhů '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// readRunHistory načte všechny záznamy historie v pořadí, v jakém byly zapsány,
+// a očísluje je od 1. Chybějící soubor znamená prázdnou historii.
+func readRunHistory(filePath string) ([]runEntry, error) {
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba při otevírání historie běhů '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	var entries []runEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry runEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, i18n.Errorf("chyba v historii běhů '%s' na řádku %d: %w", filePath, line, err)
+		}
+		entry.ID = len(entries) + 1
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.Errorf("chyba při čtení historie běhů '%s': %w", filePath, err)
+	}
+	return entries, nil
+}
+
+// runHistory vypíše historii běhů pipeline:
+//
+//	history [list] [--limit 20]
+//	history show <číslo>
+//
+// Výpis list ukazuje nejnovější běhy nahoře, show podrobnosti jednoho běhu
+// včetně výsledku každé fáze.
+func runHistory(_ context.Context, _ *config.Config, args []string) error {
+	sub := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub, args = args[0], args[1:]
+	}
+
+	switch sub {
+	case "list":
+		fs := flag.NewFlagSet("history list", flag.ExitOnError)
+		limit := fs.Int("limit", 20, i18n.T("počet vypsaných běhů (0 = všechny)"))
+		fs.Parse(args)
+
+		entries, err := readRunHistory(runHistoryFile)
+		if err != nil {
+			return err
+		}
+		if *limit > 0 && len(entries) > *limit {
+			entries = entries[len(entries)-*limit:]
+		}
+		if output.Enabled() {
+			if entries == nil {
+				entries = []runEntry{}
+			}
+			output.Result(entries)
+			return nil
+		}
+		listRuns(os.Stdout, entries)
+		return nil
+
+	case "show":
+		if len(args) != 1 {
+			return exitcode.With(exitcode.Usage, errors.New(i18n.T("použití: history show <číslo>")))
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return exitcode.With(exitcode.Usage, i18n.Errorf("'%s' není číslo běhu", args[0]))
+		}
+		entries, err := readRunHistory(runHistoryFile)
+		if err != nil {
+			return err
+		}
+		if id < 1 || id > len(entries) {
+			return exitcode.With(exitcode.Usage, i18n.Errorf("běh číslo %d v historii není", id))
+		}
+		if output.Enabled() {
+			output.Result(entries[id-1])
+			return nil
+		}
+		showRun(os.Stdout, entries[id-1])
+		return nil
+	}
+	return exitcode.With(exitcode.Usage, i18n.Errorf("neznámý příkaz: history %s", sub))
+}
+
+// listRuns vypíše běhy jako tabulku, nejnovější běh nahoře.
+func listRuns(w io.Writer, entries []runEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T("Č.\tČAS\tSPUŠTĚNÍ\tUŽIVATEL\tVÝSLEDEK\tFÁZE\tDOBA\tPŘIHLÁŠKY\tCOMMIT"))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		var phases []string
+		status := "ok"
+		for _, p := range e.Phases {
+			switch {
+			case p.Skipped:
+				continue
+			case !p.OK:
+				status = i18n.T("chyba") + " (" + p.Name + ")"
+			}
+			phases = append(phases, p.Name)
+		}
+		if !e.OK && status == "ok" {
+			status = i18n.T("chyba")
+		}
+		records := "-"
+		if e.Records != nil {
+			records = strconv.FormatInt(*e.Records, 10)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.ID, e.StartedAt.Local().Format(time.DateTime), e.Trigger, e.User, status, strings.Join(phases, ","),
+			seconds(e.DurationSeconds), records, shortHash(e.Commit))
+	}
+	tw.Flush()
+}
+
+// showRun vypíše podrobnosti jednoho běhu.
+func showRun(w io.Writer, e runEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, i18n.T("Běh:\t%d\n"), e.ID)
+	fmt.Fprintf(tw, i18n.T("Začátek:\t%s\n"), e.StartedAt.Local().Format(time.DateTime))
+	fmt.Fprintf(tw, i18n.T("Doba:\t%s\n"), seconds(e.DurationSeconds))
+	fmt.Fprintf(tw, i18n.T("Spuštění:\t%s\n"), e.Trigger)
+	fmt.Fprintf(tw, i18n.T("Uživatel:\t%s@%s\n"), e.User, e.Host)
+	fmt.Fprintf(tw, i18n.T("Adresář:\t%s\n"), e.Dir)
+	if e.Profile != "" {
+		fmt.Fprintf(tw, i18n.T("Profil:\t%s\n"), e.Profile)
+	}
+	if e.OK {
+		fmt.Fprintf(tw, i18n.T("Výsledek:\tok\n"))
+	} else {
+		fmt.Fprintf(tw, i18n.T("Výsledek:\tchyba: %s\n"), e.Error)
+	}
+	if e.Records != nil {
+		fmt.Fprintf(tw, i18n.T("Přihlášky:\t%d"), *e.Records)
+		if e.Attending != nil {
+			fmt.Fprintf(tw, i18n.T(" (přijde %d)"), *e.Attending)
+		}
+		fmt.Fprintln(tw)
+	}
+	if e.FilesUploaded > 0 || e.BytesUploaded > 0 {
+		fmt.Fprintf(tw, i18n.T("Nahráno:\t%d souborů, %d B\n"), e.FilesUploaded, e.BytesUploaded)
+	}
+	if e.Commit != "" {
+		fmt.Fprintf(tw, "Commit:\t%s\n", e.Commit)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T("FÁZE\tVÝSLEDEK\tDOBA\tCHYBA"))
+	for _, p := range e.Phases {
+		status := "ok"
+		switch {
+		case p.Skipped:
+			status = i18n.T("přeskočeno")
+		case !p.OK:
+			status = i18n.T("chyba")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Name, status, seconds(p.DurationSeconds), p.Error)
+	}
+	tw.Flush()
+}
+
+// seconds zapíše dobu v sekundách zaokrouhlenou na milisekundy.
+func seconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond).String()
+}
+
+// shortHash zkrátí hash commitu na prvních 12 znaků pro přehledný výpis.
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+
+	"hugo72/internal/hooks"
+	"hugo72/internal/output"
+	"hugo72/internal/problems"
+	"hugo72/pkg/config"
+)
+
+// withHooks obalí příkaz fáze name háčky z konfigurace (hooks.<name>).
+// Háčky pre běží před fází a jejich selhání fázi nespustí, háčky post běží
+// po fázi i po její chybě a dostanou její výsledek. Platí pro samostatný
+// příkaz i pro fázi spuštěnou příkazy run, watch a serve.
+func withHooks(name string, run func(context.Context, *config.Config, []string) error) func(context.Context, *config.Config, []string) error {
+	return func(ctx context.Context, cfg *config.Config, args []string) error {
+		h := cfg.Hooks[name]
+		if err := hooks.Run(ctx, h.Pre, hooks.Payload{Phase: name, Stage: hooks.Pre}); err != nil {
+			return err
+		}
+
+		err := run(ctx, cfg, args)
+		if len(h.Post) == 0 || ctx.Err() != nil {
+			return err
+		}
+		ok := err == nil
+		payload := hooks.Payload{Phase: name, Stage: hooks.Post, OK: &ok, Result: output.Peek()}
+		if err != nil {
+			payload.Error = err.Error()
+		}
+		// Chyba fáze má přednost, selhání háčku je v tom případě jen v logu a v souhrnu problémů.
+		if hookErr := hooks.Run(ctx, h.Post, payload); hookErr != nil {
+			if err == nil {
+				return hookErr
+			}
+			problems.Add(name, "", hookErr)
+		}
+		return err
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// initKeyFile je soubor, do kterého průvodce uloží nově vytvořený klíč age.
+const initKeyFile = ".hugo72/age.key"
+
+// runInit provede uživatele nastavením nového projektu: zeptá se na vstupní soubor,
+// adresář Hugo webu, FTP server a způsob uložení přihlašovacích údajů, zapíše
+// konfiguraci a vytvoří potřebné adresáře. Formát konfigurace se určí podle přípony
+// souboru --output stejně jako při načítání.
+func runInit(_ context.Context, _ *config.Config, args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	outputPath := fs.String("output", "config.json", i18n.T("soubor, do kterého se konfigurace zapíše (JSON, YAML nebo TOML)"))
+	force := fs.Bool("force", false, i18n.T("přepsat existující konfiguraci"))
+	fs.Parse(args)
+
+	if dryrun.Enabled() {
+		return exitcode.With(exitcode.Usage, errors.New(i18n.T("průvodce nastavením nelze spustit se zkušebním během (--dry-run)")))
+	}
+	if _, err := os.Stat(*outputPath); err == nil && !*force {
+		return i18n.Errorf("konfigurace '%s' již existuje, pro přepsání použijte --force", *outputPath)
+	}
+
+	// S výpisem --json patří standardní výstup výsledku, otázky jdou na chybový výstup.
+	var out io.Writer = os.Stdout
+	if output.Enabled() {
+		out = os.Stderr
+	}
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: out}
+	fmt.Fprintln(p.out, i18n.T("Nastavení nového projektu hugo72. Výchozí hodnota je v hranatých závorkách."))
+
+	inputFile := p.ask("Excel soubor se seznamem účastníků", "ucastnici.xlsx")
+	siteDir := p.ask("Adresář Hugo webu", "phase2")
+	protocol := p.choose("Protokol nasazení", []string{"ftp", "ftps", "ftps-implicit"}, "ftps")
+	ftpHost := p.required("Adresa FTP serveru (např. ftp.example.com)")
+	remoteDir := p.ask("Cílový adresář na serveru", "/www")
+	ftpUser := p.required("Uživatelské jméno FTP")
+	credentials := p.choose("Uložení přihlašovacích údajů (env = soubor .env, age = zašifrovat, config = přímo v konfiguraci)",
+		[]string{"env", "age", "config"}, "env")
+	ftpPassword := p.secret("Heslo FTP")
+	filesToUpload := splitList(p.ask("Soubory k nahrání oddělené čárkou (prázdné = jen artefakt z build --artifact)", ""))
+	if p.err != nil {
+		return i18n.Errorf("chyba při čtení odpovědí: %w", p.err)
+	}
+
+	target := map[string]any{
+		"ftpHost":   ftpHost,
+		"remoteDir": remoteDir,
+	}
+	switch protocol {
+	case "ftps":
+		target["tls"] = "explicit"
+	case "ftps-implicit":
+		target["tls"] = "implicit"
+	}
+
+	var envLines []string
+	switch credentials {
+	case "env":
+		target["ftpUser"] = "${FTP_USER}"
+		target["ftpPassword"] = "${FTP_PASSWORD}"
+		envLines = append(envLines, "FTP_USER="+quoteEnv(ftpUser), "FTP_PASSWORD="+quoteEnv(ftpPassword))
+	case "age":
+		public, err := writeKey(initKeyFile)
+		if err != nil {
+			return err
+		}
+		encrypted, err := config.EncryptSecret(ftpPassword, []string{public})
+		if err != nil {
+			return err
+		}
+		target["ftpUser"] = ftpUser
+		target["ftpPassword"] = encrypted
+		envLines = append(envLines, "HUGO72_AGE_KEY_FILE="+initKeyFile)
+	case "config":
+		target["ftpUser"] = ftpUser
+		target["ftpPassword"] = ftpPassword
+	}
+
+	phase3 := map[string]any{"files_to_upload": filesToUpload}
+	for key, value := range target {
+		phase3[key] = value
+	}
+	values := map[string]any{
+		"phase1": map[string]any{
+			"inputFile":  inputFile,
+			"outputFile": filepath.ToSlash(filepath.Join(siteDir, "data", "data.json")),
+		},
+		"phase2": map[string]any{"siteDir": siteDir},
+		"phase3": phase3,
+	}
+
+	// Adresářová struktura, kterou konfigurace předpokládá.
+	for _, dir := range []string{filepath.Join(siteDir, "data"), filepath.Dir(inputFile), ".hugo72"} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return i18n.Errorf("chyba při vytváření adresáře '%s': %w", dir, err)
+		}
+	}
+
+	data, err := encodeConfig(*outputPath, values)
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci konfigurace: %w", err)
+	}
+	if err := os.WriteFile(*outputPath, data, 0o600); err != nil {
+		return i18n.Errorf("chyba při zápisu konfigurace '%s': %w", *outputPath, err)
+	}
+	fmt.Fprintf(p.out, i18n.T("\nKonfigurace zapsána do '%s'.\n"), *outputPath)
+
+	if len(envLines) > 0 {
+		if err := appendEnvFile(config.DefaultEnvFile, envLines); err != nil {
+			return err
+		}
+		fmt.Fprintf(p.out, i18n.T("Proměnné prostředí doplněny do '%s' (soubor nepatří do gitu).\n"), config.DefaultEnvFile)
+		if err := config.LoadEnvFile(config.DefaultEnvFile, true); err != nil {
+			return err
+		}
+	}
+	if credentials == "age" {
+		fmt.Fprintf(p.out, i18n.T("Klíč k dešifrování hesla je v '%s', uložte si jeho zálohu.\n"), initKeyFile)
+	}
+
+	// Kontrola vytvořené konfigurace; typicky ještě chybí vstupní Excel soubor.
+	if _, err := config.Load(*outputPath, ""); err != nil {
+		fmt.Fprintf(p.out, i18n.T("\nUpozornění: %v\n"), err)
+	}
+	fmt.Fprintln(p.out, i18n.T("\nDalší kroky: hugo72 run, nebo jednotlivě convert, build a deploy."))
+	result := map[string]any{"config": *outputPath, "credentials": credentials}
+	if len(envLines) > 0 {
+		result["envFile"] = config.DefaultEnvFile
+	}
+	if credentials == "age" {
+		result["keyFile"] = initKeyFile
+	}
+	output.Result(result)
+	return nil
+}
+
+// prompter klade otázky na standardním vstupu, přeložené do zvoleného jazyka.
+// První chyba čtení se zapamatuje a další otázky už vracejí výchozí hodnoty,
+// takže se kontroluje jen jednou na konci.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	err error
+}
+
+// ask položí otázku a vrátí odpověď, nebo výchozí hodnotu, pokud je odpověď prázdná.
+func (p *prompter) ask(question, def string) string {
+	question = i18n.T(question)
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	answer := p.readLine()
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+// required opakuje otázku, dokud odpověď není neprázdná.
+func (p *prompter) required(question string) string {
+	for p.err == nil {
+		if answer := p.ask(question, ""); answer != "" {
+			return answer
+		}
+		fmt.Fprintln(p.out, i18n.T("Hodnota je povinná."))
+	}
+	return ""
+}
+
+// choose opakuje otázku, dokud odpověď není jednou z nabízených možností.
+func (p *prompter) choose(question string, options []string, def string) string {
+	question = fmt.Sprintf("%s (%s)", i18n.T(question), strings.Join(options, ", "))
+	for p.err == nil {
+		answer := p.ask(question, def)
+		if slices.Contains(options, answer) {
+			return answer
+		}
+		fmt.Fprintf(p.out, i18n.T("Neznámá možnost '%s'.\n"), answer)
+	}
+	return def
+}
+
+// secret přečte heslo. Na terminálu se zadávané znaky nezobrazují.
+func (p *prompter) secret(question string) string {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return p.ask(question, "")
+	}
+	fmt.Fprintf(p.out, "%s: ", i18n.T(question))
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(p.out)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	return string(password)
+}
+
+// readLine přečte jeden řádek odpovědi bez okolních mezer.
+func (p *prompter) readLine() string {
+	if p.err != nil {
+		return ""
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		if errors.Is(err, io.EOF) {
+			err = errors.New(i18n.T("vstup skončil před zodpovězením všech otázek"))
+		}
+		p.err = err
+	}
+	return strings.TrimSpace(line)
+}
+
+// splitList rozdělí seznam oddělený čárkami a vynechá prázdné položky.
+func splitList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// encodeConfig serializuje konfiguraci ve formátu podle přípony souboru.
+func encodeConfig(filePath string, values map[string]any) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		return yaml.Marshal(values)
+	case ".toml":
+		var b bytes.Buffer
+		err := toml.NewEncoder(&b).Encode(values)
+		return b.Bytes(), err
+	default:
+		data, err := json.MarshalIndent(values, "", "  ")
+		return append(data, '\n'), err
+	}
+}
+
+// writeKey vytvoří nový klíč age, uloží ho do souboru čitelného jen vlastníkem
+// a vrátí veřejný klíč, pro který se hodnoty šifrují.
+func writeKey(path string) (string, error) {
+	if _, err := os.Stat(path); err == nil {
+		return "", i18n.Errorf("klíč '%s' již existuje, průvodce ho nepřepíše", path)
+	}
+	secret, public, err := config.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", i18n.Errorf("chyba při vytváření adresáře pro '%s': %w", path, err)
+	}
+	data := fmt.Sprintf("# veřejný klíč: %s\n%s\n", public, secret)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		return "", i18n.Errorf("chyba při zápisu klíče '%s': %w", path, err)
+	}
+	return public, nil
+}
+
+// appendEnvFile doplní proměnné na konec souboru .env, případně soubor vytvoří.
+func appendEnvFile(path string, lines []string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return i18n.Errorf("chyba při otevírání souboru '%s': %w", path, err)
+	}
+	if _, err := fmt.Fprintf(file, "%s\n", strings.Join(lines, "\n")); err != nil {
+		file.Close()
+		return i18n.Errorf("chyba při zápisu souboru '%s': %w", path, err)
+	}
+	return file.Close()
+}
+
+// quoteEnv uzavře hodnotu do uvozovek, pokud obsahuje znaky, které by se
+// v souboru .env jinak přečetly jinak.
+func quoteEnv(value string) string {
+	if strings.ContainsAny(value, " #\"'\\$") {
+		return "'" + value + "'"
+	}
+	return value
+}
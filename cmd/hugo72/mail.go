@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"hugo72/internal/defaults"
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/mailer"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// Výchozí hodnoty rozesílání bez nastavení v sekci mailer.
+const (
+	defaultSentLog    = ".hugo72/sent.jsonl"
+	defaultBatchSize  = 20
+	defaultBatchPause = time.Minute
+	defaultInterval   = 2 * time.Second
+)
+
+// mailData jsou data šablony e-mailu pro jednoho účastníka.
+type mailData struct {
+	Jmeno  string
+	Email  string
+	Prijde string // "Ano", "Ne" nebo prázdné, pokud účastník neodpověděl
+	Link   string // Osobní odkaz na předvyplněný formulář přihlášky (s mailer.rsvpUrl)
+	Nadpis string
+	Zprava string
+}
+
+// sentEntry je záznam o odeslaném e-mailu. Záznamy se jen připisují.
+type sentEntry struct {
+	Campaign string    `json:"campaign"`
+	Email    string    `json:"email"`
+	SentAt   time.Time `json:"sentAt"`
+}
+
+// runMail rozešle účastníkům e-mail podle šablony:
+//
+//	mail [--template pozvanka.txt] [--campaign pozvanka] [--to all] [--limit 0]
+//
+// Bez --template se použije výchozí pozvánka vložená v programu (vypíše ji
+// export-defaults). Šablona je text/template s poli typu mailData, např. {{.Jmeno}} nebo {{.Link}};
+// na prvním řádku je předmět ve tvaru "Subject: ..." a po prázdném řádku text.
+// Příjemci jsou účastníci z výstupu převodu, --to je omezí na ty, kteří přijdou
+// (attending), nepřijdou (declined) nebo neodpověděli (unanswered). E-maily se
+// posílají po dávkách mailer.batchSize s přestávkou mailer.batchPause a nejvýš
+// jeden za mailer.interval. Každý odeslaný e-mail se zapíše do mailer.sentLog,
+// takže opakované spuštění téže kampaně pošle e-mail jen těm, kdo ho ještě nedostali.
+func runMail(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("mail", flag.ExitOnError)
+	templateFile := fs.String("template", "", i18n.T("soubor se šablonou e-mailu (výchozí pozvánka vložená v programu)"))
+	campaign := fs.String("campaign", "", i18n.T("název kampaně pro záznam odeslaných e-mailů (výchozí název šablony bez přípony)"))
+	to := fs.String("to", "all", i18n.T("příjemci: all, attending, declined nebo unanswered"))
+	limit := fs.Int("limit", 0, i18n.T("nejvyšší počet odeslaných e-mailů (0 = bez omezení)"))
+	fs.Parse(args)
+
+	if cfg.Mailer == nil {
+		return exitcode.With(exitcode.Config, errors.New(i18n.T("konfigurace neobsahuje sekci mailer")))
+	}
+	if cfg.Phase1 == nil {
+		return exitcode.With(exitcode.Config, errors.New(i18n.T("konfigurace neobsahuje sekci phase1")))
+	}
+	templateName := *templateFile
+	if templateName == "" {
+		templateName = defaults.InvitationName
+	}
+	if *campaign == "" {
+		*campaign = strings.TrimSuffix(filepath.Base(templateName), filepath.Ext(templateName))
+	}
+	mc := cfg.Mailer
+	logger := logging.Phase("mail")
+
+	content := defaults.Invitation
+	if *templateFile != "" {
+		var err error
+		if content, err = os.ReadFile(*templateFile); err != nil {
+			return exitcode.With(exitcode.Data, i18n.Errorf("chyba při čtení šablony e-mailu: %w", err))
+		}
+	}
+	subjectTmpl, bodyTmpl, err := parseMailTemplate(templateName, content)
+	if err != nil {
+		return exitcode.With(exitcode.Data, err)
+	}
+	converted, ok := readConverted(cfg.Phase1.OutputFile)
+	if !ok {
+		return exitcode.With(exitcode.Data, i18n.Errorf("výstup převodu '%s' nelze načíst, spusťte nejdřív convert", cfg.Phase1.OutputFile))
+	}
+	recipients, err := selectRecipients(converted.Users, *to)
+	if err != nil {
+		return exitcode.With(exitcode.Usage, err)
+	}
+
+	sentLog := mc.SentLog
+	if sentLog == "" {
+		sentLog = defaultSentLog
+	}
+	sent, err := readSentLog(sentLog)
+	if err != nil {
+		return err
+	}
+	var pending []convert.User
+	for _, user := range recipients {
+		if !sent[sentKey(*campaign, user.Email)] {
+			pending = append(pending, user)
+		}
+	}
+	alreadySent := len(recipients) - len(pending)
+	if *limit > 0 && len(pending) > *limit {
+		pending = pending[:*limit]
+	}
+	logger.Info("Rozesílám e-maily.", "campaign", *campaign, "recipients", len(recipients), "alreadySent", alreadySent, "pending", len(pending))
+
+	batchSize := mc.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultBatchSize
+	}
+	// Hodnoty jsou ověřené při načtení konfigurace.
+	batchPause, interval := defaultBatchPause, defaultInterval
+	if mc.BatchPause != "" {
+		batchPause, _ = time.ParseDuration(mc.BatchPause)
+	}
+	if mc.Interval != "" {
+		interval, _ = time.ParseDuration(mc.Interval)
+	}
+	if mc.RSVPURL != "" && mc.TokenSecret == "" {
+		logger.Warn("Odkazy na formulář nejsou podepsané (chybí mailer.tokenSecret), účastník ze seznamu jimi odpověď nezmění.")
+	}
+	server := mailer.Server{Host: mc.Host, Port: mc.Port, Username: mc.Username, Password: mc.Password}
+
+	var delivered, failed int
+	for i, user := range pending {
+		data := mailData{Jmeno: user.Jmeno, Email: user.Email, Prijde: string(user.Prijde), Nadpis: converted.Info.Nadpis, Zprava: converted.Info.Zprava}
+		if mc.RSVPURL != "" {
+			data.Link = rsvpLink(mc, user)
+		}
+		subject, body, err := renderMail(subjectTmpl, bodyTmpl, data)
+		if err != nil {
+			return exitcode.With(exitcode.Data, err)
+		}
+		if dryrun.Enabled() {
+			logger.Info("Zkušební běh, e-mail se neodešle.", "email", user.Email, "subject", subject)
+			continue
+		}
+
+		if i > 0 {
+			pause := interval
+			if i%batchSize == 0 {
+				pause = batchPause
+				logger.Info("Dávka odeslána, čekám před další.", "sent", i, "pause", pause.String())
+			}
+			select {
+			case <-ctx.Done():
+				return i18n.Errorf("rozesílání přerušeno po %d e-mailech: %w", delivered, ctx.Err())
+			case <-time.After(pause):
+			}
+		}
+
+		err = mailer.Send(server, mailer.Message{From: mc.From, To: []string{user.Email}, Subject: subject, Body: body})
+		if err != nil {
+			failed++
+			logger.Error("E-mail se nepodařilo odeslat.", "email", user.Email, "error", err)
+			continue
+		}
+		delivered++
+		logger.Debug("E-mail odeslán.", "email", user.Email)
+		if err := appendSentLog(sentLog, sentEntry{Campaign: *campaign, Email: user.Email, SentAt: time.Now()}); err != nil {
+			return err
+		}
+	}
+
+	result := map[string]any{
+		"campaign":    *campaign,
+		"recipients":  len(recipients),
+		"alreadySent": alreadySent,
+		"sent":        delivered,
+		"failed":      failed,
+	}
+	if dryrun.Enabled() {
+		result["pending"], result["dryRun"] = len(pending), true
+	}
+	output.Result(result)
+	if failed > 0 {
+		return i18n.Errorf("nepodařilo se odeslat %d z %d e-mailů", failed, len(pending))
+	}
+	logger.Info("Rozesílání dokončeno.", "sent", delivered, "alreadySent", alreadySent)
+	return nil
+}
+
+// parseMailTemplate rozloží šablonu e-mailu: první řádek "Subject: ..." je
+// šablona předmětu, zbytek po prázdném řádku šablona textu.
+func parseMailTemplate(filePath string, content []byte) (subject, body *template.Template, err error) {
+	header, text, _ := strings.Cut(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	subjectText, ok := strings.CutPrefix(header, "Subject:")
+	if !ok {
+		return nil, nil, i18n.Errorf("šablona e-mailu '%s' musí začínat řádkem \"Subject: ...\"", filePath)
+	}
+	if subject, err = template.New("subject").Parse(strings.TrimSpace(subjectText)); err != nil {
+		return nil, nil, i18n.Errorf("chyba v předmětu šablony '%s': %w", filePath, err)
+	}
+	if body, err = template.New(filepath.Base(filePath)).Parse(strings.TrimLeft(text, "\n")); err != nil {
+		return nil, nil, i18n.Errorf("chyba v šabloně e-mailu: %w", err)
+	}
+	return subject, body, nil
+}
+
+// renderMail vykreslí předmět a text e-mailu pro jednoho účastníka.
+func renderMail(subjectTmpl, bodyTmpl *template.Template, data mailData) (string, string, error) {
+	var subject, body bytes.Buffer
+	if err := subjectTmpl.Execute(&subject, data); err != nil {
+		return "", "", i18n.Errorf("chyba při vykreslení předmětu pro '%s': %w", data.Email, err)
+	}
+	if err := bodyTmpl.Execute(&body, data); err != nil {
+		return "", "", i18n.Errorf("chyba při vykreslení e-mailu pro '%s': %w", data.Email, err)
+	}
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
+
+// selectRecipients vybere účastníky podle --to. Účastníci bez e-mailu se
+// vynechají, každá adresa se použije jen jednou.
+func selectRecipients(users []convert.User, to string) ([]convert.User, error) {
+	match := map[string]func(convert.Prijde) bool{
+		"all":        func(convert.Prijde) bool { return true },
+		"attending":  func(p convert.Prijde) bool { return p == convert.Ano },
+		"declined":   func(p convert.Prijde) bool { return p == convert.Ne },
+		"unanswered": func(p convert.Prijde) bool { return p != convert.Ano && p != convert.Ne },
+	}[to]
+	if match == nil {
+		return nil, i18n.Errorf("neznámá hodnota --to '%s', povoleno je all, attending, declined nebo unanswered", to)
+	}
+
+	seen := map[string]bool{}
+	var recipients []convert.User
+	for _, user := range users {
+		key := strings.ToLower(user.Email)
+		if user.Email == "" || seen[key] || !match(user.Prijde) {
+			continue
+		}
+		seen[key] = true
+		recipients = append(recipients, user)
+	}
+	return recipients, nil
+}
+
+// rsvpLink vrací osobní odkaz na formulář přihlášky předvyplněný jménem
+// a e-mailem účastníka. S mailer.tokenSecret je odkaz podepsaný.
+func rsvpLink(mc *config.Mailer, user convert.User) string {
+	query := url.Values{"jmeno": {user.Jmeno}, "email": {user.Email}}
+	if mc.TokenSecret != "" {
+		query.Set("token", mailer.Token(mc.TokenSecret, user.Email))
+	}
+	separator := "?"
+	if strings.Contains(mc.RSVPURL, "?") {
+		separator = "&"
+	}
+	return mc.RSVPURL + separator + query.Encode()
+}
+
+// sentKey je klíč záznamu o odeslání: kampaň a adresa bez ohledu na velikost písmen.
+func sentKey(campaign, email string) string {
+	return campaign + "\x00" + strings.ToLower(email)
+}
+
+// readSentLog načte záznam odeslaných e-mailů jako množinu klíčů sentKey.
+// Chybějící soubor znamená, že se zatím nic neodeslalo.
+func readSentLog(filePath string) (map[string]bool, error) {
+	sent := map[string]bool{}
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return sent, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba při otevírání záznamu odeslaných e-mailů '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry sentEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, i18n.Errorf("chyba v záznamu odeslaných e-mailů '%s' na řádku %d: %w", filePath, line, err)
+		}
+		sent[sentKey(entry.Campaign, entry.Email)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.Errorf("chyba při čtení záznamu odeslaných e-mailů '%s': %w", filePath, err)
+	}
+	return sent, nil
+}
+
+// appendSentLog připíše záznam o odeslaném e-mailu.
+func appendSentLog(filePath string, entry sentEntry) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře '%s': %w", filepath.Dir(filePath), err)
+	}
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return i18n.Errorf("chyba při otevírání záznamu odeslaných e-mailů '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return i18n.Errorf("chyba při zápisu záznamu odeslaných e-mailů '%s': %w", filePath, err)
+	}
+	return nil
+}
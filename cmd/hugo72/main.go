@@ -0,0 +1,312 @@
+// Program hugo72 spojuje všechny fáze zpracování do jednoho nástroje.
+//
+// Použití:
+//
+//	hugo72 [--config config.json] [--env profil] [--env-file .env] <příkaz> [přepínače]
+//
+// Bez přepínače --config se použije config.json, config.yaml, config.yml
+// nebo config.toml z adresáře projektu (--dir, jinak pracovního adresáře),
+// podle toho, který existuje.
+// Přepínač --env vybere profil konfigurace (dev, staging, prod...), který
+// přepíše jen odlišné hodnoty základní konfigurace. Před načtením konfigurace
+// se nastaví proměnné prostředí ze souboru .env (nebo ze souboru --env-file),
+// takže přihlašovací údaje lze v konfiguraci zapsat jako ${FTP_PASSWORD}.
+//
+// Všechny fáze zapisují do společného logu na standardní chybový výstup.
+// Přepínač --log-format json přepne log na jeden objekt JSON na řádek pro sběr
+// logů v CI, --log-level určuje nejnižší vypisovanou úroveň. S přepínačem
+// --log-file se log zapisuje navíc do souboru, například pro noční běhy bez dozoru;
+// soubor se každý den a po dosažení --log-max-size odloží pod názvem s časem
+// a staré soubory se mažou podle --log-max-files a --log-max-days.
+// Přepínač --verbose vypisuje podrobný průběh, --quiet jen chyby.
+//
+// S přepínačem --json vypíše každý příkaz po skončení na standardní výstup
+// jediný objekt JSON s výsledkem, např. {"command": "deploy", "ok": true, "result": {...}},
+// aby ho mohly zpracovat skripty. Log zůstává na standardním chybovém výstupu.
+//
+// Přepínač --profile soubor.json zapíše po běhu profil: kolik času zabral
+// každý krok (čtení Excel souboru, zápis JSON, háčky, Hugo, připojení, nahrávání
+// souborů podle velikosti...) a jaký podíl běhu to je; profil se také vypíše.
+// Přepínač --pprof adresář navíc zapíše profil procesoru a paměti celého
+// procesu pro go tool pprof.
+//
+// Přepínač --dry-run spustí zkušební běh: convert jen vypíše, co by zapsal,
+// build ověří vstupy a vypíše příkaz hugo a deploy vypíše plán nasazení.
+// Nic se nezapíše na disk ani na server.
+//
+// Signál SIGINT nebo SIGTERM (Ctrl-C, časový limit CI) příkaz řádně ukončí:
+// zastaví se Hugo, přeruší se přenosy na server, zapíše se report a program
+// skončí s kódem 8. Druhý signál ukončí program okamžitě.
+//
+// Příkazy watch a serve lze provozovat jako službu systemd nebo Windows (viz
+// příkaz service): signál SIGHUP (systemctl reload) znovu načte konfiguraci
+// bez restartu a pod systemd jde log do žurnálu s úrovní záznamu, ve Windows
+// do protokolu událostí.
+//
+// Po běhu příkazů run, watch a serve se podle sekce notify v konfiguraci
+// odešlou oznámení (e-mail, Slack, Telegram, Discord, webhook) o selhání,
+// úspěšném nasazení nebo nových přihláškách.
+//
+// Nápověda, log a chybová hlášení jsou česky nebo anglicky podle klíče
+// language v konfiguraci, bez něj podle proměnné prostředí LANG: české
+// prostředí (cs_CZ.UTF-8) vybere češtinu, jiný jazyk nebo C a POSIX (běžné
+// v CI) angličtinu. Bez nastavení LANG zůstává čeština.
+//
+// Návratový kód rozlišuje druh chyby (chyba konfigurace, dat, sestavení,
+// nasazení, není co dělat), přehled kódů je v dokumentaci balíčku exitcode.
+//
+// Příkazy:
+//
+//	convert         převede Excel soubor na JSON (fáze 1)
+//	build           sestaví web Hugem (fáze 2)
+//	deploy          nasadí web na FTP nebo SFTP server (fáze 3)
+//	promote         znovu nasadí artefakt ověřený na jiném cíli
+//	deploys         vypíše historii nasazení (deploys list)
+//	run             spustí převod, sestavení a nasazení za sebou
+//	watch           po každé změně Excel souboru spustí převod, sestavení a nasazení
+//	serve           HTTP server, jehož požadavky spouští převod, sestavení a nasazení
+//	history         vypíše historii běhů (history list, history show <číslo>)
+//	backups         vypíše zálohy před běhy nebo jednu obnoví (backups list, backups restore <id>)
+//	mail            rozešle účastníkům pozvánky nebo zprávy e-mailem
+//	checkin         eviduje příchody účastníků v den akce (checkin scan, status, export)
+//	secrets         vytvoří klíč nebo zašifruje hodnotu do konfigurace
+//	init            průvodce nastavením nového projektu
+//	config          ověří konfiguraci (config validate) nebo vypíše její schéma
+//	doctor          ověří vše, co běh potřebuje, včetně přihlášení k serverům
+//	version         vypíše verzi programu a údaje o sestavení
+//	self-update     nahradí program novější verzí z vydání na GitHubu
+//	export-defaults zapíše na disk výchozí soubory vložené v programu (šablony, schéma)
+//	service         nainstaluje watch nebo serve jako službu systemd nebo Windows
+//
+// Přepínače jednotlivých příkazů vypíše "hugo72 <příkaz> --help".
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/output"
+	"hugo72/internal/service"
+	"hugo72/pkg/config"
+)
+
+// command je jeden příkaz nástroje. Dostává načtenou konfiguraci a argumenty za názvem příkazu.
+type command struct {
+	name     string
+	usage    string
+	run      func(ctx context.Context, config *config.Config, args []string) error
+	noConfig bool // Příkaz konfiguraci nepotřebuje a dostane nil
+}
+
+// commands je seznam příkazů v pořadí, ve kterém se vypisují v nápovědě.
+var commands = []command{
+	{name: "convert", usage: "převede Excel soubor na JSON (fáze 1)", run: withPhaseRun("convert", withHooks("convert", runConvert))},
+	{name: "build", usage: "sestaví web Hugem (fáze 2)", run: withPhaseRun("build", withHooks("build", runBuild))},
+	{name: "deploy", usage: "nasadí web na FTP nebo SFTP server (fáze 3)", run: withPhaseRun("deploy", withHooks("deploy", runDeploy))},
+	{name: "promote", usage: "znovu nasadí artefakt ověřený na jiném cíli", run: withPhaseRun("promote", runPromote)},
+	{name: "deploys", usage: "vypíše historii nasazení (deploys list)", run: runDeploys},
+	{name: "run", usage: "spustí převod, sestavení a nasazení za sebou", run: runPipeline},
+	{name: "watch", usage: "po každé změně Excel souboru spustí převod, sestavení a nasazení", run: runWatch},
+	{name: "serve", usage: "HTTP server, jehož požadavky spouští převod, sestavení a nasazení", run: runServe},
+	{name: "history", usage: "vypíše historii běhů (history list, history show <číslo>)", run: runHistory, noConfig: true},
+	{name: "backups", usage: "vypíše zálohy před běhy nebo jednu obnoví (backups list, backups restore <id>)", run: runBackups},
+	{name: "mail", usage: "rozešle účastníkům pozvánky nebo zprávy e-mailem", run: runMail},
+	{name: "checkin", usage: "eviduje příchody účastníků v den akce (checkin scan, status, export)", run: runCheckin},
+	{name: "secrets", usage: "vytvoří klíč nebo zašifruje hodnotu do konfigurace", run: runSecrets, noConfig: true},
+	{name: "init", usage: "průvodce nastavením nového projektu", run: runInit, noConfig: true},
+	{name: "config", usage: "ověří konfiguraci (config validate) nebo vypíše její schéma", run: runConfig, noConfig: true},
+	{name: "doctor", usage: "ověří vše, co běh potřebuje, včetně přihlášení k serverům", run: runDoctor, noConfig: true},
+	{name: "version", usage: "vypíše verzi programu a údaje o sestavení", run: runVersion, noConfig: true},
+	{name: "self-update", usage: "nahradí program novější verzí z vydání na GitHubu", run: runSelfUpdate, noConfig: true},
+	{name: "export-defaults", usage: "zapíše na disk výchozí soubory vložené v programu (šablony, schéma)", run: runExportDefaults, noConfig: true},
+	{name: "service", usage: "nainstaluje watch nebo serve jako službu systemd nebo Windows", run: runService, noConfig: true},
+}
+
+// Globální přepínače. Příkazy, které si konfiguraci načítají samy, z nich čtou cestu a profil.
+var (
+	configPath  = flag.String("config", "", "cesta ke konfiguračnímu souboru (JSON, YAML nebo TOML); výchozí je config.json, config.yaml, config.yml nebo config.toml v adresáři projektu")
+	env         = flag.String("env", "", "profil konfigurace, např. dev, staging nebo prod (výchozí z HUGO72_ENV)")
+	envFile     = flag.String("env-file", config.DefaultEnvFile, "soubor s proměnnými prostředí, které se nastaví před načtením konfigurace")
+	workDir     = flag.String("dir", "", "adresář projektu, do kterého program před spuštěním příkazu přejde (např. pro službu)")
+	logFormat   = flag.String("log-format", logging.FormatText, "formát logu: text nebo json (pro sběr logů v CI)")
+	logLevel    = flag.String("log-level", "info", "nejnižší vypisovaná úroveň logu: debug, info, warn nebo error")
+	logFile     = flag.String("log-file", "", "soubor, do kterého se log zapisuje navíc (denně a po dosažení velikosti se odloží)")
+	logMaxSize  = flag.Int("log-max-size", 10, "velikost souboru logu v MB, po které se odloží a začne nový")
+	logMaxKeep  = flag.Int("log-max-files", 14, "počet ponechaných odložených souborů logu")
+	logMaxDays  = flag.Int("log-max-days", 30, "počet dní, po kterých se odložené soubory logu smažou")
+	verbose     = flag.Bool("verbose", false, "vypisovat podrobný průběh (úroveň logu debug)")
+	quiet       = flag.Bool("quiet", false, "vypisovat jen chyby (soubor logu dostává dál vše podle --log-level)")
+	jsonOutput  = flag.Bool("json", false, "po skončení vypsat výsledek příkazu jako JSON na standardní výstup")
+	dryRun      = flag.Bool("dry-run", false, "zkušební běh: fáze jen vypíšou, co by udělaly, nic nezapíšou na disk ani na server")
+	profileFile = flag.String("profile", "", "soubor, do kterého se po běhu zapíše profil doby jednotlivých kroků (JSON)")
+	pprofDir    = flag.String("pprof", "", "adresář, do kterého se zapíšou profily procesoru a paměti pro go tool pprof")
+)
+
+func main() {
+	// Jazyk hlášení podle prostředí; klíč language v konfiguraci ho může změnit.
+	i18n.Set(i18n.Detect())
+	flag.Usage = usage
+	flag.Parse()
+
+	if *verbose && *quiet {
+		fmt.Fprintln(os.Stderr, i18n.T("Chyba: přepínače --verbose a --quiet nelze použít současně"))
+		os.Exit(int(exitcode.Usage))
+	}
+	level, fileLevel := *logLevel, *logLevel
+	switch {
+	case *verbose:
+		level, fileLevel = "debug", "debug"
+	case *quiet:
+		level = "error"
+	}
+	if *jsonOutput {
+		output.Enable()
+	}
+	if *dryRun {
+		dryrun.Enable()
+	}
+	// Relativní cesty (konfigurace, soubor logu, .hugo72) se berou vůči adresáři projektu.
+	path, err := enterProject(*workDir, *configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Chyba: %v\n"), err)
+		os.Exit(int(exitcode.Usage))
+	}
+	*configPath = path
+
+	logOptions := logging.Options{
+		Format:    *logFormat,
+		Level:     level,
+		File:      *logFile,
+		FileLevel: fileLevel,
+		MaxSize:   int64(*logMaxSize) << 20,
+		MaxFiles:  *logMaxKeep,
+		MaxAge:    time.Duration(*logMaxDays) * 24 * time.Hour,
+		Journal:   service.Journal(),
+	}
+	// Služba Windows nemá kam vypisovat, log jde do protokolu událostí.
+	if service.IsWindowsService() {
+		if events, err := service.OpenEventLog(); err == nil {
+			logOptions.EventLog = events
+		}
+	}
+	if err := logging.Setup(os.Stderr, logOptions); err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Chyba: %v\n"), err)
+		os.Exit(int(exitcode.Usage))
+	}
+
+	// Proměnné ze souboru .env musí být nastavené dřív, než se v konfiguraci dosadí ${PROMENNA}.
+	// Výchozí soubor je nepovinný, výslovně zadaný musí existovat.
+	if err := config.LoadEnvFile(*envFile, isFlagSet("env-file")); err != nil {
+		fatal(exitcode.With(exitcode.Config, err))
+	}
+	if *env == "" {
+		*env = os.Getenv("HUGO72_ENV")
+	}
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(int(exitcode.Usage))
+	}
+
+	stopPprof := func() {}
+	if *pprofDir != "" {
+		stop, err := startPprof(*pprofDir)
+		if err != nil {
+			fatal(exitcode.With(exitcode.Usage, err))
+		}
+		stopPprof = stop
+	}
+
+	ctx := cancelOnSignal()
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			// Konfigurace se načte a ověří jednou pro všechny fáze.
+			var cfg *config.Config
+			var err error
+			if !cmd.noConfig {
+				if cfg, err = config.Load(*configPath, *env); err == nil {
+					if cfg.Language != "" {
+						i18n.Set(cfg.Language)
+					}
+					slog.Debug("Konfigurace načtena.", "file", *configPath, "profile", *env)
+				} else {
+					err = exitcode.With(exitcode.Config, err)
+				}
+			}
+			if err == nil {
+				// Ve Windows obslouží správce služeb; jinde se příkaz jen spustí.
+				err = service.Run(ctx, func(ctx context.Context) error { return cmd.run(ctx, cfg, args) }, requestServiceReload)
+			}
+			stopPprof()
+			if err != nil && ctx.Err() != nil {
+				err = exitcode.With(exitcode.Cancelled, err)
+			}
+			if err := output.Write(os.Stdout, name, err); err != nil {
+				slog.Error("Výsledek příkazu nelze vypsat.", "error", err)
+			}
+			if err != nil {
+				fatal(err)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Neznámý příkaz: %s\n\n"), name)
+	usage()
+	os.Exit(int(exitcode.Usage))
+}
+
+// fatal zapíše chybu do logu a ukončí program s návratovým kódem podle druhu chyby.
+func fatal(err error) {
+	code := exitcode.From(err)
+	slog.Error("Příkaz skončil chybou.", "error", err, "exitCode", int(code))
+	os.Exit(int(code))
+}
+
+// isFlagSet vrací true, pokud byl globální přepínač zadán na příkazové řádce.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// enterProject přejde do adresáře projektu dir (prázdný znamená pracovní
+// adresář) a vrátí cestu ke konfiguraci. Bez výslovně zadané cesty se výchozí
+// konfigurační soubor hledá až v adresáři projektu.
+func enterProject(dir, configPath string) (string, error) {
+	if dir != "" {
+		if err := os.Chdir(dir); err != nil {
+			return "", err
+		}
+	}
+	if configPath == "" {
+		configPath = config.DefaultPath()
+	}
+	return configPath, nil
+}
+
+// usage vypíše nápovědu s přehledem příkazů.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, i18n.T("Použití: hugo72 [--config config.json] [--env profil] [--env-file .env] <příkaz> [přepínače]"))
+	fmt.Fprintln(out, i18n.T("\nPříkazy:"))
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-15s %s\n", cmd.name, i18n.T(cmd.usage))
+	}
+	fmt.Fprintln(out, i18n.T("\nPřepínače:"))
+	flag.VisitAll(func(f *flag.Flag) { f.Usage = i18n.T(f.Usage) })
+	flag.PrintDefaults()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnterProjectFindsConfigInDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	// V pracovním adresáři je config.json, v adresáři projektu jen config.yaml.
+	start, project := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(start, "config.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(project, "config.yaml"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		configPath string
+		want       string
+	}{
+		{name: "default", want: "config.yaml"},
+		{name: "explicit", configPath: "jiny.json", want: "jiny.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Chdir(start); err != nil {
+				t.Fatal(err)
+			}
+			got, err := enterProject(project, tt.configPath)
+			if err != nil {
+				t.Fatalf("enterProject: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("konfigurace %q, chci %q", got, tt.want)
+			}
+			if dir, _ := os.Getwd(); !sameDir(t, dir, project) {
+				t.Errorf("pracovní adresář %s, chci %s", dir, project)
+			}
+		})
+	}
+}
+
+// sameDir porovná adresáře po rozvinutí symbolických odkazů (dočasný adresář
+// může vést přes odkaz).
+func sameDir(t *testing.T, a, b string) bool {
+	t.Helper()
+	a, errA := filepath.EvalSymlinks(a)
+	b, errB := filepath.EvalSymlinks(b)
+	if errA != nil || errB != nil {
+		t.Fatal(errA, errB)
+	}
+	return a == b
+}
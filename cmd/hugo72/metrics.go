@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// durationBuckets jsou horní hranice intervalů histogramu doby běhu fáze v sekundách.
+var durationBuckets = []float64{0.5, 1, 2, 5, 10, 30, 60, 120, 300, 600}
+
+// metrics jsou souhrnné počty běhů pro GET /metrics ve formátu Prometheus.
+// Aktualizuje je statusTracker po každém běhu.
+type metrics struct {
+	runs, runFailures int
+	phases            map[string]*phaseMetrics
+	uploadBytes       int64
+	attendees         *int64 // Počet záznamů z posledního převodu; nil, dokud převod neproběhl
+	attending         *int64 // Z toho účastníků, kteří přijdou
+}
+
+// phaseMetrics jsou počty běhů a histogram doby běhu jedné fáze. Přeskočená
+// fáze se počítá zvlášť: neběžela, do doby běhu ani posledního úspěchu se
+// proto nezapočítá.
+type phaseMetrics struct {
+	runs, failures int
+	skipped        int
+	buckets        []int // Počet běhů do hranice durationBuckets[i]
+	sum            float64
+	lastSuccess    float64 // Unixový čas posledního úspěšného dokončení
+}
+
+// phaseCounts jsou údaje, které se pro metriky a oznámení čtou z výsledku fáze.
+// Výsledky fází jsou různé typy, proto se čtou přes JSON.
+type phaseCounts struct {
+	Records   *int64 `json:"records"`
+	Attending *int64 `json:"attending"`
+	Commit    string `json:"commit"`
+	Targets   []struct {
+		Name    string `json:"name"`
+		Summary struct {
+			Uploaded      int   `json:"uploaded"`
+			BytesUploaded int64 `json:"bytesUploaded"`
+		} `json:"summary"`
+	} `json:"targets"`
+}
+
+// countsOf přečte z výsledku fáze údaje phaseCounts. Chybějící údaje zůstanou prázdné.
+func countsOf(result any) phaseCounts {
+	var counts phaseCounts
+	if data, err := json.Marshal(result); err == nil {
+		json.Unmarshal(data, &counts)
+	}
+	return counts
+}
+
+// observe započítá dokončený běh a jeho fáze.
+func (m *metrics) observe(timings []phaseTiming, err error) {
+	if m.phases == nil {
+		m.phases = map[string]*phaseMetrics{}
+	}
+	m.runs++
+	if err != nil {
+		m.runFailures++
+	}
+
+	for _, t := range timings {
+		pm := m.phases[t.name]
+		if pm == nil {
+			pm = &phaseMetrics{buckets: make([]int, len(durationBuckets))}
+			m.phases[t.name] = pm
+		}
+		if t.skipped {
+			pm.skipped++
+			continue
+		}
+		pm.runs++
+		seconds := t.duration.Seconds()
+		pm.sum += seconds
+		for i, le := range durationBuckets {
+			if seconds <= le {
+				pm.buckets[i]++
+			}
+		}
+		if t.err != nil {
+			pm.failures++
+			continue
+		}
+		pm.lastSuccess = float64(t.started.Add(t.duration).UnixMilli()) / 1000
+
+		counts := countsOf(t.result)
+		if t.name == "convert" && counts.Records != nil {
+			m.attendees, m.attending = counts.Records, counts.Attending
+		}
+		for _, target := range counts.Targets {
+			m.uploadBytes += target.Summary.BytesUploaded
+		}
+	}
+}
+
+// write vypíše metriky v textovém formátu Prometheus.
+func (m *metrics) write(w io.Writer) {
+	metric(w, "hugo72_runs_total", "counter", "Počet dokončených běhů pipeline.")
+	fmt.Fprintf(w, "hugo72_runs_total %d\n", m.runs)
+	metric(w, "hugo72_run_failures_total", "counter", "Počet běhů pipeline, které skončily chybou.")
+	fmt.Fprintf(w, "hugo72_run_failures_total %d\n", m.runFailures)
+
+	var names []string
+	for _, phase := range pipeline {
+		if m.phases[phase.name] != nil {
+			names = append(names, phase.name)
+		}
+	}
+	metric(w, "hugo72_phase_runs_total", "counter", "Počet běhů fáze.")
+	for _, name := range names {
+		fmt.Fprintf(w, "hugo72_phase_runs_total{phase=%q} %d\n", name, m.phases[name].runs)
+	}
+	metric(w, "hugo72_phase_failures_total", "counter", "Počet běhů fáze, které skončily chybou.")
+	for _, name := range names {
+		fmt.Fprintf(w, "hugo72_phase_failures_total{phase=%q} %d\n", name, m.phases[name].failures)
+	}
+	metric(w, "hugo72_phase_skipped_total", "counter", "Počet přeskočení fáze, protože se od posledního úspěšného běhu nic nezměnilo.")
+	for _, name := range names {
+		fmt.Fprintf(w, "hugo72_phase_skipped_total{phase=%q} %d\n", name, m.phases[name].skipped)
+	}
+	metric(w, "hugo72_phase_duration_seconds", "histogram", "Doba běhu fáze v sekundách.")
+	for _, name := range names {
+		pm := m.phases[name]
+		for i, le := range durationBuckets {
+			fmt.Fprintf(w, "hugo72_phase_duration_seconds_bucket{phase=%q,le=%q} %d\n", name, formatFloat(le), pm.buckets[i])
+		}
+		fmt.Fprintf(w, "hugo72_phase_duration_seconds_bucket{phase=%q,le=\"+Inf\"} %d\n", name, pm.runs)
+		fmt.Fprintf(w, "hugo72_phase_duration_seconds_sum{phase=%q} %s\n", name, formatFloat(pm.sum))
+		fmt.Fprintf(w, "hugo72_phase_duration_seconds_count{phase=%q} %d\n", name, pm.runs)
+	}
+	metric(w, "hugo72_phase_last_success_timestamp_seconds", "gauge", "Čas posledního úspěšného dokončení fáze (unixový čas).")
+	for _, name := range names {
+		if pm := m.phases[name]; pm.lastSuccess > 0 {
+			fmt.Fprintf(w, "hugo72_phase_last_success_timestamp_seconds{phase=%q} %s\n", name, strconv.FormatFloat(pm.lastSuccess, 'f', 3, 64))
+		}
+	}
+
+	metric(w, "hugo72_upload_bytes_total", "counter", "Počet bajtů nahraných na server.")
+	fmt.Fprintf(w, "hugo72_upload_bytes_total %d\n", m.uploadBytes)
+	if m.attendees != nil {
+		metric(w, "hugo72_attendees", "gauge", "Počet přihlášených podle posledního převodu.")
+		fmt.Fprintf(w, "hugo72_attendees %d\n", *m.attendees)
+	}
+	if m.attending != nil {
+		metric(w, "hugo72_attendees_attending", "gauge", "Počet přihlášených, kteří přijdou, podle posledního převodu.")
+		fmt.Fprintf(w, "hugo72_attendees_attending %d\n", *m.attending)
+	}
+}
+
+// metric vypíše hlavičku metriky s popisem a typem.
+func metric(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// formatFloat zapíše číslo v nejkratším tvaru, jak ho čte Prometheus.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// metricsHandler odpoví na GET /metrics metrikami ze statusTrackeru.
+func metricsHandler(s *statusTracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.metrics.write(w)
+	})
+}
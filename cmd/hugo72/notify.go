@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/logging"
+	"hugo72/internal/notify"
+	"hugo72/internal/runid"
+	"hugo72/pkg/config"
+)
+
+// notifyRun odešle oznámení o dokončeném běhu: o chybě, o úspěšném nasazení
+// a o přihláškách, které přibyly od předchozího převodu (previousRecords, nebo
+// nil, pokud převod ještě neproběhl). Zrušený a zkušební běh se neoznamuje.
+// Chyba odeslání se jen zapíše do logu, výsledek běhu nemění.
+func notifyRun(ctx context.Context, cfg *config.Config, timings []phaseTiming, runErr error, previousRecords *int64) {
+	if cfg.Notify == nil || ctx.Err() != nil || dryrun.Enabled() {
+		return
+	}
+
+	var events []notify.Event
+	if runErr != nil {
+		event := notify.Event{Name: notify.RunFailure, Error: runErr.Error()}
+		if n := len(timings); n > 0 && timings[n-1].err != nil {
+			event.Phase, event.Error = timings[n-1].name, timings[n-1].err.Error()
+		}
+		events = append(events, event)
+	}
+	for _, t := range timings {
+		if t.err != nil || t.skipped {
+			continue
+		}
+		counts := countsOf(t.result)
+		switch t.name {
+		case "convert":
+			if previousRecords != nil && counts.Records != nil && *counts.Records > *previousRecords {
+				events = append(events, notify.Event{
+					Name:             notify.NewRegistrations,
+					Records:          *counts.Records,
+					NewRegistrations: *counts.Records - *previousRecords,
+				})
+			}
+		case "deploy":
+			event := notify.Event{Name: notify.DeploySuccess, Commit: counts.Commit}
+			for _, target := range counts.Targets {
+				event.Targets = append(event.Targets, target.Name)
+				event.FilesUploaded += target.Summary.Uploaded
+			}
+			events = append(events, event)
+		}
+	}
+
+	for _, event := range events {
+		event.RunID = runid.Current()
+		if err := notify.Send(ctx, cfg.Notify, event); err != nil {
+			logging.Phase("notify").Warn("Oznámení se nepodařilo odeslat.", "event", event.Name, "error", err)
+		}
+	}
+}
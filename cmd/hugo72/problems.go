@@ -0,0 +1,65 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/problems"
+)
+
+// finishProblems ukončí sběr problémů běhu (viz problems) a vypíše jejich
+// souhrn. Chyba fáze phase, která běh ukončila (runErr), je v souhrnu poslední.
+// Pokud běh jinak uspěl, ale zaznamenal chyby, vrací je jako chybu s návratovým
+// kódem exitcode.Problems; varování výsledek běhu nemění. Vrací také všechny
+// problémy pro výpis --json.
+func finishProblems(phase string, runErr error) (problems.List, error) {
+	list := problems.Take()
+	if runErr != nil {
+		list = append(list, problems.Problem{Phase: phase, Err: runErr})
+	}
+	if len(list) == 0 {
+		return nil, runErr
+	}
+	printProblems(list)
+
+	if errs := list.Errors(); runErr == nil && len(errs) > 0 {
+		return list, exitcode.With(exitcode.Problems, i18n.Errorf("běh doběhl, ale některé kroky selhaly:\n%w", errs))
+	}
+	return list, runErr
+}
+
+// printProblems vypíše souhrn problémů: v textovém logu jako tabulku (fáze,
+// položka, úroveň, chyba), v logu JSON jako jeden záznam na problém.
+func printProblems(list problems.List) {
+	if *logFormat != logging.FormatText {
+		logger := logging.Phase("run")
+		for _, p := range list {
+			if p.Warning {
+				logger.Warn("Problém běhu.", "step", p.Phase, "item", p.Item, "error", p.Err)
+			} else {
+				logger.Error("Problém běhu.", "step", p.Phase, "item", p.Item, "error", p.Err)
+			}
+		}
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, i18n.T("Souhrn problémů běhu:"))
+	tw := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T("FÁZE\tPOLOŽKA\tÚROVEŇ\tCHYBA"))
+	for _, p := range list {
+		level := i18n.T("chyba")
+		if p.Warning {
+			level = i18n.T("varování")
+		}
+		// Víceřádková chyba (např. z kontroly obsahu) by rozbila tabulku.
+		text := strings.ReplaceAll(p.Err.Error(), "\n", "; ")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Phase, cmp.Or(p.Item, "-"), level, text)
+	}
+	tw.Flush()
+}
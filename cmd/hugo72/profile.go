@@ -0,0 +1,149 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/profile"
+)
+
+// finishProfile ukončí měření kroků běhu id (viz profile). S přepínačem
+// --profile zapíše profil do souboru a vypíše ho; bez něj se profil zahodí.
+// Každý běh démona watch nebo serve soubor přepíše profilem posledního běhu.
+func finishProfile(id string) {
+	p := profile.Take()
+	p.RunID = id
+	if *profileFile == "" {
+		return
+	}
+	printProfile(p)
+	if dryrun.Enabled() {
+		return
+	}
+	if err := writeProfile(*profileFile, p); err != nil {
+		logging.Phase("run").Warn("Profil běhu se nepodařilo zapsat.", "file", *profileFile, "error", err)
+	}
+}
+
+// writeProfile zapíše profil běhu jako JSON.
+func writeProfile(filePath string, p profile.Profile) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(filePath); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filePath, append(data, '\n'), 0o644)
+}
+
+// printProfile vypíše profil: v textovém logu jako tabulku, ve které je u každé
+// fáze nejdřív její celková doba a pod ní kroky od nejpomalejšího, v logu JSON
+// jako jeden záznam na krok.
+func printProfile(p profile.Profile) {
+	steps := slices.Clone(p.Steps)
+	var phases []string
+	for _, s := range steps {
+		if !slices.Contains(phases, s.Phase) {
+			phases = append(phases, s.Phase)
+		}
+	}
+	slices.SortStableFunc(steps, func(a, b profile.Step) int {
+		if c := cmp.Compare(slices.Index(phases, a.Phase), slices.Index(phases, b.Phase)); c != 0 {
+			return c
+		}
+		if (a.Name == "") != (b.Name == "") {
+			if a.Name == "" {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(b.Total, a.Total)
+	})
+
+	if *logFormat != logging.FormatText {
+		logger := logging.Phase("run")
+		for _, s := range steps {
+			logger.Info("Profil kroku.", "step", s.Phase, "item", s.Name, "count", s.Count,
+				"total", roundStep(s.Total).String(), "max", roundStep(s.Max).String(), "bytes", s.Bytes)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, i18n.T("Profil běhu (celkem %s):\n"), p.Duration.Round(time.Millisecond))
+	tw := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T("FÁZE\tKROK\tPOČET\tCELKEM\tPRŮMĚR\tMAX\tPODÍL\tDATA"))
+	for _, s := range steps {
+		name := s.Name
+		if name == "" {
+			name = i18n.T("(celá fáze)")
+		}
+		share := "-"
+		if p.Duration > 0 {
+			share = fmt.Sprintf("%.1f %%", 100*s.Total.Seconds()/p.Duration.Seconds())
+		}
+		data := "-"
+		if s.Bytes > 0 {
+			data = fmt.Sprintf("%d B", s.Bytes)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n", s.Phase, name, s.Count,
+			roundStep(s.Total), roundStep(s.Average()), roundStep(s.Max), share, data)
+	}
+	tw.Flush()
+}
+
+// roundStep zaokrouhlí dobu kroku pro výpis: krátké kroky (zápis jednoho
+// malého souboru) na mikrosekundy, aby se nezobrazily jako 0s.
+func roundStep(d time.Duration) time.Duration {
+	if d < 10*time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+// startPprof spustí profilování procesoru pro go tool pprof do souboru
+// cpu.pprof v adresáři dir. Vrácená funkce profilování ukončí a do téhož
+// adresáře zapíše ještě profil paměti heap.pprof.
+func startPprof(dir string) (func(), error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, i18n.Errorf("chyba při vytváření adresáře '%s': %w", dir, err)
+	}
+	cpu, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, i18n.Errorf("chyba při vytváření profilu procesoru: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, i18n.Errorf("chyba při spuštění profilu procesoru: %w", err)
+	}
+
+	return func() {
+		logger := logging.Phase("run")
+		pprof.StopCPUProfile()
+		cpu.Close()
+
+		heap, err := os.Create(filepath.Join(dir, "heap.pprof"))
+		if err == nil {
+			runtime.GC() // Profil paměti ukáže jen živé objekty.
+			err = pprof.WriteHeapProfile(heap)
+			heap.Close()
+		}
+		if err != nil {
+			logger.Warn("Profil paměti se nepodařilo zapsat.", "error", err)
+		}
+		logger.Info("Profily pprof uloženy.", "dir", dir)
+	}, nil
+}
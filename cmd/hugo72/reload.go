@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/service"
+	"hugo72/pkg/config"
+)
+
+// serviceReloads jsou požadavky na nové načtení konfigurace od správce služeb Windows.
+var serviceReloads = make(chan struct{}, 1)
+
+// requestServiceReload zařadí požadavek správce služeb; čeká-li už jiný, nový se zahodí.
+func requestServiceReload() {
+	select {
+	case serviceReloads <- struct{}{}:
+	default:
+	}
+}
+
+// reloader předává démonu (watch, serve) požadavky na nové načtení konfigurace
+// ze signálu SIGHUP (systemctl reload), z požadavku POST /reload a od správce
+// služeb Windows. Každý požadavek nese kanál, do kterého démon vrátí výsledek.
+type reloader chan chan error
+
+// newReloader vrací reloader, který až do zrušení ctx přijímá signál SIGHUP
+// a požadavky správce služeb Windows. Bez démona SIGHUP program ukončí jako dřív.
+func newReloader(ctx context.Context) reloader {
+	r := make(reloader)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				logging.Phase("service").Info("Přijat signál SIGHUP, znovu načítám konfiguraci.")
+			case <-serviceReloads:
+				logging.Phase("service").Info("Správce služeb požádal o nové načtení konfigurace.")
+			}
+			r.request(ctx)
+		}
+	}()
+	return r
+}
+
+// request požádá démona o nové načtení konfigurace a počká na výsledek.
+func (r reloader) request(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case r <- reply:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reload znovu načte soubor .env a konfiguraci ze stejné cesty a se stejným
+// profilem jako při spuštění a předá ji démonu funkcí apply. Neplatná
+// konfigurace, nebo taková, kterou apply odmítne, se nepoužije a dál platí
+// dosavadní. Výsledek se zapíše do logu a vrátí do reply.
+func reload(reply chan error, apply func(*config.Config) error) {
+	logger := logging.Phase("service")
+	service.Notify(service.Reloading)
+	defer service.Notify(service.Ready)
+
+	err := config.LoadEnvFile(*envFile, isFlagSet("env-file"))
+	var cfg *config.Config
+	if err == nil {
+		cfg, err = config.Load(*configPath, *env)
+	}
+	if err == nil {
+		err = apply(cfg)
+	}
+	if err != nil {
+		logger.Error("Konfiguraci nelze znovu načíst, platí dosavadní.", "file", *configPath, "error", err)
+		reply <- err
+		return
+	}
+	if cfg.Language != "" {
+		i18n.Set(cfg.Language)
+	}
+	logger.Info("Konfigurace znovu načtena.", "file", *configPath, "profile", *env)
+	reply <- nil
+}
+
+// reloadHandler odpoví na POST /reload: konfigurace se znovu načte stejně
+// jako po signálu SIGHUP a odpověď nese výsledek.
+func reloadHandler(r reloader) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := r.request(req.Context()); err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"reloaded": false, "error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"reloaded": true})
+	})
+}
+
+// swapHandler obsluhuje požadavky handlerem, který lze za běhu vyměnit, aby
+// se po novém načtení konfigurace nemusel zavírat port. Rozpracované požadavky
+// doběhnou s dosavadní konfigurací.
+type swapHandler struct {
+	h atomic.Pointer[http.Handler]
+}
+
+// newSwapHandler vrací swapHandler, který zatím obsluhuje h.
+func newSwapHandler(h http.Handler) *swapHandler {
+	s := &swapHandler{}
+	s.set(h)
+	return s
+}
+
+// set vymění handler pro další požadavky.
+func (s *swapHandler) set(h http.Handler) {
+	s.h.Store(&h)
+}
+
+// ServeHTTP předá požadavek aktuálnímu handleru.
+func (s *swapHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	(*s.h.Load()).ServeHTTP(w, req)
+}
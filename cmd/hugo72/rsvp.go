@@ -0,0 +1,172 @@
+package main
+
+import (
+	_ "embed"
+	"html/template"
+	"net"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/mailer"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// rsvpHTML je šablona formuláře přihlášky.
+//
+//go:embed rsvp.html
+var rsvpHTML string
+
+var rsvpTemplate = template.Must(template.New("rsvp").Parse(rsvpHTML))
+
+// rsvpInterval je nejkratší doba mezi dvěma odpověďmi z jedné adresy.
+const rsvpInterval = 30 * time.Second
+
+// maxName je nejdelší jméno, které formulář přijme (ve znacích).
+const maxName = 100
+
+// rsvpPage jsou data šablony formuláře přihlášky.
+type rsvpPage struct {
+	Title  string
+	Text   string
+	Jmeno  string
+	Email  string
+	Prijde string
+	Token  string
+	Done   bool
+	Error  string
+}
+
+// rsvpLimiter pamatuje si čas poslední odpovědi z každé adresy, aby formulář
+// nešlo zahltit opakovaným odesíláním.
+type rsvpLimiter struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// allow vrací true, pokud z adresy host poslední odpověď přišla před více než rsvpInterval.
+func (l *rsvpLimiter) allow(host string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.last == nil || len(l.last) > 10000 {
+		l.last = map[string]time.Time{}
+	}
+	if now.Sub(l.last[host]) < rsvpInterval {
+		return false
+	}
+	l.last[host] = now
+	return true
+}
+
+// rsvpHandler obsluhuje veřejný formulář přihlášky /rsvp. Odpověď (jméno,
+// e-mail, zda účastník přijde) se připíše do phase1.responsesFile, převod ji
+// doplní do seznamu účastníků a spustí se publikace webu. Formulář lze
+// předvyplnit parametry jmeno a email v odkazu; odpověď z odkazu s platným
+// tokenem z pozvánky (příkaz mail) se označí jako ověřená. E-maily účastníků
+// jsou na webu vidět, proto odpověď za někoho, kdo už v seznamu je, formulář
+// přijme jen ověřenou; jinak by kdokoli mohl změnit cizí odpověď.
+func rsvpHandler(cfg *config.Config, r *runner) http.Handler {
+	responsesFile := convert.ResponsesFile(cfg)
+	limiter := &rsvpLimiter{}
+	var mu sync.Mutex // Odpovědi se do souboru zapisují po jedné
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		page := rsvpPage{
+			Jmeno:  req.FormValue("jmeno"),
+			Email:  req.FormValue("email"),
+			Prijde: req.FormValue("prijde"),
+			Token:  req.FormValue("token"),
+			Done:   req.URL.Query().Get("done") != "",
+		}
+		var attendees []convert.User
+		if cfg.Phase1 != nil {
+			if converted, ok := readConverted(cfg.Phase1.OutputFile); ok {
+				page.Title, page.Text = converted.Info.Nadpis, converted.Info.Zprava
+				attendees = converted.Users
+			}
+		}
+
+		if req.Method == http.MethodPost {
+			if !sameOrigin(req) {
+				http.Error(w, i18n.T("Požadavek z cizí stránky byl odmítnut."), http.StatusForbidden)
+				return
+			}
+			// Pole "web" je skryté, vyplní ho jen robot.
+			if req.PostFormValue("web") != "" {
+				http.Redirect(w, req, "/rsvp?done=1", http.StatusSeeOther)
+				return
+			}
+
+			response := convert.Response{
+				Jmeno:       strings.TrimSpace(page.Jmeno),
+				Email:       strings.TrimSpace(page.Email),
+				Prijde:      convert.Prijde(page.Prijde),
+				SubmittedAt: time.Now(),
+			}
+			tokenChecked := cfg.Mailer != nil && cfg.Mailer.TokenSecret != "" && page.Token != ""
+			if tokenChecked {
+				response.Verified = mailer.ValidToken(cfg.Mailer.TokenSecret, response.Email, page.Token)
+			}
+			host, _, _ := net.SplitHostPort(req.RemoteAddr)
+			switch {
+			case tokenChecked && !response.Verified:
+				// Odkaz je podepsaný pro e-mail z pozvánky, jiná adresa se jím neověří.
+				page.Error = i18n.T("Odkaz z pozvánky patří k jiné e-mailové adrese nebo je neplatný.")
+			case response.Jmeno == "" || utf8.RuneCountInString(response.Jmeno) > maxName:
+				page.Error = i18n.T("Vyplňte prosím jméno.")
+			case !validEmail(response.Email):
+				page.Error = i18n.T("Vyplňte prosím platný e-mail.")
+			case response.Prijde != convert.Ano && response.Prijde != convert.Ne:
+				page.Error = i18n.T("Vyberte prosím, zda přijdete.")
+			case !response.Verified && listed(attendees, response.Email):
+				page.Error = i18n.T("Tato adresa už v seznamu je. Odpověď změníte odkazem z pozvánky.")
+			case !limiter.allow(host, response.SubmittedAt):
+				page.Error = i18n.T("Odpověď z této adresy jsme právě přijali, zkuste to prosím za chvíli.")
+			default:
+				mu.Lock()
+				err := convert.AppendResponse(responsesFile, response)
+				mu.Unlock()
+				if err != nil {
+					logging.Phase("rsvp").Error("Odpověď z formuláře nelze uložit.", "error", err)
+					page.Error = i18n.T("Odpověď se nepodařilo uložit, zkuste to prosím později.")
+					break
+				}
+				logging.Phase("rsvp").Info("Přijata odpověď z formuláře.", "email", response.Email, "prijde", response.Prijde)
+				r.trigger(runRequest{from: "convert", to: "deploy"})
+				http.Redirect(w, req, "/rsvp?done=1", http.StatusSeeOther)
+				return
+			}
+		}
+
+		code := http.StatusOK
+		if page.Error != "" {
+			code = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(code)
+		rsvpTemplate.Execute(w, page)
+	})
+}
+
+// listed vrací true, pokud je e-mail (bez ohledu na velikost písmen) v seznamu účastníků.
+func listed(attendees []convert.User, email string) bool {
+	for _, user := range attendees {
+		if strings.EqualFold(user.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// validEmail vrací true pro samotnou e-mailovou adresu bez jména a závorek.
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email && len(email) <= 254
+}
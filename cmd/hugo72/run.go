@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"slices"
+	"time"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/output"
+	"hugo72/internal/problems"
+	"hugo72/internal/profile"
+	"hugo72/internal/runid"
+	"hugo72/pkg/config"
+)
+
+// pipeline jsou fáze v pořadí, ve kterém je spouští příkaz run.
+var pipeline = []command{
+	{name: "convert", run: withHooks("convert", runConvert)},
+	{name: "build", run: withHooks("build", runBuild)},
+	{name: "deploy", run: withHooks("deploy", runDeploy)},
+}
+
+// phaseTiming je doba běhu jedné fáze pro závěrečný souhrn.
+type phaseTiming struct {
+	name     string
+	started  time.Time
+	duration time.Duration
+	err      error
+	result   any  // Výsledek fáze pro výpis --json
+	skipped  bool // Fáze se přeskočila, protože se od posledního úspěšného běhu nic nezměnilo
+}
+
+// runOptions jsou nastavení jednoho běhu pipeline.
+type runOptions struct {
+	deployArgs    []string // Přepínače předávané fázi deploy
+	skipUnchanged bool     // Přeskočit fáze, u kterých se od posledního úspěšného běhu nic nezměnilo
+	trigger       string   // Co běh spustilo, pro historii běhů: manual, change, schedule, retry nebo request
+	waitForLock   bool     // Počkat, až jiný běh uvolní zámek běhu, místo okamžité chyby
+}
+
+// runPipeline spustí fáze convert, build a deploy za sebou:
+//
+//	run [--from convert] [--to deploy] [--resume] [--force] [--wait] [-- přepínače pro deploy]
+//
+// Chyba kterékoli fáze běh ukončí a vrátí se jako chyba celého příkazu.
+// Chyby, po kterých fáze pokračovala (např. nenahraný soubor nebo neodeslaný
+// webhook), se vypíšou na konci v souhrnné tabulce a příkaz pak skončí
+// s návratovým kódem 9. Na konci se vypíše doba běhu jednotlivých fází. Fáze, která naposledy
+// uspěla a jejíž vstupy, konfigurace ani výstupy se od té doby nezměnily,
+// se přeskočí; --force spustí všechny fáze. S --resume běh začne první
+// fází, kterou je potřeba spustit znovu. Probíhá-li jiný běh (např. watch),
+// příkaz skončí chybou; s --wait počká na jeho dokončení.
+func runPipeline(ctx context.Context, config *config.Config, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	from := fs.String("from", "convert", i18n.T("fáze, kterou běh začne (convert, build nebo deploy)"))
+	to := fs.String("to", "deploy", i18n.T("fáze, po které běh skončí (convert, build nebo deploy)"))
+	resume := fs.Bool("resume", false, i18n.T("pokračovat první fází, která naposledy selhala nebo jejíž vstupy se změnily"))
+	force := fs.Bool("force", false, i18n.T("spustit i fáze, u kterých se nic nezměnilo"))
+	wait := fs.Bool("wait", false, i18n.T("počkat na dokončení jiného běhu místo ukončení s chybou"))
+	fs.Parse(args)
+
+	start, end := phaseIndex(*from), phaseIndex(*to)
+	if start < 0 {
+		return exitcode.With(exitcode.Usage, i18n.Errorf("neznámá fáze '%s'", *from))
+	}
+	if end < 0 {
+		return exitcode.With(exitcode.Usage, i18n.Errorf("neznámá fáze '%s'", *to))
+	}
+	if start > end {
+		return exitcode.With(exitcode.Usage, i18n.Errorf("fáze '%s' následuje až po fázi '%s'", *from, *to))
+	}
+
+	if *resume {
+		start = resumePoint(config, start, end, fs.Args())
+		if start > end {
+			logging.Phase("run").Info("Všechny fáze jsou aktuální, není co spouštět.")
+			output.Result(timingsResult(nil))
+			return nil
+		}
+	}
+
+	_, err := runPhases(ctx, config, start, end, runOptions{deployArgs: fs.Args(), skipUnchanged: !*force, trigger: "manual", waitForLock: *wait})
+	return err
+}
+
+// resumePoint vrací první fázi z rozsahu start až end, kterou je potřeba
+// spustit znovu, nebo end+1, pokud jsou všechny aktuální.
+func resumePoint(config *config.Config, start, end int, deployArgs []string) int {
+	state := loadState(stateFile)
+	for i := start; i <= end; i++ {
+		name := pipeline[i].name
+		if reason := state.stale(name, fingerprint(config, name, phaseArgs(name, deployArgs))); reason != "" {
+			logging.Phase(name).Info("Pokračuji od této fáze.", "reason", reason)
+			return i
+		}
+		logging.Phase(name).Info("Fáze je aktuální, přeskakuji.")
+	}
+	return end + 1
+}
+
+// runPhases spustí fáze pipeline od start do end včetně a vrátí dobu a výsledek
+// každé spuštěné fáze. Dokončení každé fáze se zaznamená do stateFile a celý
+// běh do historie runHistoryFile (obojí mimo zkušební běh); po běhu se odešlou
+// oznámení podle nastavení notify. Po dobu běhu se drží zámek runLockFile
+// a běh má vlastní identifikátor (viz runid), který nesou jeho logy, historie,
+// reporty nasazení i oznámení. Před první fází se uloží záloha souborů, které
+// běh změní (viz snapshotRun). Doba kroků fází se měří pro --profile (viz
+// finishProfile). Na konci běhu se vypíše souhrn problémů (viz
+// finishProblems); běh, který doběhl s chybami, skončí chybou exitcode.Problems.
+func runPhases(ctx context.Context, config *config.Config, start, end int, opts runOptions) (timings []phaseTiming, err error) {
+	lock, err := acquireRunLock(ctx, runLockFile, "run", opts.waitForLock)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+	id := runid.Start()
+	defer runid.End()
+	problems.Start()
+	profile.Start()
+	snapshotRun(config, "run")
+
+	state := loadState(stateFile)
+	previousRecords := state.Registrations
+	started := time.Now()
+	defer func() {
+		logTimings(timings)
+		finishProfile(id)
+		phase := "run"
+		if n := len(timings); n > 0 && timings[n-1].err != nil {
+			phase = timings[n-1].name
+		}
+		var list problems.List
+		list, err = finishProblems(phase, err)
+		result := timingsResult(timings)
+		result["runId"] = id
+		if len(list) > 0 {
+			result["problems"] = list
+		}
+		output.Result(result)
+		if !dryrun.Enabled() {
+			if err := appendRunHistory(runHistoryFile, newRunEntry(id, opts.trigger, started, timings, err)); err != nil {
+				logging.Phase("run").Warn("Běh se nepodařilo zapsat do historie.", "error", err)
+			}
+		}
+		notifyRun(ctx, config, timings, err, previousRecords)
+	}()
+	for _, phase := range pipeline[start : end+1] {
+		if err := ctx.Err(); err != nil {
+			return timings, i18n.Errorf("běh přerušen před fází %s: %w", phase.name, err)
+		}
+
+		args := phaseArgs(phase.name, opts.deployArgs)
+		current := fingerprint(config, phase.name, args)
+		if opts.skipUnchanged && state.stale(phase.name, current) == "" {
+			logging.Phase(phase.name).Info("Vstupy ani konfigurace se od posledního úspěšného běhu nezměnily, fázi přeskakuji.")
+			timings = append(timings, phaseTiming{name: phase.name, started: time.Now(), skipped: true})
+			continue
+		}
+
+		logging.Phase(phase.name).Info("Spouštím fázi.")
+		began := time.Now()
+		err := phase.run(ctx, config, args)
+		timing := phaseTiming{name: phase.name, started: began, duration: time.Since(began), err: err, result: output.Take()}
+		profile.Observe(phase.name, "", timing.duration, 0)
+		timings = append(timings, timing)
+		if !dryrun.Enabled() {
+			state.record(config, phase.name, current, err)
+			if records := countsOf(timing.result).Records; phase.name == "convert" && err == nil && records != nil {
+				state.Registrations = records
+			}
+			if err := state.save(stateFile); err != nil {
+				logging.Phase(phase.name).Warn("Stav běhu se nepodařilo uložit.", "error", err)
+				problems.Warn(phase.name, stateFile, err)
+			}
+		}
+		if err != nil {
+			return timings, i18n.Errorf("fáze %s selhala: %w", phase.name, err)
+		}
+	}
+	return timings, nil
+}
+
+// withPhaseRun spustí samostatnou fázi jako běh: pod zámkem běhu (viz
+// withRunLock), s vlastním identifikátorem, zálohou před během a profilem
+// kroků. Na konci vypíše souhrn problémů stejně jako runPhases.
+func withPhaseRun(name string, run func(context.Context, *config.Config, []string) error) func(context.Context, *config.Config, []string) error {
+	return withRunLock(name, func(ctx context.Context, cfg *config.Config, args []string) error {
+		id := runid.Start()
+		defer runid.End()
+		problems.Start()
+		profile.Start()
+		snapshotRun(cfg, name)
+		began := time.Now()
+		err := run(ctx, cfg, args)
+		profile.Observe(name, "", time.Since(began), 0)
+		finishProfile(id)
+		_, err = finishProblems(name, err)
+		return err
+	})
+}
+
+// phaseArgs vrací přepínače pro fázi: fáze deploy dostane deployArgs, ostatní nic.
+func phaseArgs(name string, deployArgs []string) []string {
+	if name == "deploy" {
+		return deployArgs
+	}
+	return nil
+}
+
+// phaseIndex vrací pořadí fáze v pipeline, nebo -1 pro neznámou fázi.
+func phaseIndex(name string) int {
+	return slices.IndexFunc(pipeline, func(c command) bool { return c.name == name })
+}
+
+// timingsResult vrací výsledek běhu pro výpis --json: stav, dobu a výsledek každé fáze.
+func timingsResult(timings []phaseTiming) map[string]any {
+	phases := []map[string]any{}
+	var total time.Duration
+	for _, t := range timings {
+		phase := map[string]any{
+			"name":            t.name,
+			"ok":              t.err == nil,
+			"durationSeconds": t.duration.Seconds(),
+			"result":          t.result,
+		}
+		if t.skipped {
+			phase["skipped"] = true
+		}
+		if t.err != nil {
+			phase["error"] = t.err.Error()
+		}
+		phases = append(phases, phase)
+		total += t.duration
+	}
+	return map[string]any{"phases": phases, "durationSeconds": total.Seconds()}
+}
+
+// logTimings vypíše souhrn doby běhu spuštěných fází.
+func logTimings(timings []phaseTiming) {
+	logger := logging.Phase("run")
+	var total time.Duration
+	for _, t := range timings {
+		status := "ok"
+		switch {
+		case t.err != nil:
+			status = i18n.T("chyba")
+		case t.skipped:
+			status = i18n.T("přeskočeno")
+		}
+		logger.Info("Souhrn fáze.", "step", t.name, "status", status, "duration", t.duration.Round(time.Millisecond).String())
+		total += t.duration
+	}
+	logger.Info("Souhrn běhu.", "duration", total.Round(time.Millisecond).String())
+}
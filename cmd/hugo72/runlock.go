@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/pkg/config"
+)
+
+// runLockFile je zámek, který drží běh pipeline nebo samostatná fáze, aby
+// se dva běhy na stejném počítači (např. watch a ruční run) nestřídaly
+// při zápisu stejných výstupních souborů.
+const runLockFile = ".hugo72/run.lock"
+
+// runLockPoll je interval, po kterém čekající běh znovu zkusí získat zámek.
+const runLockPoll = time.Second
+
+// runLockWriteGrace je doba, po kterou se prázdný nebo nečitelný zámek považuje
+// za právě zapisovaný jiným procesem, a ne za poškozený.
+const runLockWriteGrace = 10 * time.Second
+
+// runLockInfo je obsah zámku. Podle PID a počítače se pozná, zda proces,
+// který zámek vytvořil, ještě běží.
+type runLockInfo struct {
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	Command   string    `json:"command"` // Příkaz, který zámek drží (run, watch, deploy...)
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// runLock je získaný zámek běhu. Nulová hodnota (zkušební běh) nic neuvolňuje.
+type runLock struct {
+	path string
+}
+
+// acquireRunLock získá zámek běhu pro příkaz command. Drží-li zámek jiný živý
+// proces, s wait počká na jeho uvolnění (nebo zrušení ctx), jinak vrátí chybu
+// s popisem, kdo zámek drží. Zámek procesu, který už neběží, se převezme
+// s varováním. Zkušební běh nic nezapisuje, a proto zámek nepotřebuje.
+func acquireRunLock(ctx context.Context, path, command string, wait bool) (*runLock, error) {
+	if dryrun.Enabled() {
+		return &runLock{}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, i18n.Errorf("chyba při vytváření adresáře pro '%s': %w", path, err)
+	}
+
+	logger := logging.Phase(command)
+	waiting := false
+	for {
+		err := createRunLock(path, command)
+		if err == nil {
+			return &runLock{path: path}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		holder, stale := inspectRunLock(path)
+		if stale {
+			logger.Warn("Přebírám zámek běhu, proces, který ho vytvořil, už neběží.", "pid", holder.PID, "command", holder.Command,
+				"created", holder.CreatedAt.Format(time.DateTime))
+			if err := removeStaleRunLock(path, holder); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !wait {
+			return nil, i18n.Errorf("jiný běh již probíhá: %s (PID %d) drží zámek '%s' od %s", holder.Command, holder.PID, path, holder.CreatedAt.Format(time.DateTime))
+		}
+		if !waiting {
+			logger.Info("Čekám na dokončení jiného běhu.", "pid", holder.PID, "command", holder.Command)
+			waiting = true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, i18n.Errorf("čekání na zámek běhu přerušeno: %w", ctx.Err())
+		case <-time.After(runLockPoll):
+		}
+	}
+}
+
+// createRunLock atomicky vytvoří zámek; existuje-li, vrací chybu os.ErrExist.
+func createRunLock(path, command string) error {
+	host, _ := os.Hostname()
+	data, err := json.Marshal(runLockInfo{PID: os.Getpid(), Host: host, Command: command, CreatedAt: time.Now()})
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci zámku: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return err
+		}
+		return i18n.Errorf("chyba při vytváření zámku '%s': %w", path, err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return i18n.Errorf("chyba při vytváření zámku '%s': %w", path, err)
+	}
+	return nil
+}
+
+// inspectRunLock přečte existující zámek a určí, zda je opuštěný. Opuštěný je
+// zámek procesu na tomto počítači, který už neběží, a zámek, který zůstal
+// prázdný nebo nečitelný déle než runLockWriteGrace. Zámek z jiného počítače
+// (sdílený adresář) nelze ověřit, a proto se za opuštěný nepovažuje.
+func inspectRunLock(path string) (runLockInfo, bool) {
+	var holder runLockInfo
+	info, err := os.Stat(path)
+	if err != nil {
+		// Zámek mezitím zmizel, další pokus ho vytvoří.
+		return holder, errors.Is(err, os.ErrNotExist)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &holder) != nil || holder.PID == 0 {
+		holder = runLockInfo{Command: "?", CreatedAt: info.ModTime()}
+		return holder, time.Since(info.ModTime()) > runLockWriteGrace
+	}
+	if host, _ := os.Hostname(); holder.Host != host {
+		return holder, false
+	}
+	return holder, holder.PID != os.Getpid() && !processAlive(holder.PID)
+}
+
+// removeStaleRunLock odstraní opuštěný zámek, jehož vlastníkem byl holder.
+// Opuštěný zámek mohou zároveň převzít dva běhy; kdyby ho jeden jen smazal,
+// mohl by smazat zámek, který mezitím vytvořil druhý. Zámek se proto nejprve
+// přejmenuje na jedinečný název a smaže se, jen pokud jde stále o tentýž
+// opuštěný zámek. Jinak se vrátí zpět a další pokus rozhodne znovu.
+func removeStaleRunLock(path string, holder runLockInfo) error {
+	taken := fmt.Sprintf("%s.%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, taken); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return i18n.Errorf("chyba při odstraňování zámku '%s': %w", path, err)
+	}
+	current, stale := inspectRunLock(taken)
+	if !stale || current.PID != holder.PID || !current.CreatedAt.Equal(holder.CreatedAt) {
+		if err := os.Rename(taken, path); err != nil {
+			return i18n.Errorf("chyba při odstraňování zámku '%s': %w", path, err)
+		}
+		return nil
+	}
+	if err := os.Remove(taken); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return i18n.Errorf("chyba při odstraňování zámku '%s': %w", path, err)
+	}
+	return nil
+}
+
+// release uvolní zámek.
+func (l *runLock) release() {
+	if l.path == "" {
+		return
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logging.Phase("run").Warn("Zámek běhu se nepodařilo uvolnit.", "file", l.path, "error", err)
+	}
+}
+
+// withRunLock obalí samostatně spuštěnou fázi zámkem běhu, aby se nestřídala
+// s během pipeline. Drží-li zámek jiný proces, příkaz skončí chybou.
+func withRunLock(name string, run func(context.Context, *config.Config, []string) error) func(context.Context, *config.Config, []string) error {
+	return func(ctx context.Context, cfg *config.Config, args []string) error {
+		lock, err := acquireRunLock(ctx, runLockFile, name, false)
+		if err != nil {
+			return err
+		}
+		defer lock.release()
+		return run(ctx, cfg, args)
+	}
+}
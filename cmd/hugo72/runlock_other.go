@@ -0,0 +1,20 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// processAlive zjistí, zda na tomto počítači běží proces s daným PID.
+// Signál 0 proces jen vyhledá; EPERM znamená proces jiného uživatele.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
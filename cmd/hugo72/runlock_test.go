@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// deadPID vrací PID procesu, který už skončil.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.Process.Pid
+}
+
+// writeRunLock zapíše zámek běhu s údaji holder.
+func writeRunLock(t *testing.T, path string, holder runLockInfo) {
+	t.Helper()
+	data, err := json.Marshal(holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireRunLockTakesOverDeadProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.lock")
+	host, _ := os.Hostname()
+	writeRunLock(t, path, runLockInfo{PID: deadPID(t), Host: host, Command: "watch", CreatedAt: time.Now()})
+
+	lock, err := acquireRunLock(context.Background(), path, "run", false)
+	if err != nil {
+		t.Fatalf("acquireRunLock: %v", err)
+	}
+	defer lock.release()
+	holder, stale := inspectRunLock(path)
+	if stale || holder.PID != os.Getpid() {
+		t.Errorf("zámek drží PID %d (opuštěný %v), chci %d", holder.PID, stale, os.Getpid())
+	}
+	if matches, _ := filepath.Glob(path + ".*"); len(matches) > 0 {
+		t.Errorf("po převzetí zůstaly soubory %v", matches)
+	}
+}
+
+func TestRemoveStaleRunLockKeepsNewLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.lock")
+	host, _ := os.Hostname()
+	stale := runLockInfo{PID: deadPID(t), Host: host, Command: "watch", CreatedAt: time.Now().Add(-time.Minute)}
+
+	// Opuštěný zámek mezitím převzal jiný běh a vytvořil vlastní.
+	fresh := runLockInfo{PID: os.Getpid(), Host: host, Command: "run", CreatedAt: time.Now()}
+	writeRunLock(t, path, fresh)
+
+	if err := removeStaleRunLock(path, stale); err != nil {
+		t.Fatalf("removeStaleRunLock: %v", err)
+	}
+	holder, _ := inspectRunLock(path)
+	if holder.Command != fresh.Command || !holder.CreatedAt.Equal(fresh.CreatedAt) {
+		t.Errorf("zámek %+v, chci zachovaný %+v", holder, fresh)
+	}
+}
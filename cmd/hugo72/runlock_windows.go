@@ -0,0 +1,30 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive je návratový kód GetExitCodeProcess pro proces, který ještě běží.
+const stillActive = 259
+
+// processAlive zjistí, zda na tomto počítači běží proces s daným PID.
+// Ukončený proces, na který ještě drží někdo handle, OpenProcess otevře,
+// proto se rozhoduje podle návratového kódu. Proces jiného uživatele, ke
+// kterému nemáme přístup, běží.
+func processAlive(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
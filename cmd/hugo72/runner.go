@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"hugo72/internal/logging"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// runRequest je požadavek na běh pipeline od fáze from po fázi to.
+type runRequest struct {
+	from, to string
+}
+
+// merge vrací požadavek, který pokryje fáze obou požadavků.
+func (r runRequest) merge(other runRequest) runRequest {
+	if phaseIndex(other.from) < phaseIndex(r.from) {
+		r.from = other.from
+	}
+	if phaseIndex(other.to) > phaseIndex(r.to) {
+		r.to = other.to
+	}
+	return r
+}
+
+// runner spouští běhy pipeline po jednom. Požadavek, který přijde během běhu,
+// počká na jeho konec; víc čekajících požadavků se sloučí do jediného běhu.
+type runner struct {
+	deployArgs []string // Přepínače předávané fázi deploy
+	status     *statusTracker
+
+	mu      sync.Mutex
+	cfg     *config.Config // Konfigurace dalšího běhu; po novém načtení se vymění
+	pending *runRequest
+	wake    chan struct{}
+}
+
+// newRunner vrací runner, který spouští pipeline s konfigurací cfg a běhy zaznamenává do status.
+func newRunner(cfg *config.Config, deployArgs []string, status *statusTracker) *runner {
+	return &runner{cfg: cfg, deployArgs: deployArgs, status: status, wake: make(chan struct{}, 1)}
+}
+
+// setConfig nastaví konfiguraci, se kterou proběhnou další běhy. Probíhající
+// běh doběhne s dosavadní konfigurací.
+func (r *runner) setConfig(cfg *config.Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cfg = cfg
+}
+
+// trigger zařadí požadavek na běh. Vrací true, pokud se sloučil s již čekajícím požadavkem.
+func (r *runner) trigger(req runRequest) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	merged := r.pending != nil
+	if merged {
+		req = r.pending.merge(req)
+	}
+	r.pending = &req
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return merged
+}
+
+// loop provádí zařazené požadavky, dokud není zrušen ctx.
+func (r *runner) loop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		}
+
+		r.mu.Lock()
+		req, cfg := r.pending, r.cfg
+		r.pending = nil
+		r.mu.Unlock()
+		if req == nil {
+			continue
+		}
+
+		r.status.begin("request", req.from, req.to)
+		timings, err := runPhases(ctx, cfg, phaseIndex(req.from), phaseIndex(req.to), runOptions{deployArgs: r.deployArgs, trigger: "request", waitForLock: true})
+		r.status.finish(timings, err)
+		output.Take()
+		if err != nil && ctx.Err() == nil {
+			logging.Phase("run").Error("Běh vyvolaný požadavkem selhal.", "error", err)
+		}
+	}
+}
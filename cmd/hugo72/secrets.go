@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// runSecrets pomáhá se zašifrovanými hodnotami v konfiguraci:
+//
+//	secrets keygen                          vytvoří nový klíč age
+//	secrets encrypt --recipient age1...     zašifruje hodnotu ze standardního vstupu
+//
+// Zašifrovanou hodnotu (age:...) lze vložit do konfigurace místo hesla;
+// při načtení ji dešifruje klíč z HUGO72_AGE_KEY nebo HUGO72_AGE_KEY_FILE.
+func runSecrets(_ context.Context, _ *config.Config, args []string) error {
+	if len(args) == 0 {
+		return exitcode.With(exitcode.Usage, errors.New(i18n.T("chybí příkaz: secrets keygen nebo secrets encrypt")))
+	}
+
+	switch args[0] {
+	case "keygen":
+		secret, public, err := config.GenerateKey()
+		if err != nil {
+			return err
+		}
+		if output.Enabled() {
+			output.Result(map[string]string{"publicKey": public, "secretKey": secret})
+			return nil
+		}
+		fmt.Printf("# veřejný klíč: %s\n%s\n", public, secret)
+		return nil
+	case "encrypt":
+		fs := flag.NewFlagSet("secrets encrypt", flag.ExitOnError)
+		var recipients []string
+		fs.Func("recipient", i18n.T("veřejný klíč age1..., pro který se hodnota zašifruje, lze zadat opakovaně"), func(v string) error {
+			recipients = append(recipients, v)
+			return nil
+		})
+		fs.Parse(args[1:])
+
+		value, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		encrypted, err := config.EncryptSecret(strings.TrimRight(string(value), "\r\n"), recipients)
+		if err != nil {
+			return err
+		}
+		if output.Enabled() {
+			output.Result(map[string]string{"value": encrypted})
+			return nil
+		}
+		fmt.Println(encrypted)
+		return nil
+	default:
+		return exitcode.With(exitcode.Usage, i18n.Errorf("neznámý příkaz: secrets %s", args[0]))
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// releaseRepo je repozitář na GitHubu, z jehož vydání se program aktualizuje.
+const releaseRepo = "fedo2/Hugo72"
+
+// githubAPI je adresa GitHub REST API.
+const githubAPI = "https://api.github.com"
+
+// Soubory vydání se seznamem kontrolních součtů SHA-256 (formát sha256sum)
+// a s jeho podpisem Ed25519.
+const (
+	checksumsAsset = "checksums.txt"
+	signatureAsset = "checksums.txt.sig"
+)
+
+// maxDownloadSize je největší velikost staženého souboru vydání.
+const maxDownloadSize = 200 << 20
+
+// updateTimeout je nejdelší doba zjišťování a stažení aktualizace.
+const updateTimeout = 5 * time.Minute
+
+// updatePublicKey je veřejný klíč Ed25519 (base64), kterým jsou podepsané
+// kontrolní součty vydání. Nastavuje se při sestavení vydání přepínačem
+// -ldflags "-X main.updatePublicKey=...". Sestavení bez klíče ověří jen
+// kontrolní součet staženého souboru.
+var updatePublicKey = ""
+
+// release je vydání na GitHubu, jen položky, které aktualizace potřebuje.
+type release struct {
+	TagName string         `json:"tag_name"`
+	HTMLURL string         `json:"html_url"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+// releaseAsset je soubor přiložený k vydání.
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// asset vrací adresu souboru vydání podle názvu.
+func (r *release) asset(name string) (string, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL, true
+		}
+	}
+	return "", false
+}
+
+// runSelfUpdate nahradí program novější verzí z vydání na GitHubu:
+//
+//	self-update [--check] [--version v1.5.0] [--force] [--repo vlastník/repozitář]
+//
+// Stažený soubor se před nahrazením programu ověří kontrolním součtem ze
+// souboru checksums.txt vydání, a pokud program obsahuje veřejný klíč, také
+// podpisem tohoto souboru. S --check nebo --dry-run jen vypíše, zda je
+// k dispozici novější verze.
+func runSelfUpdate(ctx context.Context, _ *config.Config, args []string) error {
+	fs := flag.NewFlagSet("self-update", flag.ExitOnError)
+	check := fs.Bool("check", false, i18n.T("jen zjistit, zda je k dispozici novější verze"))
+	want := fs.String("version", "", i18n.T("nainstalovat zadanou verzi (např. v1.5.0) místo nejnovější"))
+	force := fs.Bool("force", false, i18n.T("nainstalovat verzi, i když není novější než současná"))
+	repo := fs.String("repo", releaseRepo, i18n.T("repozitář na GitHubu, ze kterého se aktualizuje"))
+	fs.Parse(args)
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+	logger := logging.Phase("self-update")
+
+	rel, err := fetchRelease(ctx, *repo, *want)
+	if err != nil {
+		return err
+	}
+	available := *force || newerVersion(rel.TagName, version)
+	result := map[string]any{
+		"current":         version,
+		"latest":          rel.TagName,
+		"url":             rel.HTMLURL,
+		"updateAvailable": available,
+		"updated":         false,
+	}
+	defer output.Result(result)
+
+	if !available {
+		logger.Info("Program je aktuální.", "version", version, "latest", rel.TagName)
+		return nil
+	}
+	if *check || dryrun.Enabled() {
+		logger.Info("Je k dispozici novější verze.", "version", version, "latest", rel.TagName, "url", rel.HTMLURL)
+		return nil
+	}
+	if version == "dev" && !*force {
+		return exitcode.With(exitcode.Usage, errors.New(i18n.T("vývojové sestavení se neaktualizuje, pro nahrazení vydanou verzí použijte --force")))
+	}
+
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		return i18n.Errorf("cestu k programu nelze zjistit: %w", err)
+	}
+
+	name := binaryAssetName()
+	expected, err := releaseChecksum(ctx, rel, name)
+	if err != nil {
+		return exitcode.With(exitcode.Data, err)
+	}
+	url, ok := rel.asset(name)
+	if !ok {
+		return exitcode.With(exitcode.Data, i18n.Errorf("vydání %s neobsahuje soubor %s pro tento systém", rel.TagName, name))
+	}
+
+	logger.Info("Stahuji novou verzi.", "version", rel.TagName, "file", name)
+	tmp, err := downloadVerified(ctx, url, filepath.Dir(exe), expected)
+	if err != nil {
+		return exitcode.With(exitcode.Data, err)
+	}
+	defer os.Remove(tmp)
+	if err := replaceExecutable(exe, tmp); err != nil {
+		return i18n.Errorf("program '%s' nelze nahradit: %w", exe, err)
+	}
+
+	result["updated"] = true
+	logger.Info("Program byl aktualizován.", "from", version, "to", rel.TagName, "file", exe)
+	return nil
+}
+
+// fetchRelease načte z GitHubu vydání se zadanou značkou, nebo nejnovější vydání.
+func fetchRelease(ctx context.Context, repo, tag string) (*release, error) {
+	endpoint := githubAPI + "/repos/" + repo + "/releases/latest"
+	if tag != "" {
+		endpoint = githubAPI + "/repos/" + repo + "/releases/tags/" + tag
+	}
+	body, err := download(ctx, endpoint)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při zjišťování vydání: %w", err)
+	}
+	var rel release
+	if err := json.Unmarshal(body, &rel); err != nil {
+		return nil, i18n.Errorf("chyba při čtení údajů o vydání: %w", err)
+	}
+	return &rel, nil
+}
+
+// releaseChecksum vrací očekávaný kontrolní součet SHA-256 souboru name ze
+// seznamu checksums.txt vydání. S veřejným klíčem v programu nejdřív ověří
+// podpis seznamu.
+func releaseChecksum(ctx context.Context, rel *release, name string) ([]byte, error) {
+	url, ok := rel.asset(checksumsAsset)
+	if !ok {
+		return nil, i18n.Errorf("vydání %s neobsahuje seznam kontrolních součtů %s", rel.TagName, checksumsAsset)
+	}
+	sums, err := download(ctx, url)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při stahování %s: %w", checksumsAsset, err)
+	}
+
+	if updatePublicKey == "" {
+		logging.Phase("self-update").Warn("Program neobsahuje veřejný klíč, podpis vydání se neověří.")
+	} else if err := verifySignature(ctx, rel, sums); err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			sum, err := hex.DecodeString(fields[0])
+			if err != nil || len(sum) != sha256.Size {
+				return nil, i18n.Errorf("neplatný kontrolní součet souboru %s", name)
+			}
+			return sum, nil
+		}
+	}
+	return nil, i18n.Errorf("seznam %s neobsahuje soubor %s", checksumsAsset, name)
+}
+
+// verifySignature ověří podpis Ed25519 seznamu kontrolních součtů veřejným
+// klíčem updatePublicKey. Podpis může být binární nebo zakódovaný v base64.
+func verifySignature(ctx context.Context, rel *release, sums []byte) error {
+	key, err := base64.StdEncoding.DecodeString(updatePublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New(i18n.T("veřejný klíč pro ověření vydání je neplatný"))
+	}
+	url, ok := rel.asset(signatureAsset)
+	if !ok {
+		return i18n.Errorf("vydání %s není podepsané (chybí %s)", rel.TagName, signatureAsset)
+	}
+	sig, err := download(ctx, url)
+	if err != nil {
+		return i18n.Errorf("chyba při stahování %s: %w", signatureAsset, err)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig))); err == nil {
+			sig = decoded
+		}
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), sums, sig) {
+		return i18n.Errorf("podpis vydání %s nesouhlasí, soubor mohl být podvržen", rel.TagName)
+	}
+	return nil
+}
+
+// downloadVerified stáhne soubor do dočasného souboru v adresáři dir a ověří
+// jeho kontrolní součet. Vrací cestu k dočasnému souboru s právem spuštění.
+func downloadVerified(ctx context.Context, url, dir string, expected []byte) (string, error) {
+	resp, err := get(ctx, url)
+	if err != nil {
+		return "", i18n.Errorf("chyba při stahování nové verze: %w", err)
+	}
+	defer resp.Body.Close()
+
+	out, err := os.CreateTemp(dir, ".hugo72-update-*")
+	if err != nil {
+		return "", i18n.Errorf("chyba při vytváření dočasného souboru: %w", err)
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, hash), io.LimitReader(resp.Body, maxDownloadSize))
+	if err == nil {
+		err = out.Chmod(0o755)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		return "", i18n.Errorf("chyba při stahování nové verze: %w", err)
+	}
+	if !bytes.Equal(hash.Sum(nil), expected) {
+		os.Remove(out.Name())
+		return "", errors.New(i18n.T("kontrolní součet stažené verze nesouhlasí, program se nenahradí"))
+	}
+	return out.Name(), nil
+}
+
+// replaceExecutable nahradí program souborem newPath. Běžící program ve Windows
+// nelze přepsat, jen přejmenovat, proto se tam nejdřív odloží pod příponou .old.
+// Když se pak nová verze na místo programu nedostane, vrátí se původní zpět.
+func replaceExecutable(path, newPath string) error {
+	if runtime.GOOS != "windows" {
+		return os.Rename(newPath, path)
+	}
+	old := path + ".old"
+	os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return err
+	}
+	if err := os.Rename(newPath, path); err != nil {
+		if restoreErr := os.Rename(old, path); restoreErr != nil {
+			return errors.Join(err, i18n.Errorf("původní program nelze vrátit z '%s': %w", old, restoreErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// binaryAssetName vrací název souboru vydání pro tento operační systém a architekturu,
+// např. "hugo72_linux_amd64" nebo "hugo72_windows_amd64.exe".
+func binaryAssetName() string {
+	name := "hugo72_" + runtime.GOOS + "_" + runtime.GOARCH
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// download stáhne celý obsah adresy.
+func download(ctx context.Context, url string) ([]byte, error) {
+	resp, err := get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+}
+
+// get pošle požadavek GET. Odpověď mimo rozsah 2xx je považována za chybu.
+// Token z proměnné GITHUB_TOKEN, pokud je nastavená, zvýší limit požadavků na API.
+func get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "hugo72/"+version)
+	if strings.HasPrefix(url, githubAPI) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, i18n.Errorf("služba vrátila stav %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// newerVersion vrací true, pokud je verze tag novější než current. Verze se
+// porovnávají po číselných částech (v1.10.0 je novější než v1.9.2), verze
+// s příponou (v1.5.0-rc1) je starší než stejná verze bez ní. Vývojové
+// sestavení ("dev") je starší než jakékoli vydání.
+func newerVersion(tag, current string) bool {
+	if current == "dev" {
+		return true
+	}
+	a, aPre := parseVersion(tag)
+	b, bPre := parseVersion(current)
+	for i := range max(len(a), len(b)) {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return !aPre && bPre
+}
+
+// parseVersion rozloží verzi jako "v1.5.0-rc1" na číselné části a příznak přípony.
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, pre, hasPre := strings.Cut(v, "-")
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts, hasPre && pre != ""
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/pkg/config"
+)
+
+// defaultListen je adresa, na které server naslouchá bez server.listen v konfiguraci.
+const defaultListen = "127.0.0.1:8072"
+
+// runServe spustí HTTP server, jehož požadavky spouští pipeline:
+//
+//	serve [--listen 127.0.0.1:8072] [-- přepínače pro deploy]
+//
+// Požadavek POST /trigger spustí převod, sestavení a nasazení. Parametrem
+// phase=build lze spustit jedinou fázi, parametry from a to rozsah fází jako
+// u příkazu run. Požadavek se musí prokázat tokenem ze server.token v hlavičce
+// "Authorization: Bearer <token>". Server odpoví hned (202 Accepted) a běh
+// proběhne na pozadí; požadavky přijaté během běhu se sloučí do jednoho dalšího.
+// Požadavek GET /status vrátí poslední běh pipeline a každé fáze, GET /metrics
+// souhrnné počty běhů, doby fází, nahrané bajty a počet přihlášených ve formátu Prometheus.
+// GET /healthz a GET /readyz (bez tokenu) slouží monitoringu a watchdogu systemd.
+// Na adrese / je přehled pro sdílenou obrazovku: počty přihlášených, vývoj
+// přihlášek, poslední běhy fází a chyby (otevírá se odkazem /#token=<token>).
+// S nastaveným server.adminPassword je na adrese /admin administrace, ve které
+// pořadatel upraví nadpis a zprávu webu a spustí publikaci. S server.rsvp je
+// na adrese /rsvp veřejný formulář přihlášky; každá odpověď spustí publikaci.
+// Signál SIGHUP nebo požadavek POST /reload znovu načte konfiguraci bez
+// restartu; probíhající běh doběhne s dosavadní konfigurací a neplatná
+// konfigurace se nepoužije. Server ukončí signál SIGINT nebo SIGTERM.
+func runServe(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	listenAddr := fs.String("listen", "", i18n.T("adresa, na které server naslouchá (výchozí server.listen z konfigurace nebo ")+defaultListen+")")
+	fs.Parse(args)
+
+	if cfg.Server == nil || cfg.Server.Token == "" {
+		return exitcode.With(exitcode.Config, errors.New(i18n.T("konfigurace neobsahuje server.token, bez něj nelze server spustit")))
+	}
+	addr := *listenAddr
+	if addr == "" {
+		addr = cfg.Server.Listen
+	}
+	if addr == "" {
+		addr = defaultListen
+	}
+
+	listener, err := listen(addr)
+	if err != nil {
+		return err
+	}
+	status := newStatusTracker()
+	r := newRunner(cfg, fs.Args(), status)
+	reloads := newReloader(ctx)
+	api := newSwapHandler(newAPI(cfg, status, r, reloads))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.loop(ctx)
+	}()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case reply := <-reloads:
+				reload(reply, func(cfg *config.Config) error {
+					if cfg.Server == nil || cfg.Server.Token == "" {
+						return errors.New(i18n.T("konfigurace neobsahuje server.token, bez něj nelze server spustit"))
+					}
+					if *listenAddr == "" && cmp.Or(cfg.Server.Listen, defaultListen) != addr {
+						logging.Phase("serve").Warn("Změna server.listen se projeví až po restartu.", "address", addr)
+					}
+					r.setConfig(cfg)
+					api.set(newAPI(cfg, status, r, reloads))
+					return nil
+				})
+			}
+		}
+	}()
+	notifyService(ctx)
+	err = serveHTTP(ctx, listener, api)
+	<-done
+	return err
+}
+
+// newAPI vrací obsluhu HTTP požadavků démona. Bez runneru (příkaz watch) API
+// jen čte stav a POST /trigger nenabízí. POST /reload znovu načte konfiguraci.
+// S nastaveným server.token se jím musí prokázat všechny požadavky kromě
+// /healthz a /readyz pro monitoring.
+func newAPI(cfg *config.Config, status *statusTracker, r *runner, reloads reloader) http.Handler {
+	protect := func(h http.Handler) http.Handler { return h }
+	if cfg.Server != nil && cfg.Server.Token != "" {
+		protect = func(h http.Handler) http.Handler { return requireToken(cfg.Server.Token, h) }
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /{$}", dashboardHandler())
+	mux.Handle("GET /dashboard.json", protect(dashboardDataHandler(cfg, status)))
+	mux.Handle("GET /status", protect(status))
+	mux.Handle("GET /metrics", protect(metricsHandler(status)))
+	mux.Handle("GET /healthz", healthHandler())
+	mux.Handle("GET /readyz", readyHandler(cfg, status))
+	mux.Handle("POST /reload", protect(reloadHandler(reloads)))
+	if r != nil {
+		mux.Handle("POST /trigger", protect(triggerHandler(r)))
+		if cfg.Server != nil && cfg.Server.AdminPassword != "" {
+			admin := requireAdmin(cfg.Server, adminHandler(cfg, status, r))
+			mux.Handle("GET /admin", admin)
+			mux.Handle("POST /admin", admin)
+		}
+		if cfg.Server != nil && cfg.Server.RSVP {
+			rsvp := rsvpHandler(cfg, r)
+			mux.Handle("GET /rsvp", rsvp)
+			mux.Handle("POST /rsvp", rsvp)
+		}
+	}
+	return mux
+}
+
+// listen otevře adresu, na které bude server naslouchat.
+func listen(addr string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při spouštění serveru na '%s': %w", addr, err)
+	}
+	return listener, nil
+}
+
+// serveHTTP obsluhuje požadavky na listener, dokud není zrušen ctx.
+func serveHTTP(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	logger := logging.Phase("serve")
+	logger.Info("Server naslouchá.", "address", listener.Addr().String())
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return i18n.Errorf("chyba serveru: %w", err)
+	}
+	logger.Info("Server ukončen.")
+	return nil
+}
+
+// triggerHandler zařadí běh pipeline podle parametrů phase, nebo from a to.
+func triggerHandler(r *runner) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		run := runRequest{from: "convert", to: "deploy"}
+		if phase := req.FormValue("phase"); phase != "" {
+			run.from, run.to = phase, phase
+		}
+		if from := req.FormValue("from"); from != "" {
+			run.from = from
+		}
+		if to := req.FormValue("to"); to != "" {
+			run.to = to
+		}
+
+		start, end := phaseIndex(run.from), phaseIndex(run.to)
+		switch {
+		case start < 0:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf(i18n.T("neznámá fáze '%s'"), run.from)})
+			return
+		case end < 0:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf(i18n.T("neznámá fáze '%s'"), run.to)})
+			return
+		case start > end:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf(i18n.T("fáze '%s' následuje až po fázi '%s'"), run.from, run.to)})
+			return
+		}
+
+		merged := r.trigger(run)
+		logging.Phase("serve").Info("Přijat požadavek na běh.", "from", run.from, "to", run.to, "remote", req.RemoteAddr, "merged", merged)
+		writeJSON(w, http.StatusAccepted, map[string]any{"accepted": true, "from": run.from, "to": run.to, "merged": merged})
+	})
+}
+
+// requireToken propustí jen požadavky s hlavičkou "Authorization: Bearer <token>".
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			logging.Phase("serve").Warn("Odmítnut požadavek bez platného tokenu.", "remote", req.RemoteAddr, "path", req.URL.Path)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": i18n.T("chybí nebo je neplatný token")})
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// writeJSON odešle odpověď ve formátu JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/output"
+	"hugo72/internal/service"
+	"hugo72/pkg/config"
+)
+
+// notifyService oznámí systemd, že je démon připravený, spustí watchdog
+// a po zrušení ctx oznámí ukončení. Mimo službu systemd nedělá nic.
+func notifyService(ctx context.Context) {
+	if err := service.Notify(service.Ready); err != nil {
+		logging.Phase("service").Warn("Stav služby nelze oznámit.", "error", err)
+	}
+	service.Watchdog(ctx)
+	go func() {
+		<-ctx.Done()
+		service.Notify(service.Stopping)
+	}()
+}
+
+// runService připraví provoz démona jako služby systemd nebo služby Windows:
+//
+//	service unit [--name hugo72] [--user] [-- serve|watch [přepínače]]
+//	service install [--name hugo72] [--user] [--force] [-- serve|watch [přepínače]]
+//	service uninstall [--name hugo72] [--user]
+//
+// unit vypíše jednotku systemd. install ji v Linuxu zapíše do /etc/systemd/system
+// (s --user do ~/.config/systemd/user), ve Windows zaregistruje službu; uninstall
+// je odstraní. Služba spustí démona (výchozí serve) v aktuálním adresáři
+// s konfigurací, profilem a souborem .env zadanými při instalaci. Pod systemd
+// jde log do žurnálu a systemctl reload znovu načte konfiguraci; ve Windows jde
+// log do protokolu událostí a konfiguraci znovu načte sc control <služba> paramchange.
+func runService(_ context.Context, _ *config.Config, args []string) error {
+	if len(args) == 0 {
+		return exitcode.With(exitcode.Usage, errors.New(i18n.T("použití: service unit|install|uninstall [přepínače]")))
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("service "+action, flag.ExitOnError)
+	name := fs.String("name", "hugo72", i18n.T("název služby"))
+	user := fs.Bool("user", false, i18n.T("uživatelská jednotka systemd (systemctl --user)"))
+	force := fs.Bool("force", false, i18n.T("přepsat existující jednotku systemd"))
+	fs.Parse(args[1:])
+
+	switch action {
+	case "unit", "install":
+		daemon := fs.Args()
+		if len(daemon) == 0 {
+			daemon = []string{"serve"}
+		}
+		if !slices.Contains([]string{"serve", "watch"}, daemon[0]) {
+			return exitcode.With(exitcode.Usage, i18n.Errorf("jako službu lze spustit jen serve nebo watch, ne '%s'", daemon[0]))
+		}
+		// Služba s chybnou konfigurací by se po spuštění hned ukončila.
+		if _, err := config.Load(*configPath, *env); err != nil {
+			return exitcode.With(exitcode.Config, err)
+		}
+		exec, dir, err := serviceCommand(daemon)
+		if err != nil {
+			return err
+		}
+		description := fmt.Sprintf("hugo72 %s (%s)", daemon[0], dir)
+		if action == "unit" {
+			unit := service.Unit(service.UnitOptions{Description: description, WorkingDir: dir, Exec: exec, User: *user})
+			if output.Enabled() {
+				output.Result(map[string]any{"unit": unit})
+			} else {
+				fmt.Print(unit)
+			}
+			return nil
+		}
+		if runtime.GOOS == "windows" {
+			return installWindowsService(*name, description, exec)
+		}
+		return installUnit(*name, *user, *force, service.Unit(service.UnitOptions{Description: description, WorkingDir: dir, Exec: exec, User: *user}))
+	case "uninstall":
+		if runtime.GOOS == "windows" {
+			return uninstallWindowsService(*name)
+		}
+		return uninstallUnit(*name, *user)
+	default:
+		return exitcode.With(exitcode.Usage, i18n.Errorf("neznámá akce '%s', použijte unit, install nebo uninstall", action))
+	}
+}
+
+// serviceCommand vrací příkazovou řádku služby a pracovní adresář, ve kterém
+// se má spustit. Cesty ke konfiguraci a souboru .env se převedou na absolutní,
+// aby nezáležely na adresáři, ve kterém služba startuje.
+func serviceCommand(daemon []string) ([]string, string, error) {
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		return nil, "", i18n.Errorf("cestu k programu nelze zjistit: %w", err)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, "", i18n.Errorf("pracovní adresář nelze zjistit: %w", err)
+	}
+	cfgPath, err := filepath.Abs(*configPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	exec := []string{exe, "--dir", dir, "--config", cfgPath}
+	if *env != "" {
+		exec = append(exec, "--env", *env)
+	}
+	if _, err := os.Stat(*envFile); err == nil || isFlagSet("env-file") {
+		envPath, err := filepath.Abs(*envFile)
+		if err != nil {
+			return nil, "", err
+		}
+		exec = append(exec, "--env-file", envPath)
+	}
+	return append(exec, daemon...), dir, nil
+}
+
+// unitPath vrací cestu k souboru jednotky systemd.
+func unitPath(name string, user bool) (string, error) {
+	if !user {
+		return filepath.Join("/etc/systemd/system", name+".service"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", i18n.Errorf("adresář uživatelské konfigurace nelze zjistit: %w", err)
+	}
+	return filepath.Join(dir, "systemd", "user", name+".service"), nil
+}
+
+// installUnit zapíše jednotku systemd a vypíše, jak službu zapnout.
+func installUnit(name string, user, force bool, unit string) error {
+	if runtime.GOOS != "linux" {
+		return errors.New(i18n.T("služby jsou podporované jen pod systemd (Linux) a ve Windows"))
+	}
+	path, err := unitPath(name, user)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil && !force {
+		return i18n.Errorf("jednotka '%s' již existuje, pro přepsání použijte --force", path)
+	}
+
+	logger := logging.Phase("service")
+	systemctl := "systemctl"
+	if user {
+		systemctl = "systemctl --user"
+	}
+	if dryrun.Enabled() {
+		logger.Info("Zkušební běh, jednotka se nezapíše.", "file", path)
+	} else {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return i18n.Errorf("chyba při vytváření adresáře pro '%s': %w", path, err)
+		}
+		if err := os.WriteFile(path, []byte(unit), 0o644); err != nil {
+			return i18n.Errorf("chyba při zápisu souboru '%s': %w", path, err)
+		}
+		logger.Info("Jednotka systemd zapsána, službu zapnete příkazem:", "file", path,
+			"command", fmt.Sprintf("%s daemon-reload && %s enable --now %s", systemctl, systemctl, name))
+	}
+	output.Result(map[string]any{"unit": path, "dryRun": dryrun.Enabled()})
+	return nil
+}
+
+// uninstallUnit odstraní jednotku systemd.
+func uninstallUnit(name string, user bool) error {
+	if runtime.GOOS != "linux" {
+		return errors.New(i18n.T("služby jsou podporované jen pod systemd (Linux) a ve Windows"))
+	}
+	path, err := unitPath(name, user)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return exitcode.With(exitcode.NothingToDo, i18n.Errorf("jednotka '%s' neexistuje", path))
+	}
+
+	logger := logging.Phase("service")
+	systemctl := "systemctl"
+	if user {
+		systemctl = "systemctl --user"
+	}
+	if dryrun.Enabled() {
+		logger.Info("Zkušební běh, jednotka se neodstraní.", "file", path)
+	} else {
+		if err := os.Remove(path); err != nil {
+			return i18n.Errorf("chyba při odstraňování souboru '%s': %w", path, err)
+		}
+		logger.Info("Jednotka systemd odstraněna, běžící službu zastavíte příkazem:", "file", path,
+			"command", fmt.Sprintf("%s disable --now %s && %s daemon-reload", systemctl, name, systemctl))
+	}
+	output.Result(map[string]any{"unit": path, "dryRun": dryrun.Enabled()})
+	return nil
+}
+
+// installWindowsService zaregistruje službu Windows.
+func installWindowsService(name, description string, exec []string) error {
+	if dryrun.Enabled() {
+		logging.Phase("service").Info("Zkušební běh, služba se nezaregistruje.", "service", name)
+	} else {
+		if err := service.Install(name, description, exec[0], exec[1:]); err != nil {
+			return err
+		}
+		logging.Phase("service").Info("Služba zaregistrována, spustíte ji příkazem:", "service", name, "command", "sc start "+name)
+	}
+	output.Result(map[string]any{"service": name, "dryRun": dryrun.Enabled()})
+	return nil
+}
+
+// uninstallWindowsService odstraní službu Windows.
+func uninstallWindowsService(name string) error {
+	if dryrun.Enabled() {
+		logging.Phase("service").Info("Zkušební běh, služba se neodstraní.", "service", name)
+	} else {
+		if err := service.Uninstall(name); err != nil {
+			return err
+		}
+		logging.Phase("service").Info("Služba odstraněna.", "service", name)
+	}
+	output.Result(map[string]any{"service": name, "dryRun": dryrun.Enabled()})
+	return nil
+}
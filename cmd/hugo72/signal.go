@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"hugo72/internal/exitcode"
+)
+
+// cancelOnSignal vrací kontext, který se zruší po přijetí signálu SIGINT nebo SIGTERM.
+// Příkaz se pak řádně ukončí: zastaví se Hugo, přeruší se probíhající přenosy,
+// nedokončené soubory se smažou, uvolní se zámek a zapíše se report.
+// Druhý signál už ukončí program okamžitě.
+func cancelOnSignal() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		slog.Warn("Přijat signál, ukončuji příkaz (dalším signálem se program ukončí okamžitě).", "signal", sig.String())
+		cancel()
+		sig = <-signals
+		slog.Error("Přijat další signál, program končí okamžitě.", "signal", sig.String())
+		os.Exit(int(exitcode.Cancelled))
+	}()
+	return ctx
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/pkg/build"
+	"hugo72/pkg/config"
+	"hugo72/pkg/convert"
+)
+
+// stateFile je soubor, do kterého se ukládá stav dokončení fází pro run --resume.
+const stateFile = ".hugo72/run-state.json"
+
+// runState je stav posledního běhu každé fáze.
+type runState struct {
+	Phases        map[string]phaseState `json:"phases"`
+	Registrations *int64                `json:"registrations,omitempty"` // Počet přihlášek z posledního úspěšného převodu pro oznámení newRegistrations
+}
+
+// phaseState zaznamenává poslední běh fáze: otisk vstupů a konfigurace, ze
+// kterých fáze vycházela, a otisk výstupů, které po sobě nechala.
+type phaseState struct {
+	InputsHash  string    `json:"inputsHash"`
+	ConfigHash  string    `json:"configHash"`
+	OutputsHash string    `json:"outputsHash,omitempty"`
+	CompletedAt time.Time `json:"completedAt"`
+	OK          bool      `json:"ok"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// phaseFiles jsou soubory a adresáře, které fáze čte a zapisuje.
+type phaseFiles struct {
+	inputs  []string
+	outputs []string
+	skip    []string // Názvy položek, které se do vstupů nepočítají (např. výstup Huga uvnitř webu)
+}
+
+// filesOf vrací vstupy a výstupy fáze podle konfigurace a přepínačů fáze args.
+func filesOf(cfg *config.Config, phase string, args []string) phaseFiles {
+	switch phase {
+	case "convert":
+		if cfg.Phase1 != nil {
+			return phaseFiles{inputs: []string{cfg.Phase1.InputFile, convert.MessagesFile(cfg), convert.ResponsesFile(cfg)}, outputs: []string{cfg.Phase1.OutputFile}}
+		}
+	case "build":
+		siteDir := build.SiteDir(cfg)
+		files := phaseFiles{
+			inputs:  []string{siteDir},
+			outputs: []string{filepath.Join(siteDir, "public")},
+			skip:    []string{"public", "resources", ".hugo_build.lock"},
+		}
+		if cfg.Phase2 != nil && cfg.Phase2.Badges != "" && cfg.Phase1 != nil {
+			files.inputs = append(files.inputs, cfg.Phase1.OutputFile)
+			files.outputs = append(files.outputs, cfg.Phase2.Badges)
+			files.skip = append(files.skip, filepath.Base(cfg.Phase2.Badges)) // Jmenovky mohou ležet i v adresáři webu
+		}
+		return files
+	case "deploy":
+		// S --artifact se nasazuje obsah archivu, ne soubory z konfigurace.
+		if artifact := deployArtifact(args); artifact != "" {
+			return phaseFiles{inputs: []string{artifact}}
+		}
+		if cfg.Phase3 != nil {
+			var files []string
+			for _, file := range cfg.Phase3.FilesToUpload {
+				files = append(files, strings.TrimSpace(file))
+			}
+			return phaseFiles{inputs: files}
+		}
+	}
+	return phaseFiles{}
+}
+
+// loadState načte stav fází. Chybějící nebo poškozený soubor znamená prázdný
+// stav; run --resume pak pipeline spustí od začátku.
+func loadState(filePath string) runState {
+	state := runState{Phases: map[string]phaseState{}}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil || state.Phases == nil {
+		state.Phases = map[string]phaseState{}
+	}
+	return state
+}
+
+// save zapíše stav fází do souboru.
+func (s runState) save(filePath string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci stavu běhu: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře '%s': %w", filepath.Dir(filePath), err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return i18n.Errorf("chyba při zápisu stavu běhu '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// fingerprint vrací aktuální otisk vstupů, konfigurace a výstupů fáze.
+// Přepínače args patří ke konfiguraci fáze (např. --target u nasazení).
+func fingerprint(cfg *config.Config, phase string, args []string) phaseState {
+	files := filesOf(cfg, phase, args)
+	return phaseState{
+		InputsHash:  hashPaths(files.inputs, files.skip),
+		ConfigHash:  configHash(cfg, phase, args),
+		OutputsHash: hashPaths(files.outputs, nil),
+	}
+}
+
+// configHash vrací otisk sekce konfigurace, se kterou fáze pracuje, a jejích přepínačů.
+func configHash(cfg *config.Config, phase string, args []string) string {
+	var section any
+	switch phase {
+	case "convert":
+		section = cfg.Phase1
+	case "build":
+		section = cfg.Phase2
+	case "deploy":
+		section = cfg.Phase3
+	}
+	data, _ := json.Marshal(map[string]any{"config": section, "args": args})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// record zaznamená dokončení fáze s otiskem current spočítaným před jejím
+// spuštěním. Otisk výstupů se počítá znovu jen po úspěšném běhu.
+func (s runState) record(cfg *config.Config, phase string, current phaseState, err error) {
+	ps := phaseState{InputsHash: current.InputsHash, ConfigHash: current.ConfigHash, CompletedAt: time.Now(), OK: err == nil}
+	if err != nil {
+		ps.Error = err.Error()
+	} else {
+		ps.OutputsHash = hashPaths(filesOf(cfg, phase, nil).outputs, nil)
+	}
+	s.Phases[phase] = ps
+}
+
+// stale vrací důvod, proč je potřeba fázi spustit znovu, nebo "" pro fázi,
+// která naposledy uspěla a jejíž vstupy, konfigurace ani výstupy se od té
+// doby nezměnily. Aktuální otisk current vrací fingerprint.
+func (s runState) stale(phase string, current phaseState) string {
+	ps, ok := s.Phases[phase]
+	switch {
+	case !ok:
+		return "fáze ještě neproběhla"
+	case !ps.OK:
+		return "fáze naposledy selhala"
+	case current.InputsHash != ps.InputsHash:
+		return "změnily se vstupy fáze"
+	case current.ConfigHash != ps.ConfigHash:
+		return "změnila se konfigurace fáze"
+	case current.OutputsHash != ps.OutputsHash:
+		return "změnily se výstupy fáze"
+	}
+	return ""
+}
+
+// hashPaths vrací otisk obsahu souborů a adresářů. Adresáře se procházejí
+// rekurzivně a položky s názvem ze skip se vynechají. Do otisku se počítají
+// cesty i obsah souborů, takže ho změní přidání, smazání i přejmenování souboru.
+func hashPaths(paths []string, skip []string) string {
+	h := sha256.New()
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			fmt.Fprintf(h, "missing %s\n", root)
+			continue
+		}
+		if !info.IsDir() {
+			fmt.Fprintf(h, "file %s %s\n", filepath.ToSlash(root), hashContent(root))
+			continue
+		}
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if path != root && slices.Contains(skip, d.Name()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() {
+				fmt.Fprintf(h, "file %s %s\n", filepath.ToSlash(path), hashContent(path))
+			}
+			return nil
+		})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashContent vrací SHA-256 obsahu souboru, nebo "unreadable", pokud nejde přečíst.
+func hashContent(filePath string) string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "unreadable"
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "unreadable"
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hugo72/pkg/config"
+)
+
+func TestFingerprintHashesDeployArtifact(t *testing.T) {
+	artifact := filepath.Join(t.TempDir(), "build.tar.gz")
+	if err := os.WriteFile(artifact, []byte("první"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Phase3: &config.Phase3{}}
+	args := []string{"--target", "production", "--artifact", artifact}
+
+	before := fingerprint(cfg, "deploy", args)
+	if err := os.WriteFile(artifact, []byte("druhý"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	after := fingerprint(cfg, "deploy", args)
+	if before.InputsHash == after.InputsHash {
+		t.Error("otisk vstupů fáze deploy se po změně artefaktu nezměnil")
+	}
+	if got := filesOf(cfg, "deploy", args).inputs; len(got) != 1 || got[0] != artifact {
+		t.Errorf("vstupy %v, chci jen artefakt %s", got, artifact)
+	}
+}
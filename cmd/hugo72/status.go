@@ -0,0 +1,129 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// statusTracker si pamatuje poslední běh pipeline a poslední běh každé fáze.
+// Démon (watch, serve) ho zpřístupňuje přes GET /status, aby se dashboard
+// a monitoring mohly zeptat, zda je web aktuální.
+type statusTracker struct {
+	mu      sync.Mutex
+	since   time.Time
+	running *runStatus
+	lastRun *runStatus
+	phases  map[string]phaseStatus
+	metrics metrics // Souhrnné počty pro GET /metrics
+
+	lastSuccess time.Time // Konec posledního úspěšného běhu pro GET /readyz
+}
+
+// runStatus popisuje jeden běh pipeline.
+type runStatus struct {
+	Trigger         string     `json:"trigger"` // Co běh spustilo: change, schedule, retry nebo request
+	From            string     `json:"from"`
+	To              string     `json:"to"`
+	StartedAt       time.Time  `json:"startedAt"`
+	FinishedAt      *time.Time `json:"finishedAt,omitempty"`
+	OK              bool       `json:"ok"`
+	Error           string     `json:"error,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds"`
+}
+
+// phaseStatus popisuje poslední běh jedné fáze. Result je výsledek fáze stejný
+// jako ve výpisu --json, např. počet záznamů převodu nebo report nasazení s commitem.
+type phaseStatus struct {
+	StartedAt       time.Time  `json:"startedAt"`
+	OK              bool       `json:"ok"`
+	Error           string     `json:"error,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds"`
+	Result          any        `json:"result,omitempty"`
+	LastSuccessAt   *time.Time `json:"lastSuccessAt,omitempty"`
+}
+
+// statusSnapshot je odpověď GET /status.
+type statusSnapshot struct {
+	Since   time.Time              `json:"since"` // Spuštění démona
+	Running *runStatus             `json:"running,omitempty"`
+	LastRun *runStatus             `json:"lastRun,omitempty"`
+	Phases  map[string]phaseStatus `json:"phases"`
+}
+
+// newStatusTracker vrací prázdný přehled běhů.
+func newStatusTracker() *statusTracker {
+	return &statusTracker{since: time.Now(), phases: map[string]phaseStatus{}}
+}
+
+// begin zaznamená začátek běhu.
+func (s *statusTracker) begin(trigger, from, to string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = &runStatus{Trigger: trigger, From: from, To: to, StartedAt: time.Now()}
+}
+
+// finish zaznamená konec běhu a výsledky jeho fází.
+func (s *statusTracker) finish(timings []phaseTiming, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := s.running
+	if run == nil {
+		return
+	}
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.DurationSeconds = finished.Sub(run.StartedAt).Seconds()
+	run.OK = err == nil
+	if err != nil {
+		run.Error = err.Error()
+	}
+	s.lastRun, s.running = run, nil
+	if err == nil {
+		s.lastSuccess = finished
+	}
+	s.metrics.observe(timings, err)
+
+	for _, t := range timings {
+		ps := phaseStatus{
+			StartedAt:       t.started,
+			OK:              t.err == nil,
+			DurationSeconds: t.duration.Seconds(),
+			Result:          t.result,
+			LastSuccessAt:   s.phases[t.name].LastSuccessAt,
+		}
+		if t.err != nil {
+			ps.Error = t.err.Error()
+		} else {
+			end := t.started.Add(t.duration)
+			ps.LastSuccessAt = &end
+		}
+		s.phases[t.name] = ps
+	}
+}
+
+// snapshot vrací kopii aktuálního stavu.
+func (s *statusTracker) snapshot() statusSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := statusSnapshot{Since: s.since, Phases: make(map[string]phaseStatus, len(s.phases))}
+	if s.running != nil {
+		running := *s.running
+		snap.Running = &running
+	}
+	if s.lastRun != nil {
+		lastRun := *s.lastRun
+		snap.LastRun = &lastRun
+	}
+	for name, ps := range s.phases {
+		snap.Phases[name] = ps
+	}
+	return snap
+}
+
+// ServeHTTP odpoví na GET /status aktuálním stavem.
+func (s *statusTracker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.snapshot())
+}
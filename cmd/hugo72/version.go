@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// Údaje o sestavení. Nastavují se při sestavení přepínačem -ldflags, např.
+//
+//	go build -ldflags "-X main.version=1.4.0 -X main.commit=$(git rev-parse HEAD) -X main.date=$(date -u +%FT%TZ)" ./cmd/hugo72
+//
+// Bez nich se commit a čas doplní z údajů, které do programu zapisuje go build
+// v git repozitáři (viz buildInfo).
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+// versionInfo popisuje sestavení programu.
+type versionInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Dirty   bool   `json:"dirty,omitempty"` // Sestaveno z neuložených změn
+	Go      string `json:"go"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+}
+
+// buildInfo vrací údaje o sestavení. Commit a čas, které nenastavilo -ldflags,
+// doplní z údajů o verzovacím systému zapsaných při go build.
+func buildInfo() versionInfo {
+	info := versionInfo{Version: version, Commit: commit, Date: date, Go: runtime.Version(), OS: runtime.GOOS, Arch: runtime.GOARCH}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = s.Value
+			}
+		case "vcs.modified":
+			info.Dirty = s.Value == "true" && commit == ""
+		}
+	}
+	return info
+}
+
+// runVersion vypíše verzi programu, commit a čas sestavení:
+//
+//	version
+func runVersion(_ context.Context, _ *config.Config, _ []string) error {
+	info := buildInfo()
+	if output.Enabled() {
+		output.Result(info)
+		return nil
+	}
+
+	fmt.Printf("hugo72 %s", info.Version)
+	if info.Commit != "" {
+		fmt.Printf(" (commit %s", shortHash(info.Commit))
+		if info.Dirty {
+			fmt.Print(i18n.T(", neuložené změny"))
+		}
+		fmt.Print(")")
+	}
+	fmt.Println()
+	if info.Date != "" {
+		fmt.Printf(i18n.T("Sestaveno: %s\n"), info.Date)
+	}
+	fmt.Printf("Go: %s %s/%s\n", info.Go, info.OS, info.Arch)
+	return nil
+}
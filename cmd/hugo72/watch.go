@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"time"
+
+	"hugo72/internal/cron"
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/output"
+	"hugo72/pkg/config"
+)
+
+// runWatch sleduje vstupní Excel soubor a po každé jeho změně spustí převod,
+// sestavení a nasazení stejně jako příkaz run:
+//
+//	watch [--interval 5s] [--debounce 10s] [--max-backoff 15m] [--listen adresa] [-- přepínače pro deploy]
+//
+// Změna se pozná podle času úpravy a velikosti souboru. Publikace se spustí až
+// po uplynutí --debounce od poslední změny, aby se nezpracoval rozepsaný soubor.
+// Neúspěšná publikace se opakuje s rostoucí prodlevou až do --max-backoff.
+// Výrazy cronu ve watch.schedule spouští publikaci navíc v pevných časech,
+// např. "*/15 8-21 * * *" každých 15 minut od 8:00 do 22:00, a nahrazují tak
+// systémový cron. S přepínačem --listen je na zadané adrese dostupný přehled
+// (GET /), stav běhů (GET /status), metriky (GET /metrics) a kontroly stavu
+// (GET /healthz, /readyz) jako u příkazu serve. Signál SIGHUP nebo požadavek
+// POST /reload znovu načte konfiguraci (sledovaný soubor, rozvrh) bez restartu.
+// Sledování ukončí signál SIGINT nebo SIGTERM.
+func runWatch(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := fs.Duration("interval", 5*time.Second, i18n.T("jak často kontrolovat změnu souboru"))
+	debounce := fs.Duration("debounce", 10*time.Second, i18n.T("prodleva od poslední změny souboru před spuštěním publikace"))
+	maxBackoff := fs.Duration("max-backoff", 15*time.Minute, i18n.T("nejdelší prodleva před opakováním neúspěšné publikace"))
+	listenAddr := fs.String("listen", "", i18n.T("adresa, na které je dostupný stav běhů (např. 127.0.0.1:8072)"))
+	fs.Parse(args)
+
+	input, schedules, err := watchSettings(cfg)
+	if err != nil {
+		return exitcode.With(exitcode.Config, err)
+	}
+	status := newStatusTracker()
+	reloads := newReloader(ctx)
+	api := newSwapHandler(newAPI(cfg, status, nil, reloads))
+	if *listenAddr != "" {
+		listener, err := listen(*listenAddr)
+		if err != nil {
+			return err
+		}
+		go serveHTTP(ctx, listener, api)
+	}
+	notifyService(ctx)
+
+	logger := logging.Phase("watch")
+	logger.Info("Sleduji vstupní soubor.", "file", input, "interval", interval.String(), "debounce", debounce.String())
+
+	last, _ := statFile(input)
+	var changedAt, retryAt time.Time // Nulový čas = nic nečeká
+	scheduledAt := nextScheduled(schedules, time.Now())
+	if !scheduledAt.IsZero() {
+		logger.Info("Naplánován běh.", "at", scheduledAt.Format(time.DateTime))
+	}
+	var backoff time.Duration
+	runs, failures := 0, 0
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Sledování ukončeno.", "runs", runs, "failures", failures)
+			output.Result(map[string]any{"runs": runs, "failures": failures})
+			return nil
+		case reply := <-reloads:
+			// Běh pipeline probíhá uvnitř smyčky, nová konfigurace tedy platí až od dalšího běhu.
+			reload(reply, func(newCfg *config.Config) error {
+				newInput, newSchedules, err := watchSettings(newCfg)
+				if err != nil {
+					return err
+				}
+				cfg, schedules = newCfg, newSchedules
+				if newInput != input {
+					input, changedAt = newInput, time.Time{}
+					last, _ = statFile(input)
+					logger.Info("Sleduji vstupní soubor.", "file", input, "interval", interval.String(), "debounce", debounce.String())
+				}
+				scheduledAt = nextScheduled(schedules, time.Now())
+				api.set(newAPI(cfg, status, nil, reloads))
+				return nil
+			})
+		case now := <-ticker.C:
+			if state, err := statFile(input); err == nil && state != last {
+				last, changedAt = state, now
+				logger.Info("Vstupní soubor se změnil.", "file", input)
+			}
+			due := !changedAt.IsZero() && now.Sub(changedAt) >= *debounce
+			retry := !retryAt.IsZero() && !now.Before(retryAt)
+			scheduled := !scheduledAt.IsZero() && !now.Before(scheduledAt)
+			if !due && !retry && !scheduled {
+				continue
+			}
+
+			trigger := "change"
+			switch {
+			case scheduled:
+				trigger = "schedule"
+				logger.Info("Spouštím naplánovaný běh.")
+			case retry && !due:
+				trigger = "retry"
+			}
+			changedAt, retryAt = time.Time{}, time.Time{}
+			runs++
+			status.begin(trigger, pipeline[0].name, pipeline[len(pipeline)-1].name)
+			timings, err := runPhases(ctx, cfg, 0, len(pipeline)-1, runOptions{deployArgs: fs.Args(), trigger: trigger, waitForLock: true})
+			status.finish(timings, err)
+			output.Take()
+			switch {
+			case ctx.Err() != nil:
+				// Ukončení sledování se zpracuje v dalším průchodu smyčkou.
+			case exitcode.From(err) == exitcode.Problems:
+				// Publikace doběhla, opakování by chyby kroků mimo ni nejspíš nevyřešilo.
+				backoff = 0
+				logger.Warn("Publikace dokončena s chybami, sleduji další změny.", "error", err)
+			case err != nil:
+				failures++
+				backoff = min(max(2*backoff, *debounce), *maxBackoff)
+				retryAt = time.Now().Add(backoff)
+				logger.Error("Publikace selhala, další pokus proběhne později.", "error", err, "retryIn", backoff.String())
+			default:
+				backoff = 0
+				logger.Info("Publikace dokončena, sleduji další změny.")
+			}
+			// Časy, které uplynuly během běhu, se přeskočí.
+			if scheduled {
+				scheduledAt = nextScheduled(schedules, time.Now())
+			}
+		}
+	}
+}
+
+// watchSettings vrací sledovaný soubor a rozvrhy běhů z konfigurace.
+func watchSettings(cfg *config.Config) (string, []*cron.Schedule, error) {
+	if cfg.Phase1 == nil {
+		return "", nil, errors.New(i18n.T("konfigurace neobsahuje sekci phase1, není co sledovat"))
+	}
+	var schedules []*cron.Schedule
+	if cfg.Watch != nil {
+		for _, expr := range cfg.Watch.Schedule {
+			s, err := cron.Parse(expr)
+			if err != nil {
+				return "", nil, err
+			}
+			schedules = append(schedules, s)
+		}
+	}
+	return cfg.Phase1.InputFile, schedules, nil
+}
+
+// nextScheduled vrací nejbližší čas po t podle některého z rozvrhů, nebo nulový čas bez rozvrhu.
+func nextScheduled(schedules []*cron.Schedule, t time.Time) time.Time {
+	var next time.Time
+	for _, s := range schedules {
+		if n := s.Next(t); next.IsZero() || n.Before(next) {
+			next = n
+		}
+	}
+	return next
+}
+
+// fileState je čas úpravy a velikost souboru, podle kterých se pozná jeho změna.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFile vrací aktuální stav souboru.
+func statFile(path string) (fileState, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}, err
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}, nil
+}
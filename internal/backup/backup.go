@@ -0,0 +1,485 @@
+// Package backup ukládá před každým během zálohu souborů, které běh čte
+// a přepisuje, aby šlo po chybném běhu obnovit stav před ním.
+//
+// Záloha je archiv tar.gz pojmenovaný identifikátorem běhu (viz runid), např.
+// .hugo72/backups/20250601-100000-3f9a2c1b.tar.gz. Obsahuje popis zálohy
+// backup.json a zálohované soubory rozdělené podle druhu:
+//
+//	backup.json           kdy a kterým příkazem záloha vznikla, původní cesty a kontrolní součty
+//	config/config.json    konfigurační soubor
+//	input/ucastnici.xlsx  vstupy převodu (Excel soubor, texty z administrace, odpovědi z formuláře)
+//	output/data.json      JSON z předchozího převodu
+//	public.json           seznam souborů sestaveného webu s kontrolními součty
+//
+// Sestavený web se kvůli velikosti neukládá celý, jen jeho seznam souborů;
+// obsah nasazených verzí uchovávají artefakty nasazení. Záloha shodná
+// s předchozí se neukládá. Staré zálohy se mažou podle počtu a stáří.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// infoName je název popisu zálohy v archivu. Ukládá se jako první položka,
+// aby výpis záloh nemusel číst celé archivy.
+const infoName = "backup.json"
+
+// archiveExt je přípona archivů se zálohou.
+const archiveExt = ".tar.gz"
+
+// Options určují, kam se zálohy ukládají a jak dlouho se uchovávají.
+type Options struct {
+	Dir    string        // Adresář se zálohami
+	Keep   int           // Počet ponechaných nejnovějších záloh (0 = bez omezení)
+	MaxAge time.Duration // Zálohy starší než tato doba se smažou (0 = bez omezení)
+}
+
+// Item je soubor nebo adresář, který se má zálohovat.
+type Item struct {
+	Kind     string // Druh souboru, podle kterého se v archivu zařadí (config, input, output)
+	Path     string
+	Manifest bool // Z adresáře se uloží jen seznam souborů s kontrolními součty, ne jejich obsah
+}
+
+// Info je popis zálohy.
+type Info struct {
+	ID        string    `json:"id"`        // Identifikátor běhu, před kterým záloha vznikla
+	Command   string    `json:"command"`   // Příkaz, který zálohu vytvořil (run, convert, restore...)
+	CreatedAt time.Time `json:"createdAt"` // Okamžik vytvoření zálohy
+	Files     []File    `json:"files"`     // Zálohované soubory
+	Archive   string    `json:"-"`         // Cesta k archivu; doplní se při čtení
+	Size      int64     `json:"-"`         // Velikost archivu v bajtech; doplní se při čtení
+}
+
+// File je jeden zálohovaný soubor nebo seznam souborů adresáře.
+type File struct {
+	Name     string `json:"name"`               // Cesta v archivu
+	Path     string `json:"path"`               // Původní cesta, kam se soubor obnoví
+	Size     int64  `json:"size"`               // Velikost v bajtech
+	SHA256   string `json:"sha256"`             // Kontrolní součet obsahu
+	Manifest bool   `json:"manifest,omitempty"` // Položka je seznam souborů adresáře, neobnovuje se
+}
+
+// manifestEntry je jeden soubor v seznamu souborů adresáře.
+type manifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// entry je položka připravená k zápisu do archivu.
+type entry struct {
+	file File
+	data []byte      // Obsah položky; nil = obsah se čte ze souboru file.Path
+	mode fs.FileMode // Práva souboru, se kterými se obnoví
+}
+
+// Create uloží zálohu položek items před během id do adresáře opts.Dir a smaže
+// zálohy, které už se podle opts neuchovávají. Položky, které (ještě)
+// neexistují, se vynechají. Vrací cestu k archivu, nebo prázdný řetězec,
+// pokud se nic od poslední zálohy nezměnilo a záloha se neuložila.
+func Create(opts Options, id, command string, items []Item) (string, error) {
+	entries, err := collect(items)
+	if err != nil {
+		return "", err
+	}
+	info := Info{ID: id, Command: command, CreatedAt: time.Now()}
+	for _, e := range entries {
+		info.Files = append(info.Files, e.file)
+	}
+
+	existing, err := List(opts.Dir)
+	if err != nil {
+		return "", err
+	}
+	if n := len(existing); n > 0 && sameFiles(existing[n-1].Files, info.Files) {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+		return "", i18n.Errorf("chyba při vytváření adresáře záloh '%s': %w", opts.Dir, err)
+	}
+	archive := filepath.Join(opts.Dir, id+archiveExt)
+	if err := writeArchive(archive, info, entries); err != nil {
+		return "", i18n.Errorf("chyba při ukládání zálohy '%s': %w", archive, err)
+	}
+	if _, err := Prune(opts); err != nil {
+		return archive, err
+	}
+	return archive, nil
+}
+
+// collect připraví položky archivu: u souborů spočítá kontrolní součet,
+// u adresářů s Manifest sestaví seznam souborů.
+func collect(items []Item) ([]entry, error) {
+	var entries []entry
+	used := map[string]bool{}
+	for _, item := range items {
+		info, err := os.Stat(item.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, i18n.Errorf("chyba při čtení '%s': %w", item.Path, err)
+		}
+
+		var e entry
+		switch {
+		case item.Manifest:
+			data, err := manifest(item.Path)
+			if err != nil {
+				return nil, err
+			}
+			e = entry{file: File{Name: item.Kind + ".json", Path: item.Path, Size: int64(len(data)), SHA256: hashBytes(data), Manifest: true}, data: data, mode: 0o600}
+		case info.Mode().IsRegular():
+			sum, err := hashFile(item.Path)
+			if err != nil {
+				return nil, err
+			}
+			e = entry{file: File{Name: path.Join(item.Kind, filepath.Base(item.Path)), Path: item.Path, Size: info.Size(), SHA256: sum}, mode: info.Mode().Perm()}
+		default:
+			continue
+		}
+
+		// Dva soubory stejného názvu (např. vstup a výstup z různých adresářů)
+		// se v archivu odliší číslem.
+		name := e.file.Name
+		for i := 2; used[e.file.Name]; i++ {
+			ext := path.Ext(name)
+			e.file.Name = strings.TrimSuffix(name, ext) + "-" + strconv.Itoa(i) + ext
+		}
+		used[e.file.Name] = true
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// manifest vrací seznam souborů adresáře s velikostmi a kontrolními součty jako JSON.
+func manifest(dir string) ([]byte, error) {
+	files := []manifestEntry{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		sum, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, manifestEntry{Path: filepath.ToSlash(rel), Size: info.Size(), SHA256: sum})
+		return nil
+	})
+	if err != nil {
+		return nil, i18n.Errorf("chyba při procházení adresáře '%s': %w", dir, err)
+	}
+	return json.MarshalIndent(files, "", "  ")
+}
+
+// writeArchive zapíše archiv zálohy. Archiv vzniká pod dočasným názvem a na
+// konečný se přejmenuje, až je celý zapsaný, takže nedokončená záloha se
+// nikdy neobjeví mezi zálohami.
+func writeArchive(archive string, info Info, entries []entry) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(archive), ".backup-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(tw, infoName, info.CreatedAt, 0o600, &sizedReader{bytes.NewReader(data), int64(len(data))}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		var r io.Reader = bytes.NewReader(e.data)
+		if e.data == nil {
+			f, err := os.Open(e.file.Path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			// Soubor se mezi výpočtem součtu a zápisem mohl změnit; do archivu
+			// se zapíše jen velikost uvedená v popisu zálohy.
+			r = io.LimitReader(f, e.file.Size)
+		}
+		if err := writeEntry(tw, e.file.Name, info.CreatedAt, e.mode, &sizedReader{r, e.file.Size}); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), archive)
+}
+
+// sizedReader je obsah položky archivu se známou velikostí.
+type sizedReader struct {
+	io.Reader
+	size int64
+}
+
+// writeEntry zapíše do archivu jednu položku s právy mode.
+func writeEntry(tw *tar.Writer, name string, modTime time.Time, mode fs.FileMode, r *sizedReader) error {
+	hdr := &tar.Header{Name: name, Mode: int64(mode.Perm()), Size: r.size, ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	n, err := io.Copy(tw, r)
+	if err == nil && n < r.size {
+		err = i18n.Errorf("soubor '%s' se během zálohování zkrátil", name)
+	}
+	return err
+}
+
+// List vrací zálohy v adresáři dir od nejstarší. Chybějící adresář znamená,
+// že žádná záloha není.
+func List(dir string) ([]Info, error) {
+	names, err := archives(dir)
+	if err != nil {
+		return nil, err
+	}
+	var infos []Info
+	for _, name := range names {
+		info, err := readInfo(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// archives vrací názvy archivů se zálohou v adresáři dir. Název začíná
+// časem vytvoření, seřazené názvy jsou tedy seřazené i podle času.
+func archives(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba při čtení adresáře záloh '%s': %w", dir, err)
+	}
+	var names []string
+	for _, d := range dirEntries {
+		if d.Type().IsRegular() && strings.HasSuffix(d.Name(), archiveExt) {
+			names = append(names, d.Name())
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// readInfo načte popis zálohy z archivu.
+func readInfo(archive string) (Info, error) {
+	var info Info
+	err := readArchive(archive, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		if hdr.Name != infoName {
+			return false, nil
+		}
+		return true, json.NewDecoder(r).Decode(&info)
+	})
+	if err == nil && info.ID == "" {
+		err = i18n.Errorf("archiv neobsahuje %s", infoName)
+	}
+	if err != nil {
+		return Info{}, i18n.Errorf("chyba při čtení zálohy '%s': %w", archive, err)
+	}
+	info.Archive = archive
+	if st, err := os.Stat(archive); err == nil {
+		info.Size = st.Size()
+	}
+	return info, nil
+}
+
+// readArchive zavolá fn pro každou položku archivu, dokud fn nevrátí true nebo chybu.
+func readArchive(archive string, fn func(hdr *tar.Header, r io.Reader) (bool, error)) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if done, err := fn(hdr, tr); done || err != nil {
+			return err
+		}
+	}
+}
+
+// Find vrací zálohu s identifikátorem id z adresáře dir.
+func Find(dir, id string) (Info, error) {
+	archive := filepath.Join(dir, id+archiveExt)
+	if id == "" || filepath.Base(id) != id {
+		return Info{}, i18n.Errorf("neplatný identifikátor zálohy '%s'", id)
+	}
+	if _, err := os.Stat(archive); err != nil {
+		return Info{}, i18n.Errorf("záloha '%s' v adresáři '%s' není", id, dir)
+	}
+	return readInfo(archive)
+}
+
+// Restore obnoví soubory ze zálohy info na jejich původní místa. Seznamy
+// souborů adresářů se neobnovují. Každý soubor se zapíše pod dočasným názvem
+// a na původní se přejmenuje, až souhlasí jeho kontrolní součet. Vrací
+// obnovené soubory.
+func Restore(info Info) ([]File, error) {
+	files := map[string]File{}
+	for _, f := range info.Files {
+		if !f.Manifest {
+			files[f.Name] = f
+		}
+	}
+
+	var restored []File
+	err := readArchive(info.Archive, func(hdr *tar.Header, r io.Reader) (bool, error) {
+		f, ok := files[hdr.Name]
+		if !ok {
+			return false, nil
+		}
+		if err := restoreFile(f, hdr.FileInfo().Mode().Perm(), r); err != nil {
+			return true, err
+		}
+		restored = append(restored, f)
+		return false, nil
+	})
+	if err != nil {
+		return restored, i18n.Errorf("chyba při obnově zálohy '%s': %w", info.ID, err)
+	}
+	return restored, nil
+}
+
+// restoreFile zapíše obsah r na původní místo souboru f s právy mode
+// z archivu (dočasný soubor vzniká jen s právy 0600).
+func restoreFile(f File, mode fs.FileMode, r io.Reader) (err error) {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), ".restore-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), r); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != f.SHA256 {
+		return i18n.Errorf("kontrolní součet souboru '%s' nesouhlasí, záloha je poškozená", f.Name)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+// Prune smaže zálohy nad počet opts.Keep a starší než opts.MaxAge. Nejnovější
+// záloha se nesmaže nikdy. Vrací cesty smazaných archivů.
+func Prune(opts Options) ([]string, error) {
+	names, err := archives(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for i, name := range names[:max(len(names)-1, 0)] {
+		archive := filepath.Join(opts.Dir, name)
+		expired := opts.Keep > 0 && len(names)-i > opts.Keep
+		if !expired && opts.MaxAge > 0 {
+			st, err := os.Stat(archive)
+			expired = err == nil && time.Since(st.ModTime()) > opts.MaxAge
+		}
+		if !expired {
+			continue
+		}
+		if err := os.Remove(archive); err != nil {
+			return removed, i18n.Errorf("chyba při mazání staré zálohy '%s': %w", archive, err)
+		}
+		removed = append(removed, archive)
+	}
+	return removed, nil
+}
+
+// sameFiles vrací true, pokud mají obě zálohy stejné soubory se stejným obsahem.
+func sameFiles(a, b []File) bool {
+	return slices.EqualFunc(a, b, func(x, y File) bool {
+		return x.Path == y.Path && x.SHA256 == y.SHA256
+	})
+}
+
+// hashFile vrací kontrolní součet SHA-256 obsahu souboru.
+func hashFile(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", i18n.Errorf("chyba při otevírání souboru '%s': %w", filePath, err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", i18n.Errorf("chyba při čtení souboru '%s': %w", filePath, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashBytes vrací kontrolní součet SHA-256 dat.
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
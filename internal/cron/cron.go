@@ -0,0 +1,170 @@
+// Package cron počítá časy spuštění podle výrazů ve tvaru systémového cronu.
+//
+// Výraz má pět polí oddělených mezerami: minuta (0-59), hodina (0-23),
+// den v měsíci (1-31), měsíc (1-12 nebo jan-dec) a den v týdnu (0-7 nebo
+// sun-sat, 0 i 7 je neděle). Každé pole je "*", číslo, rozsah "8-21",
+// seznam "1,15,30" nebo krok "*/15" či "8-21/2". Například "*/15 8-21 * * *"
+// znamená každých 15 minut mezi 8:00 a 21:45. Časy se počítají v místním čase.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// Schedule je rozparsovaný výraz cronu. Každé pole je bitová maska povolených hodnot.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool // Pole obsahuje "*"; pro kombinaci dne v měsíci a v týdnu
+}
+
+// field popisuje rozsah jednoho pole výrazu.
+type field struct {
+	name     string
+	min, max int
+	names    []string // Názvy hodnot od min (např. měsíce), nebo nil
+}
+
+var (
+	minuteField = field{name: "minuta", min: 0, max: 59}
+	hourField   = field{name: "hodina", min: 0, max: 23}
+	domField    = field{name: "den v měsíci", min: 1, max: 31}
+	monthField  = field{name: "měsíc", min: 1, max: 12,
+		names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dowField = field{name: "den v týdnu", min: 0, max: 7,
+		names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// Parse rozparsuje výraz cronu s pěti poli.
+func Parse(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, i18n.Errorf("výraz '%s' má mít 5 polí (minuta hodina den měsíc den-v-týdnu), má %d", expr, len(fields))
+	}
+
+	s := &Schedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	for i, target := range []struct {
+		mask *uint64
+		f    field
+	}{
+		{&s.minute, minuteField},
+		{&s.hour, hourField},
+		{&s.dom, domField},
+		{&s.month, monthField},
+		{&s.dow, dowField},
+	} {
+		if *target.mask, err = parseField(fields[i], target.f); err != nil {
+			return nil, i18n.Errorf("výraz '%s': %w", expr, err)
+		}
+	}
+	// Neděle se smí zapsat jako 0 i jako 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	if s.Next(time.Now()).IsZero() {
+		return nil, i18n.Errorf("výraz '%s' neodpovídá žádnému času", expr)
+	}
+	return s, nil
+}
+
+// parseField převede jedno pole výrazu na bitovou masku povolených hodnot.
+func parseField(text string, f field) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(text, ",") {
+		rangeText, step := part, 1
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n < 1 {
+				return 0, i18n.Errorf("%s: neplatný krok '%s'", f.name, after)
+			}
+			rangeText, step = before, n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangeText == "*":
+		case strings.Contains(rangeText, "-"):
+			a, b, _ := strings.Cut(rangeText, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, i18n.Errorf("%s: rozsah '%s' je obrácený", f.name, rangeText)
+			}
+		default:
+			v, err := f.value(rangeText)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// Samotné číslo je jediná hodnota, s krokem (např. "5/10") platí až do maxima.
+			if step == 1 {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << v
+		}
+	}
+	return mask, nil
+}
+
+// value převede číslo nebo název na hodnotu pole a ověří její rozsah.
+func (f field) value(text string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(text, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, i18n.Errorf("%s: neplatná hodnota '%s'", f.name, text)
+	}
+	if v < f.min || v > f.max {
+		return 0, i18n.Errorf("%s: hodnota %d je mimo rozsah %d-%d", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next vrací první čas po t, který výrazu odpovídá, s přesností na minuty.
+// Nevyhovující měsíc, den nebo hodinu přeskočí celé. Pokud výraz neodpovídá
+// žádnému času v příštích pěti letech (např. 31. února), vrací nulový čas.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches ověří den v měsíci a den v týdnu. Jsou-li omezená obě pole,
+// stačí shoda jednoho z nich, stejně jako u systémového cronu.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	if !s.domAny && !s.dowAny {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -0,0 +1,23 @@
+// Package defaults obsahuje výchozí soubory vložené do programu, aby k nasazení
+// stačilo zkopírovat jediný soubor. Příkaz export-defaults je zapíše na disk,
+// kde je lze upravit.
+package defaults
+
+import _ "embed"
+
+// Config je šablona konfiguračního souboru s obvyklými hodnotami. Přihlašovací
+// údaje jsou v ní jako proměnné prostředí (${FTP_PASSWORD}), klíč "$schema"
+// odkazuje na schéma zapsané vedle ní.
+//
+//go:embed config.json
+var Config []byte
+
+// Invitation je šablona pozvánky pro příkaz mail (viz mail --template).
+// Použije se, pokud příkaz nedostane vlastní šablonu.
+//
+//go:embed pozvanka.txt
+var Invitation []byte
+
+// InvitationName je název, pod kterým se šablona pozvánky zapíše na disk.
+// Bez přípony je to i výchozí název kampaně.
+const InvitationName = "pozvanka.txt"
@@ -0,0 +1,26 @@
+// Package dryrun řídí zkušební běh. S globálním přepínačem --dry-run fáze jen
+// ověří vstupy a popíšou, co by udělaly: převod nezapíše JSON, sestavení nespustí
+// Hugo a nasazení vypíše plán bez připojení k serveru. Nic se nezapíše na disk
+// ani na server.
+package dryrun
+
+import "sync"
+
+var (
+	mu      sync.Mutex
+	enabled bool
+)
+
+// Enable zapne zkušební běh.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+}
+
+// Enabled vrací true, pokud jde o zkušební běh.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
@@ -0,0 +1,63 @@
+// Package exitcode určuje návratové kódy programu podle druhu chyby,
+// aby skripty a CI mohly reagovat na třídu selhání bez čtení logu.
+//
+//	0  úspěch
+//	1  jiná chyba
+//	2  chybné použití (neznámý příkaz nebo přepínač)
+//	3  chyba konfigurace (soubor chybí, nejde přečíst nebo neprošel kontrolou)
+//	4  chyba dat (vstupní Excel soubor nebo obsah k nasazení neprošel kontrolou)
+//	5  sestavení webu Hugem selhalo
+//	6  nasazení selhalo úplně nebo zčásti (podrobnosti jsou v reportu)
+//	7  není co dělat (např. vzorům --only neodpovídá žádný soubor)
+//	8  přerušeno signálem SIGINT nebo SIGTERM (Ctrl-C, časový limit CI)
+//	9  běh doběhl, ale některé kroky selhaly (podrobnosti jsou v souhrnu chyb)
+package exitcode
+
+import "errors"
+
+// Code je návratový kód programu.
+type Code int
+
+// Návratové kódy programu.
+const (
+	OK          Code = 0
+	Failure     Code = 1
+	Usage       Code = 2
+	Config      Code = 3
+	Data        Code = 4
+	Build       Code = 5
+	Deploy      Code = 6
+	NothingToDo Code = 7
+	Cancelled   Code = 8
+	Problems    Code = 9
+)
+
+// codedError je chyba s přiřazeným návratovým kódem.
+type codedError struct {
+	code Code
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// With přiřadí chybě návratový kód. Pro nil vrací nil.
+func With(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// From vrací návratový kód chyby: OK pro nil, kód přiřazený funkcí With
+// (i když je chyba dál obalená), jinak Failure.
+func From(err error) Code {
+	if err == nil {
+		return OK
+	}
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return Failure
+}
@@ -0,0 +1,208 @@
+// Package hooks spouští háčky připojené k fázím pipeline (klíč hooks v konfiguraci).
+//
+// Háček je externí příkaz, nebo adresa, na kterou se odešle POST. Oba dostanou
+// JSON typu Payload: příkaz na standardní vstup, adresa jako tělo požadavku.
+// Háčky pre běží před fází, háčky post po ní, i po neúspěšné, a dostanou
+// výsledek fáze ve stejném tvaru jako výpis --json. Háčky slouží ke krokům
+// specifickým pro konkrétní web, např. vyprázdnění mezipaměti proxy nebo
+// oznámení botovi, bez úprav programu.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"hugo72/internal/dryrun"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/problems"
+	"hugo72/internal/profile"
+	"hugo72/internal/runid"
+	"hugo72/pkg/config"
+)
+
+// Okamžiky, ve kterých se háčky fáze spouští.
+const (
+	Pre  = "pre"  // Před fází
+	Post = "post" // Po fázi
+)
+
+// Chování při selhání háčku.
+const (
+	onFailureAbort    = "abort"    // Příkaz skončí chybou, další háčky se nespustí (výchozí)
+	onFailureContinue = "continue" // Selhání se jen zapíše do logu
+)
+
+// defaultTimeout je nejdelší doba běhu háčku bez nastaveného timeout.
+const defaultTimeout = 30 * time.Second
+
+// stopTimeout je doba, kterou příkaz háčku dostane na ukončení po vypršení času.
+const stopTimeout = 5 * time.Second
+
+// Payload je JSON, který háček dostane.
+//
+// Příklad pro háček post fáze deploy:
+//
+//	{
+//	  "phase": "deploy",
+//	  "stage": "post",
+//	  "time": "2025-06-01T10:00:00+02:00",
+//	  "runId": "20250601-100000-3f9a2c1b",
+//	  "ok": true,
+//	  "result": {"generatedAt": "...", "targets": [...]}
+//	}
+type Payload struct {
+	Phase  string    `json:"phase"` // convert, build nebo deploy
+	Stage  string    `json:"stage"` // Pre nebo Post
+	Time   time.Time `json:"time"`
+	RunID  string    `json:"runId,omitempty"`  // Identifikátor běhu (viz runid)
+	OK     *bool     `json:"ok,omitempty"`     // Zda fáze uspěla (jen post)
+	Error  string    `json:"error,omitempty"`  // Chyba fáze (jen post)
+	Result any       `json:"result,omitempty"` // Výsledek fáze jako ve výpisu --json (jen post)
+}
+
+// Run spustí háčky v zadaném pořadí a každému předá payload. Selhání háčku
+// s onFailure "continue" jen zapíše do logu a do souhrnu problémů běhu; první selhání háčku s "abort"
+// další háčky přeskočí a vrátí se jako chyba. Při zkušebním běhu se háčky
+// jen vypíšou do logu.
+func Run(ctx context.Context, hooks []config.Hook, payload Payload) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	logger := logging.Phase(payload.Phase).With("stage", payload.Stage)
+	if payload.Time.IsZero() {
+		payload.Time = time.Now()
+	}
+	if payload.RunID == "" {
+		payload.RunID = runid.Current()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci dat háčku: %w", err)
+	}
+
+	for i, hook := range hooks {
+		name := hookName(hook)
+		if dryrun.Enabled() {
+			logger.Info("Zkušební běh, háček se nespustí.", "hook", name)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		started := time.Now()
+		err := run(ctx, hook, payload, body)
+		profile.Observe(payload.Phase, fmt.Sprintf("hooks.%s[%d] (%s)", payload.Stage, i, name), time.Since(started), 0)
+		if err == nil {
+			logger.Debug("Háček proběhl.", "hook", name, "duration", time.Since(started).Round(time.Millisecond))
+			continue
+		}
+		if hook.OnFailure == onFailureContinue {
+			logger.Warn("Háček selhal, pokračuji.", "hook", name, "error", err)
+			problems.Warn(payload.Phase, fmt.Sprintf("hooks.%s.%s[%d] (%s)", payload.Phase, payload.Stage, i, name), err)
+			continue
+		}
+		logger.Error("Háček selhal.", "hook", name, "error", err)
+		return i18n.Errorf("háček hooks.%s.%s[%d] (%s) selhal: %w", payload.Phase, payload.Stage, i, name, err)
+	}
+	return nil
+}
+
+// run spustí jeden háček s jeho časovým limitem.
+func run(ctx context.Context, hook config.Hook, payload Payload, body []byte) error {
+	timeout := defaultTimeout
+	if hook.Timeout != "" {
+		// Hodnota je ověřená při načtení konfigurace.
+		timeout, _ = time.ParseDuration(hook.Timeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if hook.URL != "" {
+		return post(ctx, hook.URL, body)
+	}
+	return command(ctx, hook.Command, payload, body)
+}
+
+// command spustí příkaz háčku s payloadem na standardním vstupu. Fázi, okamžik
+// a identifikátor běhu dostane příkaz i v proměnných prostředí HUGO72_PHASE,
+// HUGO72_STAGE a HUGO72_RUN_ID. Výstup
+// příkazu se zapíše do logu na úrovni debug, při selhání je jeho konec v chybě.
+func command(ctx context.Context, args []string, payload Payload, body []byte) error {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(), "HUGO72_PHASE="+payload.Phase, "HUGO72_STAGE="+payload.Stage, "HUGO72_RUN_ID="+payload.RunID)
+	cmd.WaitDelay = stopTimeout
+	out, err := cmd.CombinedOutput()
+	if len(out) > 0 {
+		logging.Phase(payload.Phase).Debug("Výstup háčku.", "hook", args[0], "output", string(out))
+	}
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.New(i18n.T("vypršel čas háčku"))
+	}
+	if last := lastLine(out); last != "" {
+		return fmt.Errorf("%w: %s", err, last)
+	}
+	return err
+}
+
+// post odešle payload metodou POST jako JSON. Odpověď mimo rozsah 2xx je
+// považována za chybu. Adresa se do chyby nevypisuje, protože může obsahovat
+// tajný token.
+func post(ctx context.Context, target string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return errors.New(i18n.T("neplatná adresa háčku"))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hugo72")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.New(i18n.T("vypršel čas háčku"))
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return i18n.Errorf("chyba při odesílání háčku: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return i18n.Errorf("služba vrátila stav %s", resp.Status)
+	}
+	return nil
+}
+
+// hookName vrací označení háčku pro log: název programu, nebo server adresy
+// (celá adresa může obsahovat tajný token).
+func hookName(hook config.Hook) string {
+	if hook.URL == "" {
+		return hook.Command[0]
+	}
+	if u, err := url.Parse(hook.URL); err == nil {
+		return u.Host
+	}
+	return "url"
+}
+
+// lastLine vrací poslední neprázdný řádek výstupu příkazu.
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
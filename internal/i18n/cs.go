@@ -0,0 +1,21 @@
+package i18n
+
+// cs je katalog českých překladů anglických hlášení (fáze build).
+var cs = map[string]string{
+	"Running hugo":                                     "Spouštím hugo.",
+	"hugo build cancelled: %w":                         "sestavení hugem přerušeno: %w",
+	"hugo build failed: %w":                            "sestavení hugem selhalo: %w",
+	"Hugo build succeeded":                             "Sestavení hugem proběhlo úspěšně.",
+	"artifact cancelled: %w":                           "vytváření artefaktu přerušeno: %w",
+	"failed to create artifact: %w":                    "artefakt se nepodařilo vytvořit: %w",
+	"Artifact written":                                 "Artefakt zapsán.",
+	"badges cancelled: %w":                             "vytváření jmenovek přerušeno: %w",
+	"failed to write badges: %w":                       "jmenovky se nepodařilo zapsat: %w",
+	"Badges written":                                   "Jmenovky zapsány.",
+	"site directory %s not found":                      "adresář webu %s nebyl nalezen",
+	"hugo not found: %w":                               "program hugo nebyl nalezen: %w",
+	"no Hugo config (hugo.toml, hugo.yaml, ...) in %s": "v %s chybí konfigurace Huga (hugo.toml, hugo.yaml, ...)",
+	"Dry run, would run hugo":                          "Zkušební běh, hugo by se spustil.",
+	"Dry run, would pack public/ into artifact":        "Zkušební běh, public/ by se zabalil do artefaktu.",
+	"Dry run, would write badges":                      "Zkušební běh, jmenovky by se zapsaly.",
+}
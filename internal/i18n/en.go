@@ -0,0 +1,687 @@
+package i18n
+
+// en je katalog anglických překladů českých hlášení.
+var en = map[string]string{
+	// Program hugo72: nápověda a globální přepínače
+	"převede Excel soubor na JSON (fáze 1)":                                          "converts the Excel file to JSON (phase 1)",
+	"sestaví web Hugem (fáze 2)":                                                     "builds the site with Hugo (phase 2)",
+	"nasadí web na FTP nebo SFTP server (fáze 3)":                                    "deploys the site to the FTP or SFTP server (phase 3)",
+	"znovu nasadí artefakt ověřený na jiném cíli":                                    "redeploys an artifact verified on another target",
+	"vypíše historii nasazení (deploys list)":                                        "lists the deploy history (deploys list)",
+	"spustí převod, sestavení a nasazení za sebou":                                   "runs convert, build and deploy in sequence",
+	"po každé změně Excel souboru spustí převod, sestavení a nasazení":               "runs convert, build and deploy after every change of the Excel file",
+	"HTTP server, jehož požadavky spouští převod, sestavení a nasazení":              "HTTP server whose requests run convert, build and deploy",
+	"vypíše historii běhů (history list, history show <číslo>)":                      "lists the run history (history list, history show <number>)",
+	"rozešle účastníkům pozvánky nebo zprávy e-mailem":                               "e-mails invitations or updates to attendees",
+	"eviduje příchody účastníků v den akce (checkin scan, status, export)":           "records attendee arrivals on the event day (checkin scan, status, export)",
+	"vytvoří klíč nebo zašifruje hodnotu do konfigurace":                             "creates a key or encrypts a value for the configuration",
+	"průvodce nastavením nového projektu":                                            "setup wizard for a new project",
+	"ověří konfiguraci (config validate) nebo vypíše její schéma":                    "validates the configuration (config validate) or prints its schema",
+	"ověří vše, co běh potřebuje, včetně přihlášení k serverům":                      "checks everything a run needs, including login to the servers",
+	"vypíše zálohy před běhy nebo jednu obnoví (backups list, backups restore <id>)": "lists the pre-run backups or restores one (backups list, backups restore <id>)",
+	"cesta ke konfiguračnímu souboru (JSON, YAML nebo TOML); výchozí je config.json, config.yaml, config.yml nebo config.toml v adresáři projektu": "path to the configuration file (JSON, YAML or TOML); defaults to config.json, config.yaml, config.yml or config.toml in the project directory",
+	"profil konfigurace, např. dev, staging nebo prod (výchozí z HUGO72_ENV)":                                                                      "configuration profile, e.g. dev, staging or prod (default from HUGO72_ENV)",
+	"soubor s proměnnými prostředí, které se nastaví před načtením konfigurace":                                                                    "file with environment variables set before the configuration is loaded",
+	"formát logu: text nebo json (pro sběr logů v CI)":                                                                                             "log format: text or json (for log collection in CI)",
+	"nejnižší vypisovaná úroveň logu: debug, info, warn nebo error":                                                                                "lowest log level printed: debug, info, warn or error",
+	"soubor, do kterého se log zapisuje navíc (denně a po dosažení velikosti se odloží)":                                                           "file the log is additionally written to (rotated daily and when it reaches the size limit)",
+	"velikost souboru logu v MB, po které se odloží a začne nový":                                                                                  "log file size in MB after which it is rotated",
+	"počet ponechaných odložených souborů logu":                                                                                                    "number of rotated log files to keep",
+	"počet dní, po kterých se odložené soubory logu smažou":                                                                                        "number of days after which rotated log files are deleted",
+	"vypisovat podrobný průběh (úroveň logu debug)":                                                                                                "print detailed progress (log level debug)",
+	"vypisovat jen chyby (soubor logu dostává dál vše podle --log-level)":                                                                          "print errors only (the log file still receives everything according to --log-level)",
+	"po skončení vypsat výsledek příkazu jako JSON na standardní výstup":                                                                           "print the command result as JSON to standard output when done",
+	"zkušební běh: fáze jen vypíšou, co by udělaly, nic nezapíšou na disk ani na server":                                                           "dry run: phases only print what they would do and write nothing to disk or the server",
+	"soubor, do kterého se po běhu zapíše profil doby jednotlivých kroků (JSON)":                                                                   "file the per-step timing profile is written to after the run (JSON)",
+	"adresář, do kterého se zapíšou profily procesoru a paměti pro go tool pprof":                                                                  "directory the CPU and memory profiles for go tool pprof are written to",
+	"Chyba: přepínače --verbose a --quiet nelze použít současně":                                                                                   "Error: flags --verbose and --quiet cannot be used together",
+	"Chyba: %v\n":                    "Error: %v\n",
+	"Konfigurace načtena.":           "Configuration loaded.",
+	"Výsledek příkazu nelze vypsat.": "Cannot print the command result.",
+	"Neznámý příkaz: %s\n\n":         "Unknown command: %s\n\n",
+	"Příkaz skončil chybou.":         "Command failed.",
+	"Použití: hugo72 [--config config.json] [--env profil] [--env-file .env] <příkaz> [přepínače]": "Usage: hugo72 [--config config.json] [--env profile] [--env-file .env] <command> [flags]",
+	"\nPříkazy:":   "\nCommands:",
+	"\nPřepínače:": "\nFlags:",
+
+	// Stav fáze, běhu nebo nasazení v logu a ve výpisech
+	"chyba":      "error",
+	"varování":   "warning",
+	"přeskočeno": "skipped",
+	" (část)":    " (partial)",
+
+	// Program hugo72: příkazy run, watch a serve
+	"fáze, kterou běh začne (convert, build nebo deploy)":                         "phase the run starts with (convert, build or deploy)",
+	"fáze, po které běh skončí (convert, build nebo deploy)":                      "phase the run ends after (convert, build or deploy)",
+	"pokračovat první fází, která naposledy selhala nebo jejíž vstupy se změnily": "resume from the first phase that failed last time or whose inputs changed",
+	"spustit i fáze, u kterých se nic nezměnilo":                                  "run phases even if nothing changed",
+	"neznámá fáze '%s'":                             "unknown phase '%s'",
+	"fáze '%s' následuje až po fázi '%s'":           "phase '%s' comes after phase '%s'",
+	"Všechny fáze jsou aktuální, není co spouštět.": "All phases are up to date, nothing to run.",
+	"Pokračuji od této fáze.":                       "Resuming from this phase.",
+	"Fáze je aktuální, přeskakuji.":                 "Phase is up to date, skipping.",
+	"Běh se nepodařilo zapsat do historie.":         "Failed to write the run to the history.",
+	"běh přerušen před fází %s: %w":                 "run cancelled before phase %s: %w",
+	"Vstupy ani konfigurace se od posledního úspěšného běhu nezměnily, fázi přeskakuji.": "Neither inputs nor configuration changed since the last successful run, skipping phase.",
+	"Spouštím fázi.":                                                               "Starting phase.",
+	"Stav běhu se nepodařilo uložit.":                                              "Failed to save the run state.",
+	"fáze %s selhala: %w":                                                          "phase %s failed: %w",
+	"Souhrn fáze.":                                                                 "Phase summary.",
+	"Souhrn běhu.":                                                                 "Run summary.",
+	"Souhrn problémů běhu:":                                                        "Run problem summary:",
+	"FÁZE\tPOLOŽKA\tÚROVEŇ\tCHYBA":                                                 "PHASE\tITEM\tLEVEL\tERROR",
+	"Problém běhu.":                                                                "Run problem.",
+	"běh doběhl, ale některé kroky selhaly:\n%w":                                   "run finished, but some steps failed:\n%w",
+	"chyba při serializaci stavu běhu: %w":                                         "error serializing run state: %w",
+	"chyba při zápisu stavu běhu '%s': %w":                                         "error writing run state '%s': %w",
+	"Běh vyvolaný požadavkem selhal.":                                              "Run triggered by request failed.",
+	"počkat na dokončení jiného běhu místo ukončení s chybou":                      "wait for another run to finish instead of failing",
+	"Přebírám zámek běhu, proces, který ho vytvořil, už neběží.":                   "Taking over the run lock, the process that created it is no longer running.",
+	"chyba při odstraňování zámku '%s': %w":                                        "error removing lock '%s': %w",
+	"jiný běh již probíhá: %s (PID %d) drží zámek '%s' od %s":                      "another run is already in progress: %s (PID %d) has held lock '%s' since %s",
+	"Čekám na dokončení jiného běhu.":                                              "Waiting for another run to finish.",
+	"čekání na zámek běhu přerušeno: %w":                                           "waiting for the run lock cancelled: %w",
+	"chyba při vytváření zámku '%s': %w":                                           "error creating lock '%s': %w",
+	"Zámek běhu se nepodařilo uvolnit.":                                            "Failed to release the run lock.",
+	"Oznámení se nepodařilo odeslat.":                                              "Failed to send the notification.",
+	"jak často kontrolovat změnu souboru":                                          "how often to check the file for changes",
+	"prodleva od poslední změny souboru před spuštěním publikace":                  "delay after the last file change before publishing starts",
+	"nejdelší prodleva před opakováním neúspěšné publikace":                        "longest delay before retrying a failed publish",
+	"adresa, na které je dostupný stav běhů (např. 127.0.0.1:8072)":                "address serving the run status (e.g. 127.0.0.1:8072)",
+	"konfigurace neobsahuje sekci phase1, není co sledovat":                        "configuration has no phase1 section, nothing to watch",
+	"Sleduji vstupní soubor.":                                                      "Watching the input file.",
+	"Naplánován běh.":                                                              "Run scheduled.",
+	"Sledování ukončeno.":                                                          "Watching stopped.",
+	"Vstupní soubor se změnil.":                                                    "Input file changed.",
+	"Spouštím naplánovaný běh.":                                                    "Starting scheduled run.",
+	"Publikace selhala, další pokus proběhne později.":                             "Publishing failed, will retry later.",
+	"Publikace dokončena, sleduji další změny.":                                    "Publishing done, watching for further changes.",
+	"Publikace dokončena s chybami, sleduji další změny.":                          "Publishing done with errors, watching for further changes.",
+	"adresa, na které server naslouchá (výchozí server.listen z konfigurace nebo ": "address the server listens on (default server.listen from the configuration or ",
+	"konfigurace neobsahuje server.token, bez něj nelze server spustit":            "configuration has no server.token, the server cannot start without it",
+	"chyba při spouštění serveru na '%s': %w":                                      "error starting the server on '%s': %w",
+	"Server naslouchá.":                                                            "Server listening.",
+	"chyba serveru: %w":                                                            "server error: %w",
+	"Server ukončen.":                                                              "Server stopped.",
+	"Přijat požadavek na běh.":                                                     "Run request received.",
+	"Odmítnut požadavek bez platného tokenu.":                                      "Rejected request without a valid token.",
+	"chybí nebo je neplatný token":                                                 "missing or invalid token",
+	"poslední úspěšný běh před %s, limit je %s":                                    "last successful run %s ago, the limit is %s",
+	"poslední úspěšný běh před %s":                                                 "last successful run %s ago",
+	"Přijat signál, ukončuji příkaz (dalším signálem se program ukončí okamžitě).": "Signal received, stopping the command (another signal exits immediately).",
+	"Přijat další signál, program končí okamžitě.":                                 "Another signal received, exiting immediately.",
+	"Historii běhů nelze načíst.":                                                  "Cannot read the run history.",
+	"Texty webu upraveny v administraci.":                                          "Site texts edited in the admin page.",
+	"Neúspěšné přihlášení do administrace.":                                        "Failed admin login.",
+	"Odpověď z formuláře nelze uložit.":                                            "Cannot save the form response.",
+	"Přijata odpověď z formuláře.":                                                 "Form response received.",
+	"Texty jsou uložené, publikace webu běží.":                                     "The texts are saved, the site is being published.",
+	"Web znovu používá texty z Excel souboru, publikace webu běží.":                "The site uses the texts from the Excel file again, the site is being published.",
+	"Požadavek z cizí stránky byl odmítnut.":                                       "A request from another site was rejected.",
+	"Pro vstup do administrace se přihlaste.":                                      "Log in to access the admin page.",
+	"Neznámá akce.":                                                                "Unknown action.",
+	"Nadpis je příliš dlouhý.":                                                     "The heading is too long.",
+	"Zpráva je příliš dlouhá.":                                                     "The message is too long.",
+	"Odkaz z pozvánky patří k jiné e-mailové adrese nebo je neplatný.":             "The invitation link belongs to a different e-mail address or is invalid.",
+	"Vyplňte prosím jméno.":                                                        "Please enter your name.",
+	"Vyplňte prosím platný e-mail.":                                                "Please enter a valid e-mail.",
+	"Vyberte prosím, zda přijdete.":                                                "Please choose whether you will come.",
+	"Tato adresa už v seznamu je. Odpověď změníte odkazem z pozvánky.":             "This address is already on the list. Use the link from your invitation to change the response.",
+	"Odpověď z této adresy jsme právě přijali, zkuste to prosím za chvíli.":        "We have just received a response from this address, please try again in a moment.",
+	"Odpověď se nepodařilo uložit, zkuste to prosím později.":                      "The response could not be saved, please try again later.",
+
+	// Program hugo72: profil běhu
+	"Profil běhu se nepodařilo zapsat.":                   "Failed to write the run profile.",
+	"Profil kroku.":                                       "Step profile.",
+	"Profil běhu (celkem %s):\n":                          "Run profile (total %s):\n",
+	"FÁZE\tKROK\tPOČET\tCELKEM\tPRŮMĚR\tMAX\tPODÍL\tDATA": "PHASE\tSTEP\tCOUNT\tTOTAL\tAVERAGE\tMAX\tSHARE\tDATA",
+	"(celá fáze)":                                         "(whole phase)",
+	"chyba při vytváření profilu procesoru: %w":           "error creating the CPU profile: %w",
+	"chyba při spuštění profilu procesoru: %w":            "error starting the CPU profile: %w",
+	"Profil paměti se nepodařilo zapsat.":                 "Failed to write the memory profile.",
+	"Profily pprof uloženy.":                              "pprof profiles saved.",
+
+	// Program hugo72: příkaz history
+	"chyba při otevírání historie běhů '%s': %w":                           "error opening run history '%s': %w",
+	"chyba při zápisu historie běhů '%s': %w":                              "error writing run history '%s': %w",
+	"chyba v historii běhů '%s' na řádku %d: %w":                           "error in run history '%s' on line %d: %w",
+	"chyba při čtení historie běhů '%s': %w":                               "error reading run history '%s': %w",
+	"počet vypsaných běhů (0 = všechny)":                                   "number of runs to list (0 = all)",
+	"použití: history show <číslo>":                                        "usage: history show <number>",
+	"'%s' není číslo běhu":                                                 "'%s' is not a run number",
+	"běh číslo %d v historii není":                                         "run number %d is not in the history",
+	"neznámý příkaz: history %s":                                           "unknown command: history %s",
+	"Č.\tČAS\tSPUŠTĚNÍ\tUŽIVATEL\tVÝSLEDEK\tFÁZE\tDOBA\tPŘIHLÁŠKY\tCOMMIT": "NO.\tTIME\tTRIGGER\tUSER\tRESULT\tPHASES\tDURATION\tRECORDS\tCOMMIT",
+	"Běh:\t%d\n":                   "Run:\t%d\n",
+	"Začátek:\t%s\n":               "Started:\t%s\n",
+	"Doba:\t%s\n":                  "Duration:\t%s\n",
+	"Spuštění:\t%s\n":              "Trigger:\t%s\n",
+	"Uživatel:\t%s@%s\n":           "User:\t%s@%s\n",
+	"Adresář:\t%s\n":               "Directory:\t%s\n",
+	"Profil:\t%s\n":                "Profile:\t%s\n",
+	"Výsledek:\tok\n":              "Result:\tok\n",
+	"Výsledek:\tchyba: %s\n":       "Result:\terror: %s\n",
+	"Přihlášky:\t%d":               "Records:\t%d",
+	" (přijde %d)":                 " (%d attending)",
+	"Nahráno:\t%d souborů, %d B\n": "Uploaded:\t%d files, %d B\n",
+	"FÁZE\tVÝSLEDEK\tDOBA\tCHYBA":  "PHASE\tRESULT\tDURATION\tERROR",
+
+	// Program hugo72: příkaz backups
+	"Zálohu před během se nepodařilo uložit.":                         "Failed to save the pre-run backup.",
+	"Soubory se od poslední zálohy nezměnily, zálohu neukládám.":      "Files have not changed since the last backup, not saving a backup.",
+	"Záloha před během uložena.":                                      "Pre-run backup saved.",
+	"Zkušební běh, soubor by se obnovil.":                             "Dry run, the file would be restored.",
+	"Současný stav zálohován.":                                        "Current state backed up.",
+	"Soubor obnoven ze zálohy.":                                       "File restored from the backup.",
+	"použití: backups restore <identifikátor>":                        "usage: backups restore <id>",
+	"neznámý příkaz: backups %s":                                      "unknown command: backups %s",
+	"současný stav se nepodařilo zálohovat, obnova se neprovedla: %w": "failed to back up the current state, nothing was restored: %w",
+	"Obnoveno souborů: %d ze zálohy %s. Web obnoví hugo72 run.\n":     "Restored %d files from backup %s. hugo72 run restores the site.\n",
+	"ZÁLOHA\tČAS\tPŘÍKAZ\tVELIKOST\tSOUBORY":                          "BACKUP\tTIME\tCOMMAND\tSIZE\tFILES",
+
+	// Program hugo72: příkaz doctor
+	"nepřipojovat se k cílům nasazení":      "do not connect to deploy targets",
+	"kontrola prostředí zjistila chyby: %d": "environment check found errors: %d",
+	"konfigurace": "configuration",
+	"Opravte uvedené hodnoty; podrobnosti vypíše hugo72 config validate.": "Fix the listed values; hugo72 config validate prints details.",
+	"Nainstalujte Hugo z https://gohugo.io a ověřte, že je v PATH.":       "Install Hugo from https://gohugo.io and make sure it is in PATH.",
+	"vstupní Excel soubor": "input Excel file",
+	"Zkontrolujte cestu phase1.inputFile; soubor musí být ve formátu .xlsx a nesmí být zamčený jiným programem.": "Check the phase1.inputFile path; the file must be in .xlsx format and must not be locked by another program.",
+	"%s, přihlášek: %d": "%s, records: %d",
+	"web Hugo":          "Hugo site",
+	"Nastavte phase2.siteDir na adresář webu s hugo.toml; nový web připraví hugo72 init.": "Set phase2.siteDir to the site directory containing hugo.toml; hugo72 init sets up a new site.",
+	"zápis do %s": "write to %s",
+	"Ověřte, že cesta je platná a uživatel, pod kterým běh poběží, k ní má přístup.":                   "Check that the path is valid and accessible to the user the run will run as.",
+	"Nastavte oprávnění k zápisu pro uživatele, pod kterým běh poběží, a ověřte volné místo na disku.": "Grant write permission to the user the run will run as and check the free disk space.",
+	"adresář neexistuje, běh ho vytvoří": "the directory does not exist, the run will create it",
+	"cíl %s": "target %s",
+	"Opravte nastavení cíle v sekci phase3.":                                                                                                   "Fix the target settings in the phase3 section.",
+	"Ověřte ftpHost včetně portu (host:21), že server běží a že spojení nezablokuje firewall.":                                                 "Check ftpHost including the port (host:21), that the server is running and that no firewall blocks the connection.",
+	"Ověřte ftpUser a ftpPassword, u šifrovaného spojení také tls, caFile nebo certFingerprint.":                                               "Check ftpUser and ftpPassword, for an encrypted connection also tls, caFile or certFingerprint.",
+	"Ověřte ftpHost včetně portu (host:22), že SSH server běží a že spojení nezablokuje firewall.":                                             "Check ftpHost including the port (host:22), that the SSH server is running and that no firewall blocks the connection.",
+	"Ověřte ftpUser, ftpPassword nebo keyFile a klíč serveru (hostKey, knownHosts); neznámý klíč po ověření otisku uloží deploy --accept-new.": "Check ftpUser, ftpPassword or keyFile and the server key (hostKey, knownHosts); after verifying the fingerprint, deploy --accept-new stores an unknown key.",
+	"Ověřte, že uživatel smí číst vzdálený adresář remoteDir.":                                                                                 "Check that the user may read the remote directory remoteDir.",
+	"%s: adresář %s je zamčený nasazením (%s)":                                                                                                 "%s: directory %s is locked by a deploy (%s)",
+	"Pokud žádné nasazení neběží, zámek přepíše deploy --force.":                                                                               "If no deploy is running, deploy --force overrides the lock.",
+	"%s: adresář %s neexistuje, nasazení ho vytvoří":                                                                                           "%s: directory %s does not exist, the deploy will create it",
+	"Pokud má adresář existovat, zkontrolujte remoteDir.":                                                                                      "If the directory should exist, check remoteDir.",
+	"%s: přihlášení v pořádku, %s obsahuje položek: %d":                                                                                        "%s: login OK, %s contains %d entries",
+	"STAV\tKONTROLA\tVÝSLEDEK": "STATUS\tCHECK\tRESULT",
+	"VAROVÁNÍ":                 "WARNING",
+	"CHYBA":                    "ERROR",
+	"\nV pořádku: %d, varování: %d, chyby: %d.\n": "\nOK: %d, warnings: %d, errors: %d.\n",
+	// Program hugo72: příkazy config, init a secrets
+	"chybí příkaz: config validate nebo config schema": "missing command: config validate or config schema",
+	"neověřovat překlad adres FTP serverů v DNS":       "do not check that FTP server addresses resolve in DNS",
+	"neznámý příkaz: config %s":                        "unknown command: config %s",
+	"CHYBA: %s\n":                                      "ERROR: %s\n",
+	"VAROVÁNÍ: %s\n":                                   "WARNING: %s\n",
+	"program hugo nebyl nalezen v PATH, nainstalujte ho z https://gohugo.io":                           "the hugo program was not found in PATH, install it from https://gohugo.io",
+	"výchozí adresář webu 'phase2' neexistuje, nastavte phase2.siteDir":                                "the default site directory 'phase2' does not exist, set phase2.siteDir",
+	"heslo je prázdné, server nejspíš přihlášení odmítne":                                              "the password is empty, the server will probably refuse the login",
+	"spojení není šifrované, heslo se posílá čitelně; pokud to server umí, nastavte \"explicit\"":      "the connection is not encrypted, the password is sent in clear text; set \"explicit\" if the server supports it",
+	"volba je zastaralá a nemá účinek, nasazení příkaz SIZE neposílá; lze ji odstranit":                "the option is deprecated and has no effect, deploys never send SIZE; it can be removed",
+	"klíč serveru se ověří podle ~/.ssh/known_hosts; na jiném počítači (např. v CI) nastavte hostKey":  "the server key is verified against ~/.ssh/known_hosts; on another machine (e.g. in CI) set hostKey",
+	"ověřování certifikátu je vypnuté, nastavte raději caFile nebo certFingerprint":                    "certificate verification is disabled, prefer setting caFile or certFingerprint",
+	"adresu '%s' nelze přeložit: %v":                                                                   "cannot resolve address '%s': %v",
+	"konfigurace '%s' neprošla kontrolou":                                                              "configuration '%s' failed validation",
+	"konfigurace '%s' je platná, ale chybí předpoklady pro spuštění (chyby: %d)":                       "configuration '%s' is valid but prerequisites for running are missing (errors: %d)",
+	"Konfigurace '%s' je v pořádku (varování: %d).\n":                                                  "Configuration '%s' is OK (warnings: %d).\n",
+	"Konfigurace '%s' je v pořádku.\n":                                                                 "Configuration '%s' is OK.\n",
+	"soubor, do kterého se konfigurace zapíše (JSON, YAML nebo TOML)":                                  "file the configuration is written to (JSON, YAML or TOML)",
+	"přepsat existující konfiguraci":                                                                   "overwrite an existing configuration",
+	"průvodce nastavením nelze spustit se zkušebním během (--dry-run)":                                 "the setup wizard cannot run as a dry run (--dry-run)",
+	"konfigurace '%s' již existuje, pro přepsání použijte --force":                                     "configuration '%s' already exists, use --force to overwrite it",
+	"Nastavení nového projektu hugo72. Výchozí hodnota je v hranatých závorkách.":                      "Setting up a new hugo72 project. Default values are in square brackets.",
+	"Excel soubor se seznamem účastníků":                                                               "Excel file with the attendee list",
+	"Adresář Hugo webu":                                                                                "Hugo site directory",
+	"Protokol nasazení":                                                                                "Deploy protocol",
+	"Adresa FTP serveru (např. ftp.example.com)":                                                       "FTP server address (e.g. ftp.example.com)",
+	"Cílový adresář na serveru":                                                                        "Target directory on the server",
+	"Uživatelské jméno FTP":                                                                            "FTP user name",
+	"Uložení přihlašovacích údajů (env = soubor .env, age = zašifrovat, config = přímo v konfiguraci)": "Credential storage (env = .env file, age = encrypt, config = directly in the configuration)",
+	"Heslo FTP": "FTP password",
+	"Soubory k nahrání oddělené čárkou (prázdné = jen artefakt z build --artifact)": "Files to upload, comma separated (empty = only the artifact from build --artifact)",
+	"chyba při čtení odpovědí: %w":                                                  "error reading answers: %w",
+	"chyba při serializaci konfigurace: %w":                                         "error serializing configuration: %w",
+	"chyba při zápisu konfigurace '%s': %w":                                         "error writing configuration '%s': %w",
+	"\nKonfigurace zapsána do '%s'.\n":                                              "\nConfiguration written to '%s'.\n",
+	"Proměnné prostředí doplněny do '%s' (soubor nepatří do gitu).\n":               "Environment variables added to '%s' (keep this file out of git).\n",
+	"Klíč k dešifrování hesla je v '%s', uložte si jeho zálohu.\n":                  "The key to decrypt the password is in '%s', keep a backup of it.\n",
+	"\nUpozornění: %v\n":                                                            "\nWarning: %v\n",
+	"\nDalší kroky: hugo72 run, nebo jednotlivě convert, build a deploy.":           "\nNext steps: hugo72 run, or convert, build and deploy one by one.",
+	"Hodnota je povinná.":                                                           "A value is required.",
+	"Neznámá možnost '%s'.\n":                                                       "Unknown option '%s'.\n",
+	"vstup skončil před zodpovězením všech otázek":                                  "input ended before all questions were answered",
+	"klíč '%s' již existuje, průvodce ho nepřepíše":                                 "key '%s' already exists, the wizard will not overwrite it",
+	"chyba při vytváření adresáře pro '%s': %w":                                     "error creating directory for '%s': %w",
+	"chyba při zápisu klíče '%s': %w":                                               "error writing key '%s': %w",
+	"chyba při otevírání souboru '%s': %w":                                          "error opening file '%s': %w",
+	"chyba při zápisu souboru '%s': %w":                                             "error writing file '%s': %w",
+	"chybí příkaz: secrets keygen nebo secrets encrypt":                             "missing command: secrets keygen or secrets encrypt",
+	"veřejný klíč age1..., pro který se hodnota zašifruje, lze zadat opakovaně":     "public key age1... to encrypt the value for, may be repeated",
+	"neznámý příkaz: secrets %s":                                                    "unknown command: secrets %s",
+
+	// Program hugo72: příkazy mail a checkin
+	"soubor se šablonou e-mailu (výchozí pozvánka vložená v programu)":                                          "e-mail template file (default: the invitation embedded in the program)",
+	"název kampaně pro záznam odeslaných e-mailů (výchozí název šablony bez přípony)":                           "campaign name for the sent-mail log (default template name without extension)",
+	"příjemci: all, attending, declined nebo unanswered":                                                        "recipients: all, attending, declined or unanswered",
+	"nejvyšší počet odeslaných e-mailů (0 = bez omezení)":                                                       "maximum number of e-mails to send (0 = unlimited)",
+	"konfigurace neobsahuje sekci mailer":                                                                       "configuration has no mailer section",
+	"Odkazy na formulář nejsou podepsané (chybí mailer.tokenSecret), účastník ze seznamu jimi odpověď nezmění.": "Form links are not signed (mailer.tokenSecret is missing), listed attendees cannot change their response with them.",
+	"Rozesílám e-maily.":                                                              "Sending e-mails.",
+	"Zkušební běh, e-mail se neodešle.":                                               "Dry run, e-mail not sent.",
+	"Dávka odeslána, čekám před další.":                                               "Batch sent, pausing before the next one.",
+	"rozesílání přerušeno po %d e-mailech: %w":                                        "sending cancelled after %d e-mails: %w",
+	"E-mail se nepodařilo odeslat.":                                                   "Failed to send e-mail.",
+	"E-mail odeslán.":                                                                 "E-mail sent.",
+	"nepodařilo se odeslat %d z %d e-mailů":                                           "failed to send %d of %d e-mails",
+	"Rozesílání dokončeno.":                                                           "Sending finished.",
+	"chyba při čtení šablony e-mailu: %w":                                             "error reading e-mail template: %w",
+	"šablona e-mailu '%s' musí začínat řádkem \"Subject: ...\"":                       "e-mail template '%s' must start with a \"Subject: ...\" line",
+	"chyba v předmětu šablony '%s': %w":                                               "error in subject of template '%s': %w",
+	"chyba v šabloně e-mailu: %w":                                                     "error in e-mail template: %w",
+	"chyba při vykreslení předmětu pro '%s': %w":                                      "error rendering subject for '%s': %w",
+	"chyba při vykreslení e-mailu pro '%s': %w":                                       "error rendering e-mail for '%s': %w",
+	"neznámá hodnota --to '%s', povoleno je all, attending, declined nebo unanswered": "unknown value --to '%s', allowed are all, attending, declined or unanswered",
+	"chyba při otevírání záznamu odeslaných e-mailů '%s': %w":                         "error opening sent-mail log '%s': %w",
+	"chyba v záznamu odeslaných e-mailů '%s' na řádku %d: %w":                         "error in sent-mail log '%s' on line %d: %w",
+	"chyba při čtení záznamu odeslaných e-mailů '%s': %w":                             "error reading sent-mail log '%s': %w",
+	"chyba při zápisu záznamu odeslaných e-mailů '%s': %w":                            "error writing sent-mail log '%s': %w",
+	"konfigurace neobsahuje sekci phase1":                                             "configuration has no phase1 section",
+	"výstup převodu '%s' nelze načíst, spusťte nejdřív convert":                       "cannot read convert output '%s', run convert first",
+	"Přítomno %d z %d očekávaných (přihlášeno %d).\n":                                 "Present %d of %d expected (%d registered).\n",
+	"formát výpisu: csv nebo json":                                                    "output format: csv or json",
+	"soubor pro výpis (výchozí standardní výstup)":                                    "output file (default standard output)",
+	"neznámý formát '%s', povoleno je csv nebo json":                                  "unknown format '%s', allowed are csv or json",
+	"chyba při vytváření souboru '%s': %w":                                            "error creating file '%s': %w",
+	"chyba při zápisu docházky: %w":                                                   "error writing attendance: %w",
+	"neznámý příkaz: checkin %s":                                                      "unknown command: checkin %s",
+	"Přítomno %d z %d. Zadejte e-mail, jméno nebo naskenujte QR kód (\"-\" na začátku příchod zruší, Ctrl-D ukončí).\n": "Present %d of %d. Enter an e-mail or name, or scan a QR code (a leading \"-\" cancels an arrival, Ctrl-D quits).\n",
+	"%s není zapsán(a) jako přítomný(á).\n":            "%s is not checked in.\n",
+	"%s už je přítomen/přítomna od %s.\n":              "%s has been present since %s.\n",
+	"Zrušeno: %s. Přítomno %d z %d.\n":                 "Cancelled: %s. Present %d of %d.\n",
+	"Vítejte, %s%s. Přítomno %d z %d.\n":               "Welcome, %s%s. Present %d of %d.\n",
+	" (nebyl(a) přihlášen(a))":                         " (not registered)",
+	"Jméno '%s' má %d účastníků, zadejte e-mail.":      "%[2]d attendees are named '%[1]s', enter the e-mail.",
+	"Účastník '%s' nemá e-mail, příchod nelze zapsat.": "Attendee '%s' has no e-mail, cannot check in.",
+	"Účastník '%s' v seznamu není.":                    "Attendee '%s' is not on the list.",
+	"chyba při čtení vstupu: %w":                       "error reading input: %w",
+	"chyba při otevírání záznamu příchodů '%s': %w":    "error opening check-in log '%s': %w",
+	"chyba v záznamu příchodů '%s' na řádku %d: %w":    "error in check-in log '%s' on line %d: %w",
+	"chyba při čtení záznamu příchodů '%s': %w":        "error reading check-in log '%s': %w",
+	"chyba při vytváření adresáře '%s': %w":            "error creating directory '%s': %w",
+	"chyba při zápisu záznamu příchodů '%s': %w":       "error writing check-in log '%s': %w",
+
+	// Program hugo72: příkaz build
+	"po sestavení zabalit public/ do tohoto souboru tar.gz": "after the build, pack public/ into this tar.gz file",
+
+	// Program hugo72: příkazy version a self-update
+	"vypíše verzi programu a údaje o sestavení":                                         "prints the program version and build information",
+	"nahradí program novější verzí z vydání na GitHubu":                                 "replaces the program with a newer version from the GitHub releases",
+	", neuložené změny":                                                                 ", uncommitted changes",
+	"Sestaveno: %s\n":                                                                   "Built: %s\n",
+	"jen zjistit, zda je k dispozici novější verze":                                     "only check whether a newer version is available",
+	"nainstalovat zadanou verzi (např. v1.5.0) místo nejnovější":                        "install the given version (e.g. v1.5.0) instead of the latest",
+	"nainstalovat verzi, i když není novější než současná":                              "install the version even if it is not newer than the current one",
+	"repozitář na GitHubu, ze kterého se aktualizuje":                                   "GitHub repository to update from",
+	"Program je aktuální.":                                                              "The program is up to date.",
+	"Je k dispozici novější verze.":                                                     "A newer version is available.",
+	"vývojové sestavení se neaktualizuje, pro nahrazení vydanou verzí použijte --force": "development builds are not updated, use --force to replace it with a released version",
+	"cestu k programu nelze zjistit: %w":                                                "cannot determine the program path: %w",
+	"vydání %s neobsahuje soubor %s pro tento systém":                                   "release %s has no file %s for this system",
+	"Stahuji novou verzi.":                                                              "Downloading the new version.",
+	"program '%s' nelze nahradit: %w":                                                   "cannot replace program '%s': %w",
+	"Program byl aktualizován.":                                                         "The program was updated.",
+	"chyba při zjišťování vydání: %w":                                                   "error looking up the release: %w",
+	"chyba při čtení údajů o vydání: %w":                                                "error reading the release information: %w",
+	"vydání %s neobsahuje seznam kontrolních součtů %s":                                 "release %s has no checksum list %s",
+	"chyba při stahování %s: %w":                                                        "error downloading %s: %w",
+	"Program neobsahuje veřejný klíč, podpis vydání se neověří.":                        "The program has no public key, the release signature will not be verified.",
+	"neplatný kontrolní součet souboru %s":                                              "invalid checksum of file %s",
+	"seznam %s neobsahuje soubor %s":                                                    "list %s does not contain file %s",
+	"veřejný klíč pro ověření vydání je neplatný":                                       "the public key for verifying releases is invalid",
+	"vydání %s není podepsané (chybí %s)":                                               "release %s is not signed (%s is missing)",
+	"podpis vydání %s nesouhlasí, soubor mohl být podvržen":                             "the signature of release %s does not match, the file may have been tampered with",
+	"chyba při stahování nové verze: %w":                                                "error downloading the new version: %w",
+	"chyba při vytváření dočasného souboru: %w":                                         "error creating a temporary file: %w",
+	"kontrolní součet stažené verze nesouhlasí, program se nenahradí":                   "the checksum of the downloaded version does not match, the program will not be replaced",
+	"původní program nelze vrátit z '%s': %w":                                           "cannot restore the original program from '%s': %w",
+
+	// Program hugo72: příkaz export-defaults
+	"zapíše na disk výchozí soubory vložené v programu (šablony, schéma)": "writes the defaults embedded in the program (templates, schema) to disk",
+	"šablona konfigurace": "configuration template",
+	"JSON Schema konfigurace, odkazuje na něj šablona konfigurace":       "JSON Schema of the configuration, referenced by the configuration template",
+	"šablona pozvánky pro příkaz mail":                                   "invitation template for the mail command",
+	"adresář, do kterého se soubory zapíšou":                             "directory to write the files to",
+	"přepsat existující soubory":                                         "overwrite existing files",
+	"jen vypsat vložené soubory":                                         "only list the embedded files",
+	"neznámý soubor '%s', vložené soubory vypíše export-defaults --list": "unknown file '%s', export-defaults --list shows the embedded files",
+	"soubory již existují (%s), pro přepsání použijte --force":           "files already exist (%s), use --force to overwrite them",
+	"Soubor zapsán.": "File written.",
+
+	// Program hugo72: příkaz service a nové načtení konfigurace
+	"nainstaluje watch nebo serve jako službu systemd nebo Windows":                         "installs watch or serve as a systemd or Windows service",
+	"adresář projektu, do kterého program před spuštěním příkazu přejde (např. pro službu)": "project directory the program changes to before running the command (e.g. for a service)",
+	"Stav služby nelze oznámit.":                          "Cannot report the service state.",
+	"použití: service unit|install|uninstall [přepínače]": "usage: service unit|install|uninstall [flags]",
+	"název služby": "service name",
+	"uživatelská jednotka systemd (systemctl --user)":                "systemd user unit (systemctl --user)",
+	"přepsat existující jednotku systemd":                            "overwrite an existing systemd unit",
+	"jako službu lze spustit jen serve nebo watch, ne '%s'":          "only serve or watch can run as a service, not '%s'",
+	"neznámá akce '%s', použijte unit, install nebo uninstall":       "unknown action '%s', use unit, install or uninstall",
+	"pracovní adresář nelze zjistit: %w":                             "cannot determine the working directory: %w",
+	"adresář uživatelské konfigurace nelze zjistit: %w":              "cannot determine the user configuration directory: %w",
+	"služby jsou podporované jen pod systemd (Linux) a ve Windows":   "services are supported only under systemd (Linux) and on Windows",
+	"jednotka '%s' již existuje, pro přepsání použijte --force":      "unit '%s' already exists, use --force to overwrite it",
+	"Zkušební běh, jednotka se nezapíše.":                            "Dry run, the unit will not be written.",
+	"Jednotka systemd zapsána, službu zapnete příkazem:":             "systemd unit written, enable the service with:",
+	"jednotka '%s' neexistuje":                                       "unit '%s' does not exist",
+	"Zkušební běh, jednotka se neodstraní.":                          "Dry run, the unit will not be removed.",
+	"chyba při odstraňování souboru '%s': %w":                        "error removing file '%s': %w",
+	"Jednotka systemd odstraněna, běžící službu zastavíte příkazem:": "systemd unit removed, stop a running service with:",
+	"Zkušební běh, služba se nezaregistruje.":                        "Dry run, the service will not be registered.",
+	"Služba zaregistrována, spustíte ji příkazem:":                   "Service registered, start it with:",
+	"Zkušební běh, služba se neodstraní.":                            "Dry run, the service will not be removed.",
+	"Služba odstraněna.":                                             "Service removed.",
+	"Přijat signál SIGHUP, znovu načítám konfiguraci.":               "SIGHUP received, reloading the configuration.",
+	"Správce služeb požádal o nové načtení konfigurace.":             "The service manager requested a configuration reload.",
+	"Konfiguraci nelze znovu načíst, platí dosavadní.":               "Cannot reload the configuration, keeping the current one.",
+	"Konfigurace znovu načtena.":                                     "Configuration reloaded.",
+	"Změna server.listen se projeví až po restartu.":                 "A change of server.listen takes effect only after a restart.",
+
+	// Balíček config
+	"neznámá fáze, povoleno je %s":                                                                  "unknown phase, allowed are %s",
+	"háček musí mít command nebo url":                                                               "a hook needs command or url",
+	"háček může mít jen jedno z command a url":                                                      "a hook can have only one of command and url",
+	"název programu je prázdný":                                                                     "the program name is empty",
+	"neznámá hodnota '%s', povoleno je \"abort\" nebo \"continue\"":                                 "unknown value '%s', allowed are \"abort\" or \"continue\"",
+	"chyba při otevírání souboru konfigurace '%s': %w":                                              "error opening configuration file '%s': %w",
+	"chyba při dekódování konfigurace '%s': %w":                                                     "error decoding configuration '%s': %w",
+	"chyba při dešifrování konfigurace '%s': %w":                                                    "error decrypting configuration '%s': %w",
+	"chyba v konfiguraci '%s': %w":                                                                  "error in configuration '%s': %w",
+	"chyba v konfiguraci '%s':\n%w":                                                                 "error in configuration '%s':\n%w",
+	"chyba při dešifrování konfigurace '%s':\n%w":                                                   "error decrypting configuration '%s':\n%w",
+	"chyba při dekódování konfigurace '%s':\n%w":                                                    "error decoding configuration '%s':\n%w",
+	"konfigurace '%s' (profil %s) obsahuje chyby:\n%w":                                              "configuration '%s' (profile %s) has errors:\n%w",
+	"konfigurace '%s' obsahuje chyby:\n%w":                                                          "configuration '%s' has errors:\n%w",
+	"%s:%d: očekáván zápis PROMENNA=hodnota":                                                        "%s:%d: expected VARIABLE=value",
+	"chyba při čtení souboru '%s': %w":                                                              "error reading file '%s': %w",
+	"chybí uzavírací uvozovka":                                                                      "missing closing quote",
+	"proměnná prostředí %s není nastavena":                                                          "environment variable %s is not set",
+	"chyba při čtení YAML: %w":                                                                      "error reading YAML: %w",
+	"chyba při čtení TOML: %w":                                                                      "error reading TOML: %w",
+	"profil '%s' není definován, konfigurace nemá sekci profiles":                                   "profile '%s' is not defined, the configuration has no profiles section",
+	"profil '%s' není definován (dostupné profily: %s)":                                             "profile '%s' is not defined (available profiles: %s)",
+	"chyba při čtení schématu konfigurace: %w":                                                      "error reading configuration schema: %w",
+	"neplatná hodnota %s, povoleno je %s":                                                           "invalid value %s, allowed are %s",
+	"hodnota má být %s, nalezeno %s":                                                                "value should be %s, found %s",
+	"neznámý klíč%s":                                                                                "unknown key%s",
+	"hodnota '%s' nemá očekávaný tvar (%s)":                                                         "value '%s' does not have the expected form (%s)",
+	"hodnota musí být alespoň %v":                                                                   "value must be at least %v",
+	"hodnotu nelze dešifrovat: %v":                                                                  "cannot decrypt value: %v",
+	"chyba při otevírání klíče '%s': %w":                                                            "error opening key '%s': %w",
+	"konfigurace obsahuje zašifrované hodnoty, ale není nastaven klíč %s ani %s":                    "configuration contains encrypted values but neither %s nor %s is set",
+	"neplatný příjemce '%s': %w":                                                                    "invalid recipient '%s': %w",
+	"chybí příjemce (veřejný klíč age1...)":                                                         "missing recipient (public key age1...)",
+	"sops soubor nedešifroval: %s":                                                                  "sops did not decrypt the file: %s",
+	"soubor je zašifrovaný nástrojem SOPS, ale program sops nelze spustit: %w":                      "the file is encrypted with SOPS but the sops program cannot be run: %w",
+	"konfigurace neobsahuje žádnou sekci (phase1, phase2, phase3)":                                  "configuration has no section (phase1, phase2, phase3)",
+	"adresář '%s' neexistuje":                                                                       "directory '%s' does not exist",
+	"jmenovky vyžadují sekci phase1, ze které se čtou účastníci":                                    "badges require the phase1 section the attendees are read from",
+	"hodnota je povinná":                                                                            "value is required",
+	"port %d je mimo rozsah 1-65535":                                                                "port %d is outside the range 1-65535",
+	"'%s' není platná adresa http(s)":                                                               "'%s' is not a valid http(s) address",
+	"klíč je příliš krátký, použijte aspoň 16 znaků":                                                "key is too short, use at least 16 characters",
+	"hodnota nesmí být záporná":                                                                     "value must not be negative",
+	"neznámá událost, povoleno je %s":                                                               "unknown event, allowed are %s",
+	"neplatná šablona: %v":                                                                          "invalid template: %v",
+	"neznámý typ '%s', povoleno je \"smtp\", \"slack\", \"telegram\", \"discord\" nebo \"webhook\"": "unknown type '%s', allowed are \"smtp\", \"slack\", \"telegram\", \"discord\" or \"webhook\"",
+	"token je příliš krátký, použijte aspoň 16 znaků":                                               "token is too short, use at least 16 characters",
+	"'%s' není adresa ve tvaru \"host:port\"":                                                       "'%s' is not an address of the form \"host:port\"",
+	"heslo je příliš krátké, použijte aspoň 12 znaků":                                               "password is too short, use at least 12 characters",
+	"soubor '%s' nelze použít: %v":                                                                  "file '%s' cannot be used: %v",
+	"failurePolicy \"abort-after\" vyžaduje maxErrors alespoň 1":                                    "failurePolicy \"abort-after\" requires maxErrors of at least 1",
+	"neznámá hodnota '%s', povoleno je \"continue\", \"abort\" nebo \"abort-after\"":                "unknown value '%s', allowed are \"continue\", \"abort\" or \"abort-after\"",
+	"neplatná práva '%s', očekáván oktalový zápis jako \"755\"":                                     "invalid permissions '%s', expected octal notation like \"755\"",
+	"'%s' má obsahovat jen adresu serveru a případně port, bez ftp://":                              "'%s' should contain only the server address and optionally a port, without ftp://",
+	"'%s' nemá za dvojtečkou číslo portu":                                                           "'%s' has no port number after the colon",
+	"neznámý režim '%s', povoleno je \"explicit\" nebo \"implicit\"":                                "unknown mode '%s', allowed are \"explicit\" or \"implicit\"",
+	"keyFile, knownHosts a hostKey platí jen pro protokol \"sftp\"":                                 "keyFile, knownHosts and hostKey apply only to protocol \"sftp\"",
+	"neznámý protokol '%s', povoleno je \"ftp\" nebo \"sftp\"":                                      "unknown protocol '%s', allowed are \"ftp\" or \"sftp\"",
+	"u protokolu \"sftp\" spojení šifruje SSH, tls, caFile, certFingerprint ani insecureSkipVerify se nenastavují": "with protocol \"sftp\" the connection is encrypted by SSH, do not set tls, caFile, certFingerprint or insecureSkipVerify",
+	"příkaz serveru lze poslat jen přes FTP, u protokolu \"sftp\" použijte quotaBytes":                             "a server command can only be sent over FTP, use quotaBytes with protocol \"sftp\"",
+	"obejití chyb FTP serverů se u protokolu \"sftp\" nepoužijí":                                                   "FTP server quirks do not apply to protocol \"sftp\"",
+	"zadejte keyFile nebo ftpPassword":                                             "set keyFile or ftpPassword",
+	"'%s' není otisk \"SHA256:...\" ani veřejný klíč jako \"ssh-ed25519 AAAA...\"": "'%s' is neither a \"SHA256:...\" fingerprint nor a public key like \"ssh-ed25519 AAAA...\"",
+	"neplatná doba trvání '%s', očekáván zápis jako \"30m\" nebo \"200ms\"":        "invalid duration '%s', expected notation like \"30m\" or \"200ms\"",
+	"neznámý jazyk '%s', povoleno je \"cs\" nebo \"en\"":                           "unknown language '%s', allowed are \"cs\" or \"en\"",
+
+	// Balíček backup
+	"chyba při vytváření adresáře záloh '%s': %w":                   "error creating backup directory '%s': %w",
+	"chyba při ukládání zálohy '%s': %w":                            "error saving backup '%s': %w",
+	"chyba při čtení '%s': %w":                                      "error reading '%s': %w",
+	"chyba při procházení adresáře '%s': %w":                        "error walking directory '%s': %w",
+	"soubor '%s' se během zálohování zkrátil":                       "file '%s' shrank while being backed up",
+	"chyba při čtení adresáře záloh '%s': %w":                       "error reading backup directory '%s': %w",
+	"archiv neobsahuje %s":                                          "the archive does not contain %s",
+	"chyba při čtení zálohy '%s': %w":                               "error reading backup '%s': %w",
+	"neplatný identifikátor zálohy '%s'":                            "invalid backup id '%s'",
+	"záloha '%s' v adresáři '%s' není":                              "backup '%s' is not in directory '%s'",
+	"chyba při obnově zálohy '%s': %w":                              "error restoring backup '%s': %w",
+	"kontrolní součet souboru '%s' nesouhlasí, záloha je poškozená": "checksum of file '%s' does not match, the backup is corrupted",
+	"chyba při mazání staré zálohy '%s': %w":                        "error deleting old backup '%s': %w",
+
+	// Balíček hooks
+	"chyba při serializaci dat háčku: %w":   "error serializing hook data: %w",
+	"Zkušební běh, háček se nespustí.":      "Dry run, the hook will not run.",
+	"Háček proběhl.":                        "Hook finished.",
+	"Háček selhal, pokračuji.":              "Hook failed, continuing.",
+	"Háček selhal.":                         "Hook failed.",
+	"háček hooks.%s.%s[%d] (%s) selhal: %w": "hook hooks.%s.%s[%d] (%s) failed: %w",
+	"Výstup háčku.":                         "Hook output.",
+	"vypršel čas háčku":                     "hook timed out",
+	"neplatná adresa háčku":                 "invalid hook URL",
+	"chyba při odesílání háčku: %w":         "error sending hook: %w",
+
+	// Balíček service
+	"chyba při oznámení stavu služby systemd: %w":   "error reporting the state to systemd: %w",
+	"chyba při spouštění služby Windows: %w":        "error starting the Windows service: %w",
+	"chyba při připojení ke správci služeb: %w":     "error connecting to the service manager: %w",
+	"služba '%s' již existuje":                      "service '%s' already exists",
+	"chyba při vytváření služby '%s': %w":           "error creating service '%s': %w",
+	"Zdroj protokolu událostí nelze zaregistrovat.": "Cannot register the event log source.",
+	"služba '%s' neexistuje: %w":                    "service '%s' does not exist: %w",
+	"chyba při odstraňování služby '%s': %w":        "error removing service '%s': %w",
+	"chyba při otevírání protokolu událostí: %w":    "error opening the event log: %w",
+	"služby Windows lze instalovat jen ve Windows":  "Windows services can be installed only on Windows",
+	"protokol událostí je dostupný jen ve Windows":  "the event log is available only on Windows",
+
+	// Balíček cron
+	"výraz '%s' má mít 5 polí (minuta hodina den měsíc den-v-týdnu), má %d": "expression '%s' should have 5 fields (minute hour day month weekday), it has %d",
+	"výraz '%s': %w": "expression '%s': %w",
+	"výraz '%s' neodpovídá žádnému času":  "expression '%s' matches no time",
+	"%s: neplatný krok '%s'":              "%s: invalid step '%s'",
+	"%s: rozsah '%s' je obrácený":         "%s: range '%s' is reversed",
+	"%s: neplatná hodnota '%s'":           "%s: invalid value '%s'",
+	"%s: hodnota %d je mimo rozsah %d-%d": "%s: value %d is outside the range %d-%d",
+
+	// Balíčky logging, mailer a notify
+	"neznámá úroveň logu '%s', povoleno je debug, info, warn nebo error": "unknown log level '%s', allowed are debug, info, warn or error",
+	"neznámý formát logu '%s', povoleno je \"text\" nebo \"json\"":       "unknown log format '%s', allowed are \"text\" or \"json\"",
+	"chyba při vytváření adresáře logu '%s': %w":                         "error creating log directory '%s': %w",
+	"chyba při otevírání souboru logu '%s': %w":                          "error opening log file '%s': %w",
+	"chyba při odkládání souboru logu '%s': %w":                          "error rotating log file '%s': %w",
+	"chyba při odesílání e-mailu přes '%s': %w":                          "error sending e-mail via '%s': %w",
+	"chyba v šabloně oznámení '%s': %w":                                  "error in notification template '%s': %w",
+	"chyba při vykreslení oznámení '%s': %w":                             "error rendering notification '%s': %w",
+	"neznámý typ kanálu '%s'":                                            "unknown channel type '%s'",
+	"chyba při serializaci oznámení: %w":                                 "error serializing notification: %w",
+	"neplatná adresa kanálu":                                             "invalid channel address",
+	"chyba při odesílání oznámení: %w":                                   "error sending notification: %w",
+	"služba vrátila stav %s":                                             "service returned status %s",
+
+	// Fáze 1: převod
+	"chyba při otevírání Excel souboru: %w":                                           "error opening Excel file: %w",
+	"chyba při čtení řádků ze souboru: %w":                                            "error reading rows from the file: %w",
+	"Načten list Excel souboru.":                                                      "Excel sheet loaded.",
+	"převod přerušen: %w":                                                             "conversion cancelled: %w",
+	"Zkušební běh, soubor se nezapíše.":                                               "Dry run, file not written.",
+	"chyba při zápisu JSON souboru: %w":                                               "error writing JSON file: %w",
+	"Soubor byl úspěšně vytvořen.":                                                    "File created successfully.",
+	"chyba při čtení textů webu '%s': %w":                                             "error reading site texts '%s': %w",
+	"chyba v souboru s texty webu '%s': %w":                                           "error in site texts file '%s': %w",
+	"chyba při serializaci textů webu: %w":                                            "error serializing site texts: %w",
+	"chyba při zápisu textů webu '%s': %w":                                            "error writing site texts '%s': %w",
+	"chyba při otevírání odpovědí '%s': %w":                                           "error opening responses '%s': %w",
+	"chyba při zápisu odpovědi '%s': %w":                                              "error writing response '%s': %w",
+	"chyba v odpovědích '%s' na řádku %d: %w":                                         "error in responses '%s' on line %d: %w",
+	"chyba při čtení odpovědí '%s': %w":                                               "error reading responses '%s': %w",
+	"Neověřené odpovědi z formuláře nemohou změnit účastníka, který už v seznamu je.": "Unverified form responses cannot change an attendee who is already listed.",
+
+	// Fáze 3: nasazení
+	"chyba při vytváření adresáře artefaktů '%s': %w":                                    "error creating artifact directory '%s': %w",
+	"chyba při vytváření dočasného adresáře: %w":                                         "error creating temporary directory: %w",
+	"chyba při serializaci manifestu: %w":                                                "error serializing manifest: %w",
+	"chyba při zápisu manifestu: %w":                                                     "error writing manifest: %w",
+	"chyba při ukládání artefaktu '%s': %w":                                              "error saving artifact '%s': %w",
+	"artefakt '%s' nebyl v '%s' nalezen":                                                 "artifact '%s' not found in '%s'",
+	"chyba při čtení manifestu artefaktu: %w":                                            "error reading artifact manifest: %w",
+	"chyba při dekódování manifestu artefaktu: %w":                                       "error decoding artifact manifest: %w",
+	"artefakt '%s' byl po uložení změněn":                                                "artifact '%s' was modified after it was saved",
+	"cestu '%s' nelze uložit do artefaktu":                                               "path '%s' cannot be stored in an artifact",
+	"chyba při otevření souboru '%s': %w":                                                "error opening file '%s': %w",
+	"chyba při kopírování souboru '%s': %w":                                              "error copying file '%s': %w",
+	"cíl '%s' nemá žádné úspěšné nasazení, není co povýšit":                              "target '%s' has no successful deploy, nothing to promote",
+	"Povyšuji artefakt na další cíl.":                                                    "Promoting artifact to the next target.",
+	"chyba při otevření artefaktu '%s': %w":                                              "error opening artifact '%s': %w",
+	"artefakt '%s' není platný gzip: %w":                                                 "artifact '%s' is not valid gzip: %w",
+	"chyba při čtení artefaktu '%s': %w":                                                 "error reading artifact '%s': %w",
+	"chyba při rozbalování souboru '%s': %w":                                             "error extracting file '%s': %w",
+	"chyba při serializaci mezipaměti kontrolních součtů: %w":                            "error serializing checksum cache: %w",
+	"chyba při ukládání mezipaměti kontrolních součtů '%s': %w":                          "error saving checksum cache '%s': %w",
+	"nasazení se nezdařilo":                                                              "deploy failed",
+	"konfigurace neobsahuje sekci phase3":                                                "configuration has no phase3 section",
+	"název cíle nasazení z konfigurace, lze zadat opakovaně (výchozí \"default\")":       "deploy target name from the configuration, may be repeated (default \"default\")",
+	"nasadit souběžně na všechny cíle z konfigurace":                                     "deploy to all configured targets concurrently",
+	"přepsat existující zámek nasazení na serveru":                                       "overwrite an existing deploy lock on the server",
+	"nasadit obsah sestaveného artefaktu tar.gz místo souborů z konfigurace":             "deploy the contents of a built tar.gz artifact instead of the configured files",
+	"nasadit jen soubory odpovídající vzoru (např. \"data/\"), lze zadat opakovaně":      "deploy only files matching the pattern (e.g. \"data/\"), may be repeated",
+	"zadaným vzorům neodpovídá žádný soubor, není co nasadit":                            "no file matches the given patterns, nothing to deploy",
+	"cíl, jehož poslední úspěšné nasazení se má povýšit":                                 "target whose last successful deploy should be promoted",
+	"cíl, na který se artefakt nasadí":                                                   "target the artifact is deployed to",
+	"neznámý příkaz: deploys %s":                                                         "unknown command: deploys %s",
+	"cíl nasazení '%s' není v konfiguraci definován":                                     "deploy target '%s' is not defined in the configuration",
+	"neznámý režim tls '%s', povoleno je \"explicit\" nebo \"implicit\"":                 "unknown tls mode '%s', allowed are \"explicit\" or \"implicit\"",
+	"chyba při připojování k FTP serveru: %w":                                            "error connecting to the FTP server: %w",
+	"chyba při přihlášení na FTP server: %w":                                             "error logging in to the FTP server: %w",
+	"Úspěšně připojeno k FTP serveru.":                                                   "Connected to the FTP server.",
+	"uložit do known_hosts klíč SFTP serveru, ke kterému se připojuje poprvé":            "store the host key of an SFTP server connected to for the first time in known_hosts",
+	"chyba při připojování k SFTP serveru: %w":                                           "error connecting to the SFTP server: %w",
+	"chyba při přihlášení na SFTP server: %w":                                            "error logging in to the SFTP server: %w",
+	"server %s nenabízí SFTP: %w":                                                        "server %s does not offer SFTP: %w",
+	"Úspěšně připojeno k SFTP serveru.":                                                  "Connected to the SFTP server.",
+	"chyba při čtení klíče '%s': %w":                                                     "error reading key '%s': %w",
+	"klíč '%s' nelze odemknout heslem ftpPassword: %w":                                   "key '%s' cannot be unlocked with the ftpPassword passphrase: %w",
+	"chyba v klíči '%s': %w":                                                             "error in key '%s': %w",
+	"nelze určit domovský adresář pro known_hosts, nastavte knownHosts nebo hostKey: %w": "cannot determine the home directory for known_hosts, set knownHosts or hostKey: %w",
+	"soubor known_hosts '%s' neexistuje; ověřte otisk klíče serveru a připojte se s --accept-new, nebo nastavte hostKey": "known_hosts file '%s' does not exist; verify the server key fingerprint and connect with --accept-new, or set hostKey",
+	"chyba při vytváření souboru known_hosts '%s': %w":                                                                   "error creating known_hosts file '%s': %w",
+	"chyba při čtení known_hosts '%s': %w":                                                                               "error reading known_hosts '%s': %w",
+	"klíč serveru %s se změnil (%s %s), neodpovídá known_hosts '%s'; pokud změnu nečekáte, může jít o podvržený server":  "host key of server %s has changed (%s %s) and does not match known_hosts '%s'; if you did not expect the change, the server may be an impostor",
+	"klíč serveru %s (%s %s) není v known_hosts '%s'; ověřte otisk a připojte se s --accept-new, nebo nastavte hostKey":  "host key of server %s (%s %s) is not in known_hosts '%s'; verify the fingerprint and connect with --accept-new, or set hostKey",
+	"chyba při zápisu do known_hosts '%s': %w":                                                                           "error writing to known_hosts '%s': %w",
+	"Klíč serveru uložen do known_hosts při prvním připojení (--accept-new).":                                            "Server host key stored in known_hosts on first contact (--accept-new).",
+	"klíč serveru %s (%s %s) neodpovídá nastavenému hostKey; pokud změnu nečekáte, může jít o podvržený server":          "host key of server %s (%s %s) does not match the configured hostKey; if you did not expect the change, the server may be an impostor",
+	"neplatný hostKey '%s': %w":                                                                "invalid hostKey '%s': %w",
+	"server %s není dosažitelný: %w":                                                           "server %s is not reachable: %w",
+	"Nahrávám soubor.":                                                                         "Uploading file.",
+	"chyba při otevření lokálního souboru '%s': %w":                                            "error opening local file '%s': %w",
+	"chyba při nahrávání souboru '%s' na server: %w":                                           "error uploading file '%s' to the server: %w",
+	"Soubor byl úspěšně nahrán na server.":                                                     "File uploaded to the server.",
+	"Opakuji nahrání souboru.":                                                                 "Retrying file upload.",
+	"Nedokončený soubor se nepodařilo smazat.":                                                 "Failed to delete the incomplete file.",
+	"Nedokončený soubor byl ze serveru smazán.":                                                "Incomplete file deleted from the server.",
+	"nasazení odmítnuto, obsah neprošel kontrolou:\n%w":                                        "deploy refused, content failed validation:\n%w",
+	"Mezipaměť kontrolních součtů nelze uložit.":                                               "Cannot save the checksum cache.",
+	"Chyba při sestavování manifestu.":                                                         "Error building the manifest.",
+	"Chyba při ukládání artefaktu.":                                                            "Error saving the artifact.",
+	"Chyba při zápisu reportu.":                                                                "Error writing the report.",
+	"Chyba při zápisu historie nasazení.":                                                      "Error writing the deploy history.",
+	"Chyba při odesílání webhooku.":                                                            "Error sending the webhook.",
+	"Zámek nasazení se nepodařilo uvolnit.":                                                    "Failed to release the deploy lock.",
+	"Stránka údržby se nepoužije, mezi soubory chybí index.html.":                              "Maintenance page not used, index.html is missing from the files.",
+	"Chyba při nahrávání stránky údržby.":                                                      "Error uploading the maintenance page.",
+	"Stránka údržby je aktivní.":                                                               "Maintenance page is active.",
+	"Další spojení se nepodařilo otevřít.":                                                     "Failed to open another connection.",
+	"Nasazení bylo přerušeno, stránka údržby zůstává aktivní.":                                 "Deploy was cancelled, the maintenance page stays active.",
+	"Chyba při nahrávání souboru.":                                                             "Error uploading file.",
+	"neplatná hodnota %s '%s': %w":                                                             "invalid value %s '%s': %w",
+	"chyba při otevírání historie nasazení '%s': %w":                                           "error opening deploy history '%s': %w",
+	"chyba při zápisu historie nasazení '%s': %w":                                              "error writing deploy history '%s': %w",
+	"chyba v historii nasazení '%s' na řádku %d: %w":                                           "error in deploy history '%s' on line %d: %w",
+	"chyba při čtení historie nasazení '%s': %w":                                               "error reading deploy history '%s': %w",
+	"ČAS\tNÁZEV\tCÍL\tVÝSLEDEK\tCOMMIT\tMANIFEST\tSOUBORY\tADRESÁŘ":                            "TIME\tNAME\tTARGET\tRESULT\tCOMMIT\tMANIFEST\tFILES\tDIRECTORY",
+	"Přepisuji zámek jiného nasazení (--force).":                                               "Overwriting the lock of another deploy (--force).",
+	"Zámek je starší než lockTimeout, považuji ho za opuštěný.":                                "Lock is older than lockTimeout, treating it as abandoned.",
+	"nasazení již probíhá: zámek drží %s od %s (použijte --force, pokud jde o opuštěný zámek)": "a deploy is already running: lock held by %s since %s (use --force if the lock is abandoned)",
+	"zámek současně vytvořilo jiné nasazení (%s), nasazení přerušeno":                          "another deploy created the lock at the same time (%s), deploy aborted",
+	"chyba při serializaci zámku: %w":                                                          "error serializing lock: %w",
+	"chyba při vytváření zámku na serveru: %w":                                                 "error creating lock on the server: %w",
+	"chyba při čtení zámku ze serveru: %w":                                                     "error reading lock from the server: %w",
+	"chyba při odstraňování zámku ze serveru: %w":                                              "error removing lock from the server: %w",
+	"chyba při serializaci údajů o nasazení: %w":                                               "error serializing deploy info: %w",
+	"chyba při nahrávání souboru %s: %w":                                                       "error uploading file %s: %w",
+	"Údaje o nasazení se nepodařilo nahrát.":                                                   "Failed to upload the deploy info.",
+	"práva souborů nelze nastavit: %w":                                                         "cannot set file permissions: %w",
+	"chyba při nastavování práv souboru '%s': %w":                                              "error setting permissions of file '%s': %w",
+	"Souboru nastavena práva.":                                                                 "File permissions set.",
+	"server nepodporuje SITE CHMOD, práva souborů nebyla nastavena (%d %s)":                    "server does not support SITE CHMOD, file permissions not set (%d %s)",
+	"server odmítl nastavit práva souboru '%s': %d %s":                                         "server refused to set permissions of file '%s': %d %s",
+	"Zkušební běh, na server se nic nenahraje.":                                                "Dry run, nothing will be uploaded to the server.",
+	"Během nasazení by se zobrazila stránka údržby.":                                           "The maintenance page would be shown during the deploy.",
+	"Soubor by se nahrál.":                                                                     "File would be uploaded.",
+	"neznámá failurePolicy '%s', povoleno je \"continue\", \"abort\" nebo \"abort-after\"":     "unknown failurePolicy '%s', allowed are \"continue\", \"abort\" or \"abort-after\"",
+	"Nasazení proběhlo bez chyb.":                                                              "Deploy finished without errors.",
+	"Nasazení skončilo s chybami.":                                                             "Deploy finished with errors.",
+	"Soubor se nepodařilo nahrát.":                                                             "Failed to upload file.",
+	"Nasazení bylo přerušeno.":                                                                 "Deploy was cancelled.",
+	"Chyba nasazení.":                                                                          "Deploy error.",
+	"Souhrn nasazení.":                                                                         "Deploy summary.",
+	"soubor '%s' na serveru neexistuje":                                                        "file '%s' does not exist on the server",
+	"adresář '%s' na serveru neexistuje":                                                       "directory '%s' does not exist on the server",
+	"adresář '%s' na serveru již existuje":                                                     "directory '%s' already exists on the server",
+	"spojení se serverem je ukončené":                                                          "the connection to the server is closed",
+	"neočekávaná odpověď FTP serveru: %w":                                                      "unexpected FTP server response: %w",
+	"server odmítl šifrované spojení: %w":                                                      "server refused the encrypted connection: %w",
+	"neočekávaná odpověď %d na příkaz USER":                                                    "unexpected response %d to the USER command",
+	"commit nelze zjistit, nasazuje se mimo git repozitář":                                     "cannot determine the commit, deploying outside a git repository",
+	"neplatná šablona remoteDir '%s': %w":                                                      "invalid remoteDir template '%s': %w",
+	"chyba při vyhodnocení šablony remoteDir '%s': %w":                                         "error evaluating remoteDir template '%s': %w",
+	"Chyba při nasazení.":                                                                      "Error during deploy.",
+	"chyba při vytváření reportu '%s': %w":                                                     "error creating report '%s': %w",
+	"chyba při zápisu reportu '%s': %w":                                                        "error writing report '%s': %w",
+	"volné místo na serveru nelze zjistit: %w":                                                 "cannot determine free space on the server: %w",
+	"na serveru není dost místa: nasazení potřebuje %s, volných je jen %s":                     "not enough space on the server: the deploy needs %s, only %s is free",
+	"Na serveru je dost volného místa.":                                                        "There is enough free space on the server.",
+	"server nepodporuje příkaz '%s' (%d %s)":                                                   "server does not support command '%s' (%d %s)",
+	"odpověď '%s' na příkaz '%s' neobsahuje počet bajtů":                                       "response '%s' to command '%s' contains no byte count",
+	"chyba při výpisu adresáře '%s' na serveru: %w":                                            "error listing directory '%s' on the server: %w",
+	"Ověřování certifikátu serveru je vypnuto (insecureSkipVerify)! Spojení může odposlouchávat nebo podvrhnout kdokoli po cestě, heslo k FTP není v bezpečí.": "Server certificate verification is disabled (insecureSkipVerify)! Anyone on the path can eavesdrop on or spoof the connection, the FTP password is not safe.",
+	"server '%s' nepředložil certifikát":                                           "server '%s' presented no certificate",
+	"otisk certifikátu serveru '%s' (%s) neodpovídá nastavenému certFingerprint":   "certificate fingerprint of server '%s' (%s) does not match the configured certFingerprint",
+	"chyba při čtení certifikační autority '%s': %w":                               "error reading certificate authority '%s': %w",
+	"soubor '%s' neobsahuje žádný platný PEM certifikát":                           "file '%s' contains no valid PEM certificate",
+	"neplatný certFingerprint '%s', očekáván SHA-256 otisk v hexadecimálním tvaru": "invalid certFingerprint '%s', expected a SHA-256 fingerprint in hex",
+	"zadaným vzorům neodpovídá žádný soubor":                                       "no file matches the given patterns",
+	"seznam souborů k nasazení je prázdný":                                         "the list of files to deploy is empty",
+	"soubor '%s' nelze použít: %w":                                                 "file '%s' cannot be used: %w",
+	"'%s' není běžný soubor":                                                       "'%s' is not a regular file",
+	"mezi soubory chybí index.html":                                                "index.html is missing from the files",
+	"všechny soubory jsou prázdné":                                                 "all files are empty",
+	"celková velikost %d B je podezřele malá (minTotalSize je %d B)":               "total size %d B is suspiciously small (minTotalSize is %d B)",
+	"soubor '%s' neobsahuje platný JSON":                                           "file '%s' does not contain valid JSON",
+	"chyba při serializaci webhooku: %w":                                           "error serializing webhook: %w",
+	"chyba při volání webhooku '%s': %w":                                           "error calling webhook '%s': %w",
+	"webhook '%s' vrátil stav %s":                                                  "webhook '%s' returned status %s",
+}
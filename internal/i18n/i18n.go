@@ -0,0 +1,85 @@
+// Package i18n překládá hlášení programu do zvoleného jazyka (čeština
+// nebo angličtina).
+//
+// Klíčem katalogu je samotný text hlášení ve zdrojovém kódu, podobně jako
+// u gettextu: české texty překládá do angličtiny katalog en, anglické texty
+// (fáze build) do češtiny katalog cs. Text, který v katalogu zvoleného jazyka
+// chybí, se vypíše beze změny. Formátovací řetězce (%s, %d, %w) se překládají
+// celé, argumenty se dosadí až do přeloženého textu.
+//
+// Jazyk se volí podle klíče "language" v konfiguraci, bez něj podle proměnných
+// prostředí LC_ALL, LC_MESSAGES a LANG (viz Detect).
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Podporované jazyky.
+const (
+	CS = "cs" // Čeština, výchozí jazyk
+	EN = "en" // Angličtina, např. pro logy v CI
+)
+
+// Languages je seznam podporovaných jazyků.
+var Languages = []string{CS, EN}
+
+// catalogs přiřazuje jazyku katalog překladů podle zdrojového textu.
+var catalogs = map[string]map[string]string{
+	CS: cs,
+	EN: en,
+}
+
+var lang atomic.Value // Zvolený jazyk (string); bez nastavení CS
+
+// Set zvolí jazyk hlášení.
+func Set(language string) error {
+	if _, ok := catalogs[language]; !ok {
+		return Errorf("neznámý jazyk '%s', povoleno je \"cs\" nebo \"en\"", language)
+	}
+	lang.Store(language)
+	return nil
+}
+
+// Lang vrací zvolený jazyk hlášení.
+func Lang() string {
+	if l, ok := lang.Load().(string); ok {
+		return l
+	}
+	return CS
+}
+
+// Detect vrací jazyk podle nastavení prostředí: první neprázdná z proměnných
+// LC_ALL, LC_MESSAGES a LANG. Čeština (cs_CZ.UTF-8) i slovenština vyberou CS,
+// ostatní jazyky a locale C nebo POSIX (běžné v CI) vyberou EN. Bez nastavení
+// prostředí zůstane CS.
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := strings.ToLower(os.Getenv(name))
+		if locale == "" {
+			continue
+		}
+		if strings.HasPrefix(locale, "cs") || strings.HasPrefix(locale, "sk") {
+			return CS
+		}
+		return EN
+	}
+	return CS
+}
+
+// T vrací překlad textu do zvoleného jazyka, nebo text beze změny, pokud
+// ho katalog neobsahuje.
+func T(text string) string {
+	if translated, ok := catalogs[Lang()][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// Errorf je fmt.Errorf s přeloženým formátovacím řetězcem.
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
@@ -0,0 +1,179 @@
+// Package logging nastavuje společný strukturovaný log všech fází.
+//
+// Záznamy mají úroveň a jednotná pole: phase (convert, build, deploy, run),
+// target (název cíle nasazení), file (zpracovávaný soubor), error a během
+// běhu run (identifikátor běhu).
+// Textový výstup je určený pro člověka, výstup JSON pro sběr logů v CI.
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/runid"
+)
+
+// Formáty výstupu logu.
+const (
+	FormatText = "text" // Čitelný řádek: čas, úroveň, zpráva a pole klíč=hodnota
+	FormatJSON = "json" // Jeden objekt JSON na řádek
+)
+
+// Options určuje, kam a v jakém tvaru se log zapisuje.
+type Options struct {
+	Format string // FormatText nebo FormatJSON
+	Level  string // Nejnižší vypisovaná úroveň: debug, info, warn nebo error
+
+	File      string        // Soubor, do kterého se log zapisuje navíc; prázdný = jen standardní výstup
+	FileLevel string        // Nejnižší úroveň zapisovaná do souboru (výchozí Level)
+	MaxSize   int64         // Velikost v bajtech, po které se soubor odloží a začne se nový (0 = bez omezení)
+	MaxFiles  int           // Počet ponechaných odložených souborů (0 = bez omezení)
+	MaxAge    time.Duration // Stáří, po kterém se odložené soubory smažou (0 = bez omezení)
+
+	Journal  bool        // Výstup vede do žurnálu systemd: textové řádky bez času s prioritou <N> na začátku
+	EventLog LevelWriter // Protokol událostí Windows, do kterého se log zapisuje navíc (služba Windows)
+}
+
+// LevelWriter zapisuje řádky logu spolu s jejich úrovní, např. do protokolu událostí Windows.
+type LevelWriter interface {
+	WriteLevel(level slog.Level, line string) error
+}
+
+// Setup nastaví výchozí logger slog, do kterého zapisují všechny fáze.
+// Na tento logger se přesměruje i výstup balíčku log. Zprávy záznamů se
+// překládají do jazyka zvoleného balíčkem i18n. S nastaveným opts.File
+// se log zapisuje navíc do souboru, který se denně a po dosažení opts.MaxSize odloží,
+// s opts.EventLog navíc do protokolu událostí Windows.
+func Setup(w io.Writer, opts Options) error {
+	handler, err := newHandler(w, opts.Format, opts.Level)
+	if err != nil {
+		return err
+	}
+	if th, ok := handler.(*textHandler); ok {
+		th.journal = opts.Journal
+	}
+	if opts.EventLog != nil {
+		events, err := newHandler(nil, FormatText, opts.Level)
+		if err != nil {
+			return err
+		}
+		events.(*textHandler).events = opts.EventLog
+		handler = fanout{handler, events}
+	}
+
+	if opts.File != "" {
+		file, err := openRotating(opts.File, opts.MaxSize, opts.MaxFiles, opts.MaxAge)
+		if err != nil {
+			return err
+		}
+		level := opts.FileLevel
+		if level == "" {
+			level = opts.Level
+		}
+		fileHandler, err := newHandler(file, opts.Format, level)
+		if err != nil {
+			return err
+		}
+		handler = fanout{handler, fileHandler}
+	}
+	slog.SetDefault(slog.New(translated{handler}))
+	return nil
+}
+
+// newHandler vrací handler zadaného formátu, který zapisuje do w záznamy od úrovně level výše.
+func newHandler(w io.Writer, format, level string) (slog.Handler, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, i18n.Errorf("neznámá úroveň logu '%s', povoleno je debug, info, warn nebo error", level)
+	}
+	switch strings.ToLower(format) {
+	case FormatText:
+		return newTextHandler(w, lvl), nil
+	case FormatJSON:
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl}), nil
+	default:
+		return nil, i18n.Errorf("neznámý formát logu '%s', povoleno je \"text\" nebo \"json\"", format)
+	}
+}
+
+// fanout předává záznamy všem handlerům, z nichž každý má vlastní úroveň.
+type fanout []slog.Handler
+
+// Enabled vrací true, pokud záznam zapíše aspoň jeden z handlerů.
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle předá záznam handlerům, které ho na jeho úrovni zapisují.
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs vrací fanout, jehož handlery ke každému záznamu přidají attrs.
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+// WithGroup vrací fanout, jehož handlery vkládají další pole do skupiny name.
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// translated překládá zprávu každého záznamu do zvoleného jazyka (viz i18n.T).
+// Během běhu přidá pole run s identifikátorem běhu (viz runid), ostatní pole
+// záznamu zůstávají beze změny.
+type translated struct {
+	slog.Handler
+}
+
+// Handle předá handleru záznam s přeloženou zprávou.
+func (t translated) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, i18n.T(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(a)
+		return true
+	})
+	if id := runid.Current(); id != "" {
+		out.AddAttrs(slog.String("run", id))
+	}
+	return t.Handler.Handle(ctx, out)
+}
+
+// WithAttrs vrací handler, který ke každému záznamu přidá attrs.
+func (t translated) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return translated{t.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup vrací handler, který vkládá další pole do skupiny name.
+func (t translated) WithGroup(name string) slog.Handler {
+	return translated{t.Handler.WithGroup(name)}
+}
+
+// Phase vrací logger fáze zpracování, jehož záznamy mají pole phase.
+func Phase(name string) *slog.Logger {
+	return slog.Default().With("phase", name)
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// backupTimeFormat je formát času v názvu odloženého souboru logu, např. hugo72-20240501-183000.log.
+const backupTimeFormat = "20060102-150405"
+
+// rotatingFile je soubor logu, který se odloží pod jiným názvem a začne znovu,
+// když by překročil maxSize (je-li kladná) nebo když do něj má zapisovat až další den.
+// Každé spuštění tak zapisuje do stejného souboru, dokud nepřijde nový den.
+// Z odložených souborů se ponechá nejvýše maxFiles nejnovějších a žádný starší než maxAge.
+type rotatingFile struct {
+	mu        sync.Mutex
+	path      string
+	maxSize   int64
+	maxFiles  int
+	maxAge    time.Duration
+	file      *os.File
+	size      int64
+	lastWrite time.Time // Čas posledního zápisu, podle něj se pozná nový den
+}
+
+// openRotating otevře soubor logu pro připisování a odloží ho hned, pokud
+// patří k dřívějšímu dni nebo už je příliš velký.
+func openRotating(path string, maxSize int64, maxFiles int, maxAge time.Duration) (*rotatingFile, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, i18n.Errorf("chyba při vytváření adresáře logu '%s': %w", dir, err)
+		}
+	}
+	f := &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles, maxAge: maxAge}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	if f.size > 0 && ((maxSize > 0 && f.size >= maxSize) || !sameDay(f.lastWrite, time.Now())) {
+		if err := f.rotate(); err != nil {
+			return nil, err
+		}
+	}
+	f.cleanup()
+	return f, nil
+}
+
+// Write zapíše záznam do souboru, před tím ho případně odloží.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if f.size > 0 && ((f.maxSize > 0 && f.size+int64(len(p)) > f.maxSize) || !sameDay(f.lastWrite, now)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	f.lastWrite = now
+	return n, err
+}
+
+// open otevře soubor logu a zjistí jeho velikost a čas poslední změny.
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return i18n.Errorf("chyba při otevírání souboru logu '%s': %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return i18n.Errorf("chyba při otevírání souboru logu '%s': %w", f.path, err)
+	}
+	f.file, f.size, f.lastWrite = file, info.Size(), info.ModTime()
+	return nil
+}
+
+// rotate odloží aktuální soubor pod názvem s časem posledního zápisu,
+// otevře nový soubor a smaže odložené soubory nad limit.
+func (f *rotatingFile) rotate() error {
+	f.file.Close()
+	if err := os.Rename(f.path, f.backupName(f.lastWrite)); err != nil {
+		return i18n.Errorf("chyba při odkládání souboru logu '%s': %w", f.path, err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.cleanup()
+	return nil
+}
+
+// backupName vrací volný název odloženého souboru pro čas t.
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext) + "-" + t.Format(backupTimeFormat)
+	name := base + ext
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s.%d%s", base, i, ext)
+	}
+}
+
+// cleanup smaže odložené soubory starší než maxAge a nejstarší soubory nad počet maxFiles.
+// Chyby mazání se ignorují, log kvůli nim nesmí přestat fungovat.
+func (f *rotatingFile) cleanup() {
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(f.path, ext) + "-"
+	matches, _ := filepath.Glob(prefix + "*" + ext)
+
+	type backup struct {
+		path    string
+		modTime time.Time
+	}
+	var backups []backup
+	for _, m := range matches {
+		// Jen soubory s časem v názvu, jiné soubory s podobným názvem se nemažou.
+		stamp := strings.TrimPrefix(m, prefix)
+		if len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)]); err != nil {
+			continue
+		}
+		if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() {
+			backups = append(backups, backup{m, info.ModTime()})
+		}
+	}
+	// Od nejnovějšího po nejstarší.
+	slices.SortFunc(backups, func(a, b backup) int { return b.modTime.Compare(a.modTime) })
+
+	for i, b := range backups {
+		if (f.maxFiles > 0 && i >= f.maxFiles) || (f.maxAge > 0 && time.Since(b.modTime) > f.maxAge) {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// sameDay vrací true, pokud oba časy spadají do stejného kalendářního dne.
+func sameDay(a, b time.Time) bool {
+	ya, ma, da := a.Date()
+	yb, mb, db := b.Date()
+	return ya == yb && ma == mb && da == db
+}
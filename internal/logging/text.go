@@ -0,0 +1,161 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// textHandler vypisuje záznamy ve tvaru
+//
+//	2024/05/01 18:30:00 [staging] Soubor byl úspěšně nahrán na server. file=index.html
+//
+// Úroveň se vypisuje jen u jiných záznamů než INFO. Název cíle se vypisuje jako předpona,
+// aby šlo souběžné nasazení na více cílů snadno sledovat; pole phase se vynechá,
+// protože je z textu zprávy zřejmé. Pole error se vypisuje nakonec bez uvozovek
+// a víceřádkové chyby (např. seznam problémů konfigurace) se odsadí na další řádky.
+//
+// Pro žurnál systemd a protokol událostí Windows, které si čas zapisují samy,
+// se čas vynechá; v žurnálu začíná řádek prioritou záznamu, např. <4> u WARN.
+type textHandler struct {
+	mu      *sync.Mutex
+	w       io.Writer
+	level   slog.Leveler
+	attrs   []slog.Attr
+	groups  string      // Předpona klíčů ze skupin (WithGroup), např. "upload."
+	journal bool        // Řádky pro žurnál systemd
+	events  LevelWriter // Zapisovat do protokolu událostí místo do w
+}
+
+// journalPriority vrací předponu řádku s prioritou syslogu, podle které žurnál
+// systemd určí úroveň záznamu.
+func journalPriority(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "<3>"
+	case level >= slog.LevelWarn:
+		return "<4>"
+	case level >= slog.LevelInfo:
+		return "<6>"
+	default:
+		return "<7>"
+	}
+}
+
+// newTextHandler vrací handler, který zapisuje záznamy od úrovně level výše.
+func newTextHandler(w io.Writer, level slog.Leveler) *textHandler {
+	return &textHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+// Enabled vrací true pro záznamy, které se mají vypsat.
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle zapíše jeden záznam jako řádek textu.
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var b bytes.Buffer
+	switch {
+	case h.journal:
+		b.WriteString(journalPriority(r.Level))
+	case h.events != nil:
+	case !r.Time.IsZero():
+		b.WriteString(r.Time.Format("2006/01/02 15:04:05 "))
+	}
+	if r.Level != slog.LevelInfo {
+		b.WriteString(r.Level.String())
+		b.WriteByte(' ')
+	}
+
+	var fields []slog.Attr
+	var errValue string
+	collect := func(a slog.Attr) bool {
+		switch a.Key {
+		case "phase":
+		case "target":
+			fmt.Fprintf(&b, "[%s] ", a.Value)
+		case "error":
+			errValue = a.Value.String()
+		default:
+			fields = append(fields, a)
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		collect(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		a.Key = h.groups + a.Key
+		return collect(a)
+	})
+
+	b.WriteString(r.Message)
+	for _, a := range fields {
+		appendAttr(&b, "", a)
+	}
+	if errValue != "" {
+		b.WriteString(" error: ")
+		b.WriteString(strings.ReplaceAll(errValue, "\n", "\n    "))
+	}
+	if h.events != nil {
+		return h.events.WriteLevel(r.Level, b.String())
+	}
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(b.Bytes())
+	return err
+}
+
+// appendAttr připíše pole ve tvaru klíč=hodnota. Hodnoty s mezerami se uzavřou do uvozovek.
+func appendAttr(b *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			appendAttr(b, prefix+a.Key+".", ga)
+		}
+		return
+	}
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = fmt.Sprintf("%q", value)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, a.Key, value)
+}
+
+// WithAttrs vrací handler, který ke každému záznamu přidá attrs.
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], prefixed(h.groups, attrs)...)
+	return &h2
+}
+
+// WithGroup vrací handler, jehož další pole mají klíče s předponou skupiny.
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = h.groups + name + "."
+	return &h2
+}
+
+// prefixed vrací pole s klíči doplněnými o předponu skupin.
+func prefixed(prefix string, attrs []slog.Attr) []slog.Attr {
+	if prefix == "" {
+		return attrs
+	}
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = slog.Attr{Key: prefix + a.Key, Value: a.Value}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+// Package mailer odesílá e-maily přes SMTP a podepisuje odkazy, kterými
+// účastníci z pozvánky otevřou předvyplněný formulář přihlášky.
+package mailer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// DefaultPort je port SMTP serveru pro odesílání pošty (submission).
+const DefaultPort = 587
+
+// Server je SMTP server, přes který se pošta odesílá.
+type Server struct {
+	Host     string
+	Port     int // Bez hodnoty DefaultPort
+	Username string
+	Password string
+}
+
+// Message je jeden e-mail s textovým obsahem.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+	Date    time.Time
+}
+
+// Send odešle zprávu. Pokud server nabízí STARTTLS, spojení se zašifruje;
+// přihlašovací údaje se bez šifrování posílají jen na localhost.
+func Send(server Server, msg Message) error {
+	port := server.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	addr := net.JoinHostPort(server.Host, strconv.Itoa(port))
+	var auth smtp.Auth
+	if server.Username != "" {
+		auth = smtp.PlainAuth("", server.Username, server.Password, server.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, msg.From, msg.To, compose(msg)); err != nil {
+		return i18n.Errorf("chyba při odesílání e-mailu přes '%s': %w", addr, err)
+	}
+	return nil
+}
+
+// compose sestaví zprávu ve formátu RFC 5322 s textem v UTF-8.
+func compose(msg Message) []byte {
+	date := msg.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", msg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+// Token vrací podpis e-mailové adresy účastníka klíčem secret. Token v odkazu
+// prokazuje, že odkaz pochází z pozvánky poslané na tuto adresu.
+func Token(secret, email string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.ToLower(email)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// ValidToken vrací true, pokud token patří k e-mailové adrese.
+func ValidToken(secret, email, token string) bool {
+	return hmac.Equal([]byte(Token(secret, email)), []byte(token))
+}
@@ -0,0 +1,136 @@
+// Package notify posílá pořadatelům oznámení o výsledku běhu e-mailem (SMTP),
+// do Slacku, Telegramu, Discordu nebo na obecný webhook.
+//
+// Oznámení se posílají o událostech runFailure (běh selhal), deploySuccess
+// (web byl nasazen) a newRegistrations (od posledního převodu přibyly přihlášky).
+// Které události kanál dostává, určuje jeho "on", jinak notify.on v konfiguraci;
+// bez nastavení se posílá jen runFailure. Text zprávy lze pro každou událost
+// nahradit šablonou text/template s poli typu Event, např.
+// "Běh selhal ve fázi {{.Phase}}: {{.Error}}".
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"text/template"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/pkg/config"
+)
+
+// Události, o kterých lze posílat oznámení.
+const (
+	RunFailure       = "runFailure"       // Běh pipeline selhal
+	DeploySuccess    = "deploySuccess"    // Nasazení proběhlo úspěšně
+	NewRegistrations = "newRegistrations" // Od posledního převodu přibyly přihlášky
+)
+
+// sendTimeout je nejdelší doba odesílání oznámení jedním kanálem.
+const sendTimeout = 15 * time.Second
+
+// Event je událost, o které se posílá oznámení. Pole jsou dostupná v šabloně zprávy.
+type Event struct {
+	Name  string    // Jedna z RunFailure, DeploySuccess, NewRegistrations
+	Time  time.Time // Okamžik události
+	Host  string    // Počítač, na kterém běh proběhl
+	RunID string    // Identifikátor běhu, podle kterého se dohledá jeho log
+
+	Phase string // Fáze, která selhala (RunFailure)
+	Error string // Popis chyby (RunFailure)
+
+	Targets       []string // Nasazené cíle (DeploySuccess)
+	Commit        string   // Commit nasazeného obsahu, pokud je znám (DeploySuccess)
+	FilesUploaded int      // Počet nahraných souborů (DeploySuccess)
+
+	Records          int64 // Celkový počet přihlášek (NewRegistrations)
+	NewRegistrations int64 // Počet přihlášek, které přibyly od posledního převodu (NewRegistrations)
+}
+
+// subjects jsou předměty zpráv (e-mail, webhook) podle události.
+var subjects = map[string]string{
+	RunFailure:       "hugo72: běh selhal",
+	DeploySuccess:    "hugo72: web nasazen",
+	NewRegistrations: "hugo72: nové přihlášky",
+}
+
+// defaultTemplates jsou texty zpráv, pokud je notify.templates nenahradí.
+var defaultTemplates = map[string]string{
+	RunFailure:       "Běh hugo72 na {{.Host}} selhal{{if .Phase}} ve fázi {{.Phase}}{{end}}: {{.Error}}{{if .RunID}} (běh {{.RunID}}){{end}}",
+	DeploySuccess:    "Web byl nasazen na {{range $i, $t := .Targets}}{{if $i}}, {{end}}{{$t}}{{end}}, nahráno souborů: {{.FilesUploaded}}.{{if .Commit}} Commit {{.Commit}}.{{end}}{{if .RunID}} Běh {{.RunID}}.{{end}}",
+	NewRegistrations: "Od posledního převodu přibylo přihlášek: {{.NewRegistrations}}, celkem {{.Records}}.",
+}
+
+// message je vykreslené oznámení připravené k odeslání.
+type message struct {
+	subject string
+	text    string
+	event   Event
+}
+
+// Send odešle oznámení o události všem kanálům, které ji odebírají. Selhání
+// jednoho kanálu nebrání odeslání ostatními; chyby všech kanálů se vrátí společně.
+func Send(ctx context.Context, cfg *config.Notify, event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Host == "" {
+		event.Host, _ = os.Hostname()
+	}
+
+	var msg *message
+	var errs []error
+	for i, ch := range cfg.Channels {
+		if !subscribed(cfg, ch, event.Name) {
+			continue
+		}
+		if msg == nil {
+			text, err := render(cfg, event)
+			if err != nil {
+				return err
+			}
+			msg = &message{subject: subjects[event.Name], text: text, event: event}
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := send(sendCtx, ch, *msg)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify.channels[%d] (%s): %w", i, ch.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subscribed vrací true, pokud kanál odebírá událost.
+func subscribed(cfg *config.Notify, ch config.NotifyChannel, event string) bool {
+	on := ch.On
+	if len(on) == 0 {
+		on = cfg.On
+	}
+	if len(on) == 0 {
+		on = []string{RunFailure}
+	}
+	return slices.Contains(on, event)
+}
+
+// render vykreslí text zprávy ze šablony z konfigurace, nebo z výchozí šablony.
+func render(cfg *config.Notify, event Event) (string, error) {
+	source, ok := cfg.Templates[event.Name]
+	if !ok {
+		source = defaultTemplates[event.Name]
+	}
+	tmpl, err := template.New(event.Name).Parse(source)
+	if err != nil {
+		return "", i18n.Errorf("chyba v šabloně oznámení '%s': %w", event.Name, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, event); err != nil {
+		return "", i18n.Errorf("chyba při vykreslení oznámení '%s': %w", event.Name, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/mailer"
+	"hugo72/pkg/config"
+)
+
+// telegramAPI je adresa Bot API Telegramu; za ní následuje token bota.
+const telegramAPI = "https://api.telegram.org/bot"
+
+// webhookPayload je JSON zpráva pro kanál typu webhook.
+//
+// Příklad:
+//
+//	{
+//	  "event": "runFailure",
+//	  "subject": "hugo72: běh selhal",
+//	  "text": "Běh hugo72 na server selhal ve fázi deploy: ...",
+//	  "time": "2025-06-01T10:00:00+02:00",
+//	  "host": "server",
+//	  "runId": "20250601-100000-3f9a2c1b",
+//	  "phase": "deploy",
+//	  "error": "..."
+//	}
+type webhookPayload struct {
+	Event            string    `json:"event"`
+	Subject          string    `json:"subject"`
+	Text             string    `json:"text"`
+	Time             time.Time `json:"time"`
+	Host             string    `json:"host"`
+	RunID            string    `json:"runId,omitempty"`
+	Phase            string    `json:"phase,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	Targets          []string  `json:"targets,omitempty"`
+	Commit           string    `json:"commit,omitempty"`
+	FilesUploaded    int       `json:"filesUploaded,omitempty"`
+	Records          int64     `json:"records,omitempty"`
+	NewRegistrations int64     `json:"newRegistrations,omitempty"`
+}
+
+// send odešle zprávu jedním kanálem podle jeho typu.
+func send(ctx context.Context, ch config.NotifyChannel, msg message) error {
+	switch ch.Type {
+	case "smtp":
+		return sendSMTP(ch, msg)
+	case "slack":
+		return postJSON(ctx, ch.URL, map[string]string{"text": msg.text})
+	case "discord":
+		return postJSON(ctx, ch.URL, map[string]string{"content": msg.text})
+	case "telegram":
+		return postJSON(ctx, telegramAPI+ch.BotToken+"/sendMessage", map[string]string{"chat_id": ch.ChatID, "text": msg.text})
+	case "webhook":
+		e := msg.event
+		return postJSON(ctx, ch.URL, webhookPayload{
+			Event:            e.Name,
+			Subject:          msg.subject,
+			Text:             msg.text,
+			Time:             e.Time,
+			Host:             e.Host,
+			RunID:            e.RunID,
+			Phase:            e.Phase,
+			Error:            e.Error,
+			Targets:          e.Targets,
+			Commit:           e.Commit,
+			FilesUploaded:    e.FilesUploaded,
+			Records:          e.Records,
+			NewRegistrations: e.NewRegistrations,
+		})
+	}
+	return i18n.Errorf("neznámý typ kanálu '%s'", ch.Type)
+}
+
+// postJSON odešle payload metodou POST jako JSON. Odpověď mimo rozsah 2xx je
+// považována za chybu. Adresa se do chyby nevypisuje, protože může obsahovat
+// tajný token (Slack, Discord, Telegram).
+func postJSON(ctx context.Context, target string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci oznámení: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return errors.New(i18n.T("neplatná adresa kanálu"))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return i18n.Errorf("chyba při odesílání oznámení: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return i18n.Errorf("služba vrátila stav %s", resp.Status)
+	}
+	return nil
+}
+
+// sendSMTP odešle zprávu e-mailem.
+func sendSMTP(ch config.NotifyChannel, msg message) error {
+	return mailer.Send(
+		mailer.Server{Host: ch.Host, Port: ch.Port, Username: ch.Username, Password: ch.Password},
+		mailer.Message{From: ch.From, To: ch.To, Subject: msg.subject, Body: msg.text, Date: msg.event.Time},
+	)
+}
@@ -0,0 +1,87 @@
+// Package output předává výsledky příkazů skriptům ve strojově čitelné podobě.
+//
+// Příkazy popisují svůj výsledek funkcí Result. S globálním přepínačem --json
+// se po skončení příkazu vypíše na standardní výstup jediný objekt
+//
+//	{"command": "deploy", "ok": true, "exitCode": 0, "result": {...}}
+//
+// případně s položkou "error", pokud příkaz selhal. Bez přepínače --json se
+// výsledek nevypisuje, průběh i výsledek popisuje log.
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"hugo72/internal/exitcode"
+)
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	result  any
+)
+
+// Enable zapne výpis výsledků ve formátu JSON.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+}
+
+// Enabled vrací true, pokud se výsledky vypisují ve formátu JSON. Příkazy, které
+// jinak píšou na standardní výstup text (např. tabulku), ho pak vynechají.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Result zaznamená výsledek příkazu. Pozdější volání předchozí výsledek nahradí.
+func Result(v any) {
+	mu.Lock()
+	defer mu.Unlock()
+	result = v
+}
+
+// Peek vrací zaznamenaný výsledek, aniž by ho zapomněl.
+func Peek() any {
+	mu.Lock()
+	defer mu.Unlock()
+	return result
+}
+
+// Take vrací zaznamenaný výsledek a zapomene ho. Slouží příkazům, které spouští
+// jiné příkazy a jejich výsledky skládají do vlastního (např. run).
+func Take() any {
+	mu.Lock()
+	defer mu.Unlock()
+	v := result
+	result = nil
+	return v
+}
+
+// summary je objekt, který se po skončení příkazu vypíše na standardní výstup.
+type summary struct {
+	Command  string `json:"command"`
+	OK       bool   `json:"ok"`
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error,omitempty"`
+	Result   any    `json:"result,omitempty"`
+}
+
+// Write vypíše výsledek příkazu command do w, pokud je zapnutý výpis ve formátu JSON.
+// Chyba err označí příkaz jako neúspěšný.
+func Write(w io.Writer, command string, err error) error {
+	if !Enabled() {
+		return nil
+	}
+	s := summary{Command: command, OK: err == nil, ExitCode: int(exitcode.From(err)), Result: Take()}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
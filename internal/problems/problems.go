@@ -0,0 +1,109 @@
+// Package problems sbírá chyby a varování, po kterých běh pokračuje.
+//
+// Fáze, která může po chybě bezpečně pokračovat (nenahraný soubor při
+// failurePolicy "continue", nezapsaný report, neodeslaný webhook, háček
+// s onFailure "continue"), ji kromě zápisu do logu zaznamená funkcí Add nebo
+// Warn. Na konci běhu se všechny problémy vypíšou v souhrnné tabulce (fáze,
+// položka, chyba) a chyby, na rozdíl od varování, určí návratový kód běhu.
+package problems
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// Problem je jedna chyba nebo varování zaznamenané během běhu.
+type Problem struct {
+	Phase   string // Fáze, ve které problém nastal (convert, build, deploy, run)
+	Item    string // Čeho se problém týká (soubor, cíl, háček); prázdné = celé fáze
+	Err     error
+	Warning bool // Varování neovlivní výsledek běhu
+}
+
+// MarshalJSON vrací problém pro výpis --json, chybu jako text.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Phase   string `json:"phase"`
+		Item    string `json:"item,omitempty"`
+		Error   string `json:"error"`
+		Warning bool   `json:"warning,omitempty"`
+	}{p.Phase, p.Item, p.Err.Error(), p.Warning})
+}
+
+// List je strukturovaná chyba složená z více problémů.
+type List []Problem
+
+// Error vrací problémy po řádcích ve tvaru "fáze: položka: chyba".
+func (l List) Error() string {
+	lines := make([]string, len(l))
+	for i, p := range l {
+		lines[i] = p.Phase + ": "
+		if p.Item != "" {
+			lines[i] += p.Item + ": "
+		}
+		lines[i] += p.Err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap vrací jednotlivé chyby, aby je našly errors.Is a errors.As.
+func (l List) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, p := range l {
+		errs[i] = p.Err
+	}
+	return errs
+}
+
+// Errors vrací jen chyby, bez varování.
+func (l List) Errors() List {
+	var errs List
+	for _, p := range l {
+		if !p.Warning {
+			errs = append(errs, p)
+		}
+	}
+	return errs
+}
+
+var (
+	mu      sync.Mutex
+	active  bool
+	current List
+)
+
+// Start zahájí sběr problémů nového běhu. Mimo běh se problémy nesbírají.
+func Start() {
+	mu.Lock()
+	defer mu.Unlock()
+	active, current = true, nil
+}
+
+// Take ukončí sběr a vrátí problémy zaznamenané od Start v pořadí, v jakém nastaly.
+func Take() List {
+	mu.Lock()
+	defer mu.Unlock()
+	list := current
+	active, current = false, nil
+	return list
+}
+
+// Add zaznamená chybu, po které fáze pokračovala. Lze volat souběžně.
+func Add(phase, item string, err error) {
+	record(Problem{Phase: phase, Item: item, Err: err})
+}
+
+// Warn zaznamená varování. Lze volat souběžně.
+func Warn(phase, item string, err error) {
+	record(Problem{Phase: phase, Item: item, Err: err, Warning: true})
+}
+
+// record připojí problém k probíhajícímu běhu.
+func record(p Problem) {
+	mu.Lock()
+	defer mu.Unlock()
+	if active && p.Err != nil {
+		current = append(current, p)
+	}
+}
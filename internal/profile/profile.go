@@ -0,0 +1,128 @@
+// Package profile měří, kolik času zabírají jednotlivé kroky běhu.
+//
+// Fáze změří své kroky (čtení Excel souboru, zápis JSON, háčky, Hugo,
+// nahrávání souborů...) funkcí Track nebo Observe. Opakované kroky se
+// sčítají: u každého kroku se eviduje počet, celková, nejkratší a nejdelší
+// doba a počet zpracovaných bajtů. Nahrávání souborů se tak dá rozdělit do
+// skupin podle velikosti a je vidět, zda čas zabírají tisíce malých souborů,
+// nebo několik velkých. Na konci běhu vrátí Take profil pro report (viz
+// přepínač --profile).
+//
+// Kroky běžící souběžně (nahrávání více spojeními, nasazení na více cílů) se
+// sčítají, jejich součet proto může být delší než doba běhu.
+package profile
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Step je souhrn jednoho kroku běhu.
+type Step struct {
+	Phase string        // Fáze, do které krok patří (convert, build, deploy)
+	Name  string        // Název kroku; prázdný = celá fáze
+	Count int           // Kolikrát krok proběhl
+	Total time.Duration // Celková doba všech opakování
+	Min   time.Duration
+	Max   time.Duration
+	Bytes int64 // Zpracované bajty (u nahrávání velikost souborů)
+}
+
+// MarshalJSON vrací krok pro report, doby v sekundách.
+func (s Step) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Phase        string  `json:"phase"`
+		Name         string  `json:"step,omitempty"`
+		Count        int     `json:"count"`
+		TotalSeconds float64 `json:"totalSeconds"`
+		MinSeconds   float64 `json:"minSeconds"`
+		MaxSeconds   float64 `json:"maxSeconds"`
+		Bytes        int64   `json:"bytes,omitempty"`
+	}{s.Phase, s.Name, s.Count, s.Total.Seconds(), s.Min.Seconds(), s.Max.Seconds(), s.Bytes})
+}
+
+// Average vrací průměrnou dobu jednoho opakování kroku.
+func (s Step) Average() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// Profile je profil jednoho běhu: kroky v pořadí, v jakém poprvé proběhly.
+type Profile struct {
+	RunID     string        `json:"runId,omitempty"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"-"`
+	Steps     []Step        `json:"steps"`
+}
+
+// MarshalJSON vrací profil pro report, dobu běhu v sekundách.
+func (p Profile) MarshalJSON() ([]byte, error) {
+	type plain Profile
+	return json.Marshal(struct {
+		plain
+		DurationSeconds float64 `json:"durationSeconds"`
+	}{plain(p), p.Duration.Seconds()})
+}
+
+// key identifikuje krok.
+type key struct{ phase, name string }
+
+var (
+	mu      sync.Mutex
+	active  bool
+	started time.Time
+	steps   map[key]*Step
+	order   []key
+)
+
+// Start zahájí měření nového běhu. Mimo běh se nic neměří.
+func Start() {
+	mu.Lock()
+	defer mu.Unlock()
+	active, started, steps, order = true, time.Now(), map[key]*Step{}, nil
+}
+
+// Take ukončí měření a vrátí profil běhu od Start.
+func Take() Profile {
+	mu.Lock()
+	defer mu.Unlock()
+	p := Profile{StartedAt: started, Duration: time.Since(started)}
+	for _, k := range order {
+		p.Steps = append(p.Steps, *steps[k])
+	}
+	active, steps, order = false, nil, nil
+	return p
+}
+
+// Observe zaznamená jedno proběhnutí kroku name fáze phase, které trvalo d
+// a zpracovalo bytes bajtů. Lze volat souběžně.
+func Observe(phase, name string, d time.Duration, bytes int64) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !active {
+		return
+	}
+	k := key{phase, name}
+	s, ok := steps[k]
+	if !ok {
+		s = &Step{Phase: phase, Name: name, Min: d}
+		steps[k] = s
+		order = append(order, k)
+	}
+	s.Count++
+	s.Total += d
+	s.Bytes += bytes
+	s.Min = min(s.Min, d)
+	s.Max = max(s.Max, d)
+}
+
+// Track začne měřit krok a vrátí funkci, která měření ukončí:
+//
+//	defer profile.Track("convert", "read excel")()
+func Track(phase, name string) func() {
+	began := time.Now()
+	return func() { Observe(phase, name, time.Since(began), 0) }
+}
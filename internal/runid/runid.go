@@ -0,0 +1,44 @@
+// Package runid přiděluje každému běhu (příkaz run, běh démona watch nebo
+// serve, samostatně spuštěná fáze) jednoznačný identifikátor.
+//
+// Identifikátor probíhajícího běhu nese každý záznam logu (pole run), report
+// a historie nasazení, historie běhů, soubor deployed.json nahraný na server,
+// data háčků a oznámení. Problém na živém webu tak lze podle deployed.json
+// dohledat až ke konkrétnímu běhu a jeho logu.
+package runid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+// current je identifikátor probíhajícího běhu (string); prázdný mimo běh.
+var current atomic.Value
+
+// New vrací nový identifikátor ve tvaru 20240501-183000-3f9a2c1b: čas
+// zahájení, podle kterého se běhy dají řadit, a náhodná přípona.
+func New() string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Start zahájí běh s novým identifikátorem a vrátí ho.
+func Start() string {
+	id := New()
+	current.Store(id)
+	return id
+}
+
+// End ukončí běh; záznamy logu už identifikátor nenesou.
+func End() {
+	current.Store("")
+}
+
+// Current vrací identifikátor probíhajícího běhu, nebo prázdný řetězec mimo běh.
+func Current() string {
+	id, _ := current.Load().(string)
+	return id
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+)
+
+// IsWindowsService vrací true, pokud program spustil správce služeb Windows.
+// Mimo Windows vždy false.
+func IsWindowsService() bool {
+	return false
+}
+
+// Run spustí příkaz run. Mimo Windows není co obsluhovat.
+func Run(ctx context.Context, run func(context.Context) error, _ func()) error {
+	return run(ctx)
+}
+
+// Install je dostupné jen ve Windows.
+func Install(_, _, _ string, _ []string) error {
+	return errors.New(i18n.T("služby Windows lze instalovat jen ve Windows"))
+}
+
+// Uninstall je dostupné jen ve Windows.
+func Uninstall(_ string) error {
+	return errors.New(i18n.T("služby Windows lze instalovat jen ve Windows"))
+}
+
+// OpenEventLog je dostupné jen ve Windows.
+func OpenEventLog() (logging.LevelWriter, error) {
+	return nil, errors.New(i18n.T("protokol událostí je dostupný jen ve Windows"))
+}
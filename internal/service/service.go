@@ -0,0 +1,83 @@
+// Package service umožňuje provozovat démony hugo72 (watch, serve) jako
+// službu systemd nebo službu Windows.
+//
+// Pod systemd oznamuje službě připravenost, nové načtení konfigurace
+// a ukončení (Type=notify), udržuje watchdog (WatchdogSec) a pozná, že log
+// vede do žurnálu. Ve Windows obsluhuje správce služeb: zastavení služby
+// zruší kontext příkazu a příkaz ParamChange znovu načte konfiguraci; log
+// se zapisuje do protokolu událostí.
+package service
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// EventSource je zdroj, pod kterým služba Windows zapisuje do protokolu událostí.
+const EventSource = "hugo72"
+
+// Stavy služby oznamované systemd.
+const (
+	Ready     = "READY=1"     // Služba je připravená
+	Reloading = "RELOADING=1" // Služba znovu načítá konfiguraci; po dokončení se oznámí Ready
+	Stopping  = "STOPPING=1"  // Služba se ukončuje
+)
+
+// Notify oznámí systemd stav služby. Bez proměnné NOTIFY_SOCKET (program
+// nespustil systemd se službou Type=notify) nedělá nic.
+func Notify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	// Adresa začínající @ je abstraktní socket Linuxu.
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return i18n.Errorf("chyba při oznámení stavu služby systemd: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return i18n.Errorf("chyba při oznámení stavu služby systemd: %w", err)
+	}
+	return nil
+}
+
+// Watchdog posílá systemd signál watchdogu v polovině intervalu WatchdogSec,
+// dokud není zrušen ctx. Bez watchdogu v jednotce služby nedělá nic.
+func Watchdog(ctx context.Context) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(usec) * time.Microsecond / 2)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				Notify("WATCHDOG=1")
+			}
+		}
+	}()
+}
+
+// Journal vrací true, pokud standardní chybový výstup vede do žurnálu systemd.
+// Žurnál si zapisuje čas sám a úroveň záznamu čte z předpony <N> řádku.
+func Journal() bool {
+	return os.Getenv("JOURNAL_STREAM") != ""
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strings"
+	"text/template"
+)
+
+// UnitOptions popisují jednotku systemd, která spouští démona hugo72.
+type UnitOptions struct {
+	Description string   // Popis služby
+	WorkingDir  string   // Adresář projektu; relativní cesty v konfiguraci se berou vůči němu
+	Exec        []string // Program a jeho argumenty, např. ["/usr/local/bin/hugo72", "--config", "config.json", "serve"]
+	User        bool     // Uživatelská jednotka (systemctl --user) místo systémové
+}
+
+// unitTemplate je šablona jednotky. Type=notify čeká s ohlášením startu na
+// Notify(Ready), systemctl reload pošle SIGHUP a dlouhý TimeoutStopSec dá
+// rozběhnutému nasazení čas po SIGTERM uklidit a uvolnit zámek na serveru.
+// Démon, který do WatchdogSec neohlásí, že žije (viz Watchdog), systemd
+// ukončí a díky Restart=on-failure znovu spustí.
+var unitTemplate = template.Must(template.New("unit").Parse(`[Unit]
+Description={{.Description}}
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=notify
+WorkingDirectory={{.WorkingDir}}
+ExecStart={{.ExecStart}}
+ExecReload=/bin/kill -HUP $MAINPID
+Restart=on-failure
+RestartSec=10
+WatchdogSec=60
+TimeoutStopSec=120
+
+[Install]
+WantedBy={{if .User}}default.target{{else}}multi-user.target{{end}}
+`))
+
+// Unit vrací obsah souboru jednotky systemd.
+func Unit(opts UnitOptions) string {
+	args := make([]string, len(opts.Exec))
+	for i, a := range opts.Exec {
+		args[i] = quoteUnitArg(a)
+	}
+	var b strings.Builder
+	unitTemplate.Execute(&b, struct {
+		UnitOptions
+		ExecStart string
+	}{opts, strings.Join(args, " ")})
+	return b.String()
+}
+
+// quoteUnitArg uzavře do uvozovek argument s mezerami nebo zvláštními znaky
+// tak, jak ho v ExecStart čte systemd. Znak % by systemd bral jako zástupný symbol.
+func quoteUnitArg(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\$;") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`)
+	return `"` + r.Replace(s) + `"`
+}
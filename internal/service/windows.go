@@ -0,0 +1,150 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+)
+
+// IsWindowsService vrací true, pokud program spustil správce služeb Windows.
+func IsWindowsService() bool {
+	ok, err := svc.IsWindowsService()
+	return err == nil && ok
+}
+
+// Run spustí příkaz run jako službu Windows. Zastavení služby (i při vypnutí
+// počítače) zruší kontext příkazu, příkaz ParamChange (sc control <služba>
+// paramchange) zavolá reload. Vrací chybu příkazu.
+func Run(ctx context.Context, run func(context.Context) error, reload func()) error {
+	h := &handler{ctx: ctx, run: run, reload: reload}
+	if err := svc.Run(EventSource, h); err != nil {
+		return i18n.Errorf("chyba při spouštění služby Windows: %w", err)
+	}
+	return h.err
+}
+
+// handler obsluhuje požadavky správce služeb.
+type handler struct {
+	ctx    context.Context
+	run    func(context.Context) error
+	reload func()
+	err    error // Chyba, se kterou příkaz skončil
+}
+
+// Execute spustí příkaz a do jeho skončení předává požadavky správce služeb.
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	const accepts = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptParamChange
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(h.ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- h.run(ctx) }()
+	changes <- svc.Status{State: svc.Running, Accepts: accepts}
+
+	for {
+		select {
+		case h.err = <-done:
+			changes <- svc.Status{State: svc.StopPending}
+			if h.err != nil {
+				return true, 1
+			}
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				changes <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				cancel()
+			case svc.ParamChange:
+				h.reload()
+				changes <- req.CurrentStatus
+			}
+		}
+	}
+}
+
+// Install zaregistruje automaticky spouštěnou službu name, která spustí program
+// exe s argumenty args, a zdroj EventSource pro protokol událostí.
+func Install(name, description, exe string, args []string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return i18n.Errorf("chyba při připojení ke správci služeb: %w", err)
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(name); err == nil {
+		s.Close()
+		return i18n.Errorf("služba '%s' již existuje", name)
+	}
+	s, err := m.CreateService(name, exe, mgr.Config{
+		DisplayName: description,
+		Description: description,
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return i18n.Errorf("chyba při vytváření služby '%s': %w", name, err)
+	}
+	defer s.Close()
+
+	// Zdroj může zůstat z dřívější instalace nebo ho sdílí jiná služba hugo72.
+	if err := eventlog.InstallAsEventCreate(EventSource, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil {
+		slog.Debug("Zdroj protokolu událostí nelze zaregistrovat.", "source", EventSource, "error", err)
+	}
+	return nil
+}
+
+// Uninstall odstraní službu name. Zdroj protokolu událostí zůstane, aby šly
+// číst dřívější záznamy a aby ho mohly dál používat jiné služby hugo72.
+func Uninstall(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return i18n.Errorf("chyba při připojení ke správci služeb: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return i18n.Errorf("služba '%s' neexistuje: %w", name, err)
+	}
+	defer s.Close()
+	if err := s.Delete(); err != nil {
+		return i18n.Errorf("chyba při odstraňování služby '%s': %w", name, err)
+	}
+	return nil
+}
+
+// OpenEventLog otevře protokol událostí Windows pro zápis logu.
+func OpenEventLog() (logging.LevelWriter, error) {
+	l, err := eventlog.Open(EventSource)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při otevírání protokolu událostí: %w", err)
+	}
+	return eventLog{l}, nil
+}
+
+// eventLog zapisuje řádky logu do protokolu událostí podle jejich úrovně.
+type eventLog struct {
+	l *eventlog.Log
+}
+
+// WriteLevel zapíše řádek jako chybu, varování nebo informaci.
+func (e eventLog) WriteLevel(level slog.Level, line string) error {
+	switch {
+	case level >= slog.LevelError:
+		return e.l.Error(1, line)
+	case level >= slog.LevelWarn:
+		return e.l.Warning(1, line)
+	default:
+		return e.l.Info(1, line)
+	}
+}
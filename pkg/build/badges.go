@@ -0,0 +1,97 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+// qrQuiet is the blank margin around a QR code in modules; readers need at least 4.
+const qrQuiet = 4
+
+// Attendee is one badge: the printed name and the text of its QR code.
+type Attendee struct {
+	Name string
+	Code string // E-mail or personal link with an email parameter, as read by "hugo72 checkin scan"
+}
+
+// badgePage is a printable page with one badge per attendee.
+var badgePage = template.Must(template.New("badges").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; font-family: sans-serif; }
+.badge { display: inline-flex; align-items: center; gap: 4mm; box-sizing: border-box;
+  width: 90mm; height: 55mm; padding: 5mm; border: 1px dashed #999; break-inside: avoid; }
+.badge svg { width: 35mm; height: 35mm; flex: none; }
+.badge span { font-size: 16pt; font-weight: bold; overflow-wrap: anywhere; }
+</style>
+</head>
+<body>
+{{range .Badges}}<div class="badge">{{.QR}}<span>{{.Name}}</span></div>
+{{end}}</body>
+</html>
+`))
+
+// writeBadges writes a printable HTML page with a name badge and a QR code for
+// every attendee. The page is written to a temporary file first, so a failed
+// or cancelled run leaves the previous page in place.
+func writeBadges(ctx context.Context, file, title string, attendees []Attendee) error {
+	type badge struct {
+		Name string
+		QR   template.HTML
+	}
+	badges := make([]badge, 0, len(attendees))
+	for _, a := range attendees {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		code, err := qr.Encode(a.Code, qr.M)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.Name, err)
+		}
+		badges = append(badges, badge{Name: a.Name, QR: qrSVG(code)})
+	}
+
+	var page bytes.Buffer
+	if err := badgePage.Execute(&page, map[string]any{"Title": title, "Badges": badges}); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(file); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, page.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// qrSVG draws the code as an SVG with one unit per module, including the quiet zone.
+func qrSVG(code *qr.Code) template.HTML {
+	size := code.Size + 2*qrQuiet
+	var path strings.Builder
+	for y := range code.Size {
+		for x := range code.Size {
+			if code.Black(x, y) {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x+qrQuiet, y+qrQuiet)
+			}
+		}
+	}
+	return template.HTML(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges"><rect width="%d" height="%d" fill="#fff"/><path d="%s"/></svg>`,
+		size, size, size, size, path.String()))
+}
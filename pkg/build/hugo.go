@@ -0,0 +1,245 @@
+// Package build builds the Hugo site (phase 2) and packs the output into
+// a tar.gz artifact that package deploy can deploy as-is.
+package build
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/profile"
+	"hugo72/pkg/config"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultSiteDir is the Hugo project directory used when the config does not set one.
+const defaultSiteDir = "phase2"
+
+// hugoStopTimeout is how long Hugo gets to exit after an interrupt before it is killed.
+const hugoStopTimeout = 10 * time.Second
+
+// Options selects the site to build and where to pack the output.
+type Options struct {
+	SiteDir  string // Hugo project directory; empty = the default "phase2"
+	Artifact string // tar.gz file to pack public/ into after the build; empty = no artifact
+	DryRun   bool   // Only check the inputs, do not run Hugo
+
+	Badges     string     // HTML file to write attendee badges into while Hugo builds; empty = no badges
+	BadgeTitle string     // Title of the badge page, e.g. the event heading
+	Attendees  []Attendee // One badge per attendee
+}
+
+// Result describes a finished build.
+type Result struct {
+	SiteDir  string `json:"siteDir"`
+	Public   string `json:"public,omitempty"`  // Directory with the built site
+	Command  string `json:"command,omitempty"` // Hugo binary that would run (dry run only)
+	Artifact string `json:"artifact"`
+	Badges   string `json:"badges,omitempty"` // Page with attendee badges
+	DryRun   bool   `json:"dryRun,omitempty"`
+}
+
+// Build builds the site with Hugo and optionally packs the output into an
+// artifact. Badges (see Options.Badges) do not depend on the site and are
+// written while Hugo runs; if either fails, the other is cancelled.
+// Cancelling ctx interrupts Hugo and removes a partially written
+// artifact. In a dry run the inputs are only checked and the hugo command
+// that would run is returned.
+func Build(ctx context.Context, opts Options) (*Result, error) {
+	siteDir := opts.SiteDir
+	if siteDir == "" {
+		siteDir = defaultSiteDir
+	}
+	if opts.DryRun {
+		return dryRun(siteDir, opts.Artifact, opts.Badges)
+	}
+
+	logger := logging.Phase("build")
+	g, gctx := errgroup.WithContext(ctx)
+	if opts.Badges != "" {
+		g.Go(func() error {
+			defer profile.Track("build", "badges")()
+			if err := writeBadges(gctx, opts.Badges, opts.BadgeTitle, opts.Attendees); err != nil {
+				if ctx.Err() != nil {
+					return i18n.Errorf("badges cancelled: %w", ctx.Err())
+				}
+				return exitcode.With(exitcode.Build, i18n.Errorf("failed to write badges: %w", err))
+			}
+			logger.Info("Badges written", "file", opts.Badges, "count", len(opts.Attendees))
+			return nil
+		})
+	}
+	g.Go(func() error {
+		return runHugo(gctx, siteDir)
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if opts.Artifact != "" {
+		defer profile.Track("build", "artifact")()
+		if err := createArtifact(ctx, filepath.Join(siteDir, "public"), opts.Artifact); err != nil {
+			if ctx.Err() != nil {
+				return nil, i18n.Errorf("artifact cancelled: %w", ctx.Err())
+			}
+			return nil, exitcode.With(exitcode.Build, i18n.Errorf("failed to create artifact: %w", err))
+		}
+		logger.Info("Artifact written", "file", opts.Artifact)
+	}
+	return &Result{SiteDir: siteDir, Public: filepath.Join(siteDir, "public"), Artifact: opts.Artifact, Badges: opts.Badges}, nil
+}
+
+// runHugo runs Hugo in siteDir. Cancelling ctx interrupts it.
+func runHugo(ctx context.Context, siteDir string) error {
+	logger := logging.Phase("build")
+	logger.Debug("Running hugo", "dir", siteDir)
+	cmd := exec.CommandContext(ctx, "hugo")
+	cmd.Dir = siteDir
+	cmd.Cancel = func() error {
+		// Hugo gets a chance to stop on its own; Windows cannot deliver an interrupt.
+		if runtime.GOOS == "windows" {
+			return cmd.Process.Kill()
+		}
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = hugoStopTimeout
+	stop := profile.Track("build", "hugo")
+	err := cmd.Run()
+	stop()
+	if err != nil {
+		if ctx.Err() != nil {
+			return i18n.Errorf("hugo build cancelled: %w", ctx.Err())
+		}
+		return exitcode.With(exitcode.Build, i18n.Errorf("hugo build failed: %w", err))
+	}
+	logger.Info("Hugo build succeeded", "dir", siteDir)
+	return nil
+}
+
+// SiteDir returns the Hugo project directory from phase2.siteDir, or the default.
+func SiteDir(config *config.Config) string {
+	if config.Phase2 != nil && config.Phase2.SiteDir != "" {
+		return config.Phase2.SiteDir
+	}
+	return defaultSiteDir
+}
+
+// hugoConfigFiles are the site config files Hugo looks for in the site directory.
+var hugoConfigFiles = []string{
+	"hugo.toml", "hugo.yaml", "hugo.yml", "hugo.json",
+	"config.toml", "config.yaml", "config.yml", "config.json", "config",
+}
+
+// Check checks that the site in siteDir could be built: the directory exists,
+// has a Hugo config and the hugo binary is on the PATH (in this order, so an
+// error wrapping exec.ErrNotFound means the site itself is fine). It returns the path
+// of the hugo binary.
+func Check(siteDir string) (string, error) {
+	if info, err := os.Stat(siteDir); err != nil || !info.IsDir() {
+		return "", exitcode.With(exitcode.Config, i18n.Errorf("site directory %s not found", siteDir))
+	}
+	hasConfig := slices.ContainsFunc(hugoConfigFiles, func(name string) bool {
+		_, err := os.Stat(filepath.Join(siteDir, name))
+		return err == nil
+	})
+	if !hasConfig {
+		return "", exitcode.With(exitcode.Build, i18n.Errorf("no Hugo config (hugo.toml, hugo.yaml, ...) in %s", siteDir))
+	}
+	hugo, err := exec.LookPath("hugo")
+	if err != nil {
+		return "", exitcode.With(exitcode.Build, i18n.Errorf("hugo not found: %w", err))
+	}
+	return hugo, nil
+}
+
+// dryRun checks that the build could run and logs the hugo command instead of running it.
+func dryRun(siteDir, artifact, badges string) (*Result, error) {
+	hugo, err := Check(siteDir)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logging.Phase("build")
+	logger.Info("Dry run, would run hugo", "dir", siteDir, "command", hugo)
+	if artifact != "" {
+		logger.Info("Dry run, would pack public/ into artifact", "file", artifact)
+	}
+	if badges != "" {
+		logger.Info("Dry run, would write badges", "file", badges)
+	}
+	return &Result{SiteDir: siteDir, Command: hugo, Artifact: artifact, Badges: badges, DryRun: true}, nil
+}
+
+// createArtifact packs all regular files below srcDir into a tar.gz archive.
+// Entry names are relative to srcDir and use forward slashes, so the archive
+// can be deployed as-is by package deploy (deploy --artifact). The archive is removed
+// again if packing fails or ctx is cancelled.
+func createArtifact(ctx context.Context, srcDir, dst string) (err error) {
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(dst)
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(srcDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,376 @@
+// Package config načítá a ověřuje společný konfigurační soubor všech fází.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"hugo72/internal/i18n"
+)
+
+// Config reprezentuje strukturu konfiguračního souboru.
+// Každá fáze má v souboru vlastní sekci; sekce, která v souboru chybí, je nil.
+//
+// Hodnoty mohou obsahovat proměnné prostředí, např. "ftpPassword": "${FTP_PASSWORD}",
+// nebo být zašifrované klíčem age, např. "ftpPassword": "age:YWdlLWVuY3J5cHRpb24..."
+// (klíč k dešifrování se čte z HUGO72_AGE_KEY nebo HUGO72_AGE_KEY_FILE).
+// Konfiguraci lze zapsat také v YAML (config.yaml) nebo TOML (config.toml)
+// se stejnými názvy klíčů. Struktura konfiguračního souboru (config.json):
+//
+//	{
+//	  "phase1": {
+//	    "inputFile": "ucastnici.xlsx",
+//	    "outputFile": "phase2/data/data.json"
+//	  },
+//	  "phase2": {
+//	    "siteDir": "phase2",
+//	    "badges": "jmenovky.html"
+//	  },
+//	  "phase3": {
+//	    "ftpHost": "ftp.example.com",
+//	    "ftpUser": "uzivatel",
+//	    "ftpPassword": "heslo",
+//	    "remoteDir": "/cesta/na/serveru",
+//	    "files_to_upload": ["soubor1.txt", "soubor2.txt"],
+//	    "targets": {
+//	      "staging": {"ftpHost": "ftp.example.com", "ftpUser": "uzivatel", "ftpPassword": "heslo", "remoteDir": "/staging"},
+//	      "release": {"ftpHost": "ftp.example.com", "ftpUser": "uzivatel", "ftpPassword": "heslo",
+//	                  "remoteDir": "/releases/{{.Date}}_{{.Commit}}"},
+//	      "production": {"ftpHost": "ftp.example.com", "ftpUser": "uzivatel", "ftpPassword": "heslo", "remoteDir": "/www",
+//	                     "tls": "explicit", "caFile": "hosting-ca.pem", "spaceProbe": "AVBL",
+//	                     "quirks": {"pasvHost": "control", "disableEpsv": true, "disableMlsd": true}},
+//	      "mirror": {"protocol": "sftp", "ftpHost": "ssh.example.com", "ftpUser": "uzivatel", "keyFile": "deploy_ed25519",
+//	                 "remoteDir": "/var/www", "hostKey": "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"}
+//	    },
+//	    "webhookUrl": "https://n8n.example.com/webhook/deploy",
+//	    "lockTimeout": "30m",
+//	    "retries": 2,
+//	    "reportFile": "deploy-report.json",
+//	    "historyFile": "deploy-history.jsonl",
+//	    "artifactDir": ".hugo72/artifacts",
+//	    "checksumCache": ".hugo72/checksums.json",
+//	    "maintenancePage": "maintenance.html",
+//	    "maxConnections": 2,
+//	    "maxParallelTargets": 2,
+//	    "commandDelay": "200ms",
+//	    "failurePolicy": "abort-after",
+//	    "maxErrors": 3,
+//	    "minTotalSize": 10240,
+//	    "permissions": [{"pattern": "cgi-bin/**", "mode": "755"}]
+//	  },
+//	  "watch": {
+//	    "schedule": ["*/15 8-21 * * *"]
+//	  },
+//	  "server": {
+//	    "listen": "127.0.0.1:8072",
+//	    "token": "${HUGO72_TOKEN}",
+//	    "staleAfter": "2h",
+//	    "adminPassword": "${HUGO72_ADMIN_PASSWORD}",
+//	    "rsvp": true
+//	  },
+//	  "notify": {
+//	    "on": ["runFailure", "deploySuccess"],
+//	    "channels": [
+//	      {"type": "smtp", "host": "smtp.example.com", "username": "uzivatel", "password": "${SMTP_PASSWORD}",
+//	       "from": "hugo72@example.com", "to": ["poradatel@example.com"]},
+//	      {"type": "slack", "url": "${SLACK_WEBHOOK}", "on": ["runFailure", "newRegistrations"]},
+//	      {"type": "telegram", "botToken": "${TELEGRAM_TOKEN}", "chatId": "-100123456"}
+//	    ],
+//	    "templates": {"newRegistrations": "Přibylo {{.NewRegistrations}} přihlášek, celkem {{.Records}}."}
+//	  },
+//	  "mailer": {
+//	    "host": "smtp.example.com", "username": "uzivatel", "password": "${SMTP_PASSWORD}",
+//	    "from": "Sraz 72 <sraz@example.com>",
+//	    "rsvpUrl": "https://sraz.example.com/rsvp", "tokenSecret": "${HUGO72_TOKEN_SECRET}",
+//	    "batchSize": 20, "batchPause": "1m", "interval": "2s"
+//	  },
+//	  "hooks": {
+//	    "build": {"pre": [{"command": ["npm", "run", "css"], "timeout": "2m"}]},
+//	    "deploy": {"post": [
+//	      {"command": ["./purge-cache.sh"], "onFailure": "continue"},
+//	      {"url": "https://bot.example.com/hugo72", "timeout": "10s", "onFailure": "continue"}
+//	    ]}
+//	  },
+//	  "backup": {
+//	    "dir": ".hugo72/backups",
+//	    "keep": 20,
+//	    "maxAge": "720h"
+//	  },
+//	  "language": "cs",
+//	  "profiles": {
+//	    "dev": {"phase3": {"remoteDir": "/dev"}},
+//	    "prod": {"phase3": {"ftpHost": "ftp.example.com", "tls": "explicit"}}
+//	  }
+//	}
+type Config struct {
+	Phase1 *Phase1 `json:"phase1"` // Převod Excel souboru na JSON
+	Phase2 *Phase2 `json:"phase2"` // Sestavení webu Hugem
+	Phase3 *Phase3 `json:"phase3"` // Nasazení na FTP server
+	Watch  *Watch  `json:"watch"`  // Automatické spouštění příkazem watch
+	Server *Server `json:"server"` // HTTP server příkazu serve
+	Notify *Notify `json:"notify"` // Oznámení o výsledku běhu
+	Mailer *Mailer `json:"mailer"` // Rozesílání e-mailů účastníkům
+	Backup *Backup `json:"backup"` // Zálohy souborů před každým během
+
+	Hooks map[string]PhaseHooks `json:"hooks"` // Háčky spouštěné před fází a po ní podle názvu fáze ("convert", "build", "deploy")
+
+	Language string `json:"language"` // Jazyk hlášení: "cs" nebo "en" (výchozí podle LANG, viz i18n.Detect)
+}
+
+// Phase1 je nastavení převodu Excel souboru na JSON.
+type Phase1 struct {
+	InputFile  string `json:"inputFile"`  // Excel soubor se seznamem účastníků
+	OutputFile string `json:"outputFile"` // JSON soubor, ze kterého čte Hugo
+
+	MessagesFile  string `json:"messagesFile"`  // Nadpis a zpráva upravené v administraci (výchozí ".hugo72/messages.json")
+	ResponsesFile string `json:"responsesFile"` // Odpovědi z formuláře přihlášky (výchozí ".hugo72/responses.jsonl")
+}
+
+// Phase2 je nastavení sestavení webu.
+type Phase2 struct {
+	SiteDir string `json:"siteDir"` // Adresář Hugo webu (výchozí "phase2")
+	Badges  string `json:"badges"`  // HTML stránka se jmenovkami účastníků s QR kódem pro checkin; vytváří se souběžně se sestavením (prázdné = bez jmenovek)
+}
+
+// Watch je nastavení příkazu watch.
+type Watch struct {
+	Schedule []string `json:"schedule"` // Výrazy cronu, podle kterých se běh spouští i bez změny Excel souboru (místní čas)
+}
+
+// Server je nastavení HTTP serveru, jehož požadavky spouští běh (příkaz serve).
+type Server struct {
+	Listen string `json:"listen"` // Adresa, na které server naslouchá (výchozí "127.0.0.1:8072")
+	Token  string `json:"token"`  // Tajný token, kterým se požadavky prokazují v hlavičce Authorization
+
+	StaleAfter string `json:"staleAfter"` // Stáří posledního úspěšného běhu, po kterém /readyz hlásí chybu (např. "2h")
+
+	AdminUser     string `json:"adminUser"`     // Uživatel administrace textů webu (výchozí "admin")
+	AdminPassword string `json:"adminPassword"` // Heslo administrace; bez něj je /admin vypnutá
+
+	RSVP bool `json:"rsvp"` // Zapne veřejný formulář přihlášky na adrese /rsvp
+}
+
+// Notify je nastavení oznámení, která se po běhu pipeline posílají pořadatelům.
+type Notify struct {
+	Channels  []NotifyChannel   `json:"channels"`  // Kanály, kterými se oznámení odesílají
+	On        []string          `json:"on"`        // Události: "runFailure", "deploySuccess", "newRegistrations" (výchozí jen "runFailure")
+	Templates map[string]string `json:"templates"` // Šablony zpráv podle události (text/template), nahrazují výchozí text
+}
+
+// NotifyChannel je jeden kanál oznámení. Které hodnoty jsou povinné, určuje Type.
+type NotifyChannel struct {
+	Type string   `json:"type"` // "smtp", "slack", "telegram", "discord" nebo "webhook"
+	On   []string `json:"on"`   // Události pro tento kanál; bez hodnoty platí notify.on
+
+	URL string `json:"url"` // Adresa webhooku (slack, discord, webhook)
+
+	BotToken string `json:"botToken"` // Token bota (telegram)
+	ChatID   string `json:"chatId"`   // Chat, do kterého bot píše (telegram)
+
+	Host     string   `json:"host"`     // SMTP server
+	Port     int      `json:"port"`     // Port SMTP serveru (výchozí 587, se STARTTLS, pokud ho server nabízí)
+	Username string   `json:"username"` // Uživatel pro přihlášení k SMTP serveru
+	Password string   `json:"password"` // Heslo pro přihlášení k SMTP serveru
+	From     string   `json:"from"`     // Odesílatel e-mailu
+	To       []string `json:"to"`       // Příjemci e-mailu
+}
+
+// Mailer je nastavení rozesílání pozvánek a zpráv účastníkům (příkaz mail).
+type Mailer struct {
+	Host     string `json:"host"`     // SMTP server
+	Port     int    `json:"port"`     // Port SMTP serveru (výchozí 587)
+	Username string `json:"username"` // Uživatel pro přihlášení k SMTP serveru
+	Password string `json:"password"` // Heslo pro přihlášení k SMTP serveru
+	From     string `json:"from"`     // Odesílatel e-mailů
+
+	RSVPURL     string `json:"rsvpUrl"`     // Adresa formuláře přihlášky pro osobní odkazy (např. "https://sraz.example.com/rsvp")
+	TokenSecret string `json:"tokenSecret"` // Tajný klíč, kterým se osobní odkazy podepisují; bez něj účastník ze seznamu odpověď formulářem nezmění
+
+	BatchSize  int    `json:"batchSize"`  // Počet e-mailů v jedné dávce (výchozí 20)
+	BatchPause string `json:"batchPause"` // Přestávka mezi dávkami (výchozí "1m")
+	Interval   string `json:"interval"`   // Nejkratší prodleva mezi dvěma e-maily (výchozí "2s")
+	SentLog    string `json:"sentLog"`    // Záznam odeslaných e-mailů, podle kterého se nic nepošle dvakrát (výchozí ".hugo72/sent.jsonl")
+}
+
+// Backup je nastavení záloh, které se ukládají před každým během (viz balíček backup).
+// Bez sekce backup se zálohy ukládají s výchozími hodnotami.
+type Backup struct {
+	Disabled bool   `json:"disabled"` // Vypne zálohy
+	Dir      string `json:"dir"`      // Adresář se zálohami (výchozí ".hugo72/backups")
+	Keep     int    `json:"keep"`     // Počet ponechaných záloh (výchozí 20, 0 = výchozí)
+	MaxAge   string `json:"maxAge"`   // Zálohy starší než tato doba se smažou, např. "720h" (výchozí bez omezení)
+}
+
+// PhaseHooks jsou háčky jedné fáze.
+type PhaseHooks struct {
+	Pre  []Hook `json:"pre"`  // Spustí se před fází; selhání háčku s onFailure "abort" fázi nespustí
+	Post []Hook `json:"post"` // Spustí se po fázi, i neúspěšné, a dostanou její výsledek
+}
+
+// Hook je jeden háček: externí příkaz, nebo adresa, na kterou se pošle POST.
+// V obou případech dostane JSON s názvem fáze a jejím výsledkem, příkaz na
+// standardní vstup, adresa jako tělo požadavku.
+type Hook struct {
+	Command   []string `json:"command"`   // Program a jeho argumenty (spouští se bez shellu)
+	URL       string   `json:"url"`       // Adresa, na kterou se JSON odešle metodou POST
+	Timeout   string   `json:"timeout"`   // Nejdelší doba běhu háčku (výchozí "30s")
+	OnFailure string   `json:"onFailure"` // Chování při selhání: "abort" (výchozí, příkaz skončí chybou) nebo "continue" (jen varování)
+}
+
+// Phase3 je nastavení nasazení na FTP server.
+type Phase3 struct {
+	Target                               // Výchozí cíl nasazení, použije se bez přepínače --target
+	FilesToUpload      []string          `json:"files_to_upload"`    // Seznam lokálních souborů určených k nahrání na FTP server
+	Targets            map[string]Target `json:"targets"`            // Pojmenované cíle nasazení (např. "staging", "production")
+	WebhookURL         string            `json:"webhookUrl"`         // Volitelná adresa, na kterou se po nasazení odešle JSON s výsledkem
+	LockTimeout        string            `json:"lockTimeout"`        // Stáří, po kterém je cizí zámek na serveru považován za opuštěný (výchozí "30m")
+	Retries            int               `json:"retries"`            // Počet opakování nahrání souboru po chybě
+	ReportFile         string            `json:"reportFile"`         // Cesta ke strojově čitelnému reportu (výchozí "deploy-report.json")
+	HistoryFile        string            `json:"historyFile"`        // Cesta k lokální historii nasazení (výchozí "deploy-history.jsonl")
+	ArtifactDir        string            `json:"artifactDir"`        // Adresář s uloženými artefakty nasazení (výchozí ".hugo72/artifacts")
+	ChecksumCache      string            `json:"checksumCache"`      // Mezipaměť kontrolních součtů (výchozí ".hugo72/checksums.json")
+	MaintenancePage    string            `json:"maintenancePage"`    // Stránka, která během nasazení dočasně nahradí index.html
+	MaxConnections     int               `json:"maxConnections"`     // Nejvyšší počet současných spojení k serveru (výchozí 1)
+	MaxParallelTargets int               `json:"maxParallelTargets"` // Nejvyšší počet cílů, na které se nasazuje současně (výchozí všechny)
+	CommandDelay       string            `json:"commandDelay"`       // Minimální prodleva mezi příkazy posílanými na server (např. "200ms")
+	FailurePolicy      string            `json:"failurePolicy"`      // Chování při chybě souboru: "continue" (výchozí), "abort" nebo "abort-after"
+	MaxErrors          int               `json:"maxErrors"`          // Počet chyb, po kterém se nasazení přeruší (pro "abort-after")
+	MinTotalSize       int64             `json:"minTotalSize"`       // Minimální celková velikost nasazovaných souborů v bajtech
+	Permissions        []PermissionRule  `json:"permissions"`        // Práva nastavovaná nahraným souborům podle vzoru cesty
+	DisableDeployInfo  bool              `json:"disableDeployInfo"`  // Nenahrávat na server deployed.json s údaji o nasazení
+}
+
+// Target popisuje jeden cíl nasazení, tedy FTP server a adresář na něm.
+type Target struct {
+	FtpHost     string `json:"ftpHost"`     // Adresa FTP serveru (např. "ftp.example.com")
+	FtpUser     string `json:"ftpUser"`     // Uživatelské jméno pro připojení k FTP
+	FtpPassword string `json:"ftpPassword"` // Heslo pro připojení k FTP
+	RemoteDir   string `json:"remoteDir"`   // Cílový adresář na FTP serveru, může obsahovat šablonu (např. "/releases/{{.Date}}")
+
+	Protocol string `json:"protocol"` // Protokol: "" nebo "ftp" (FTP/FTPS), "sftp" (SFTP přes SSH, výchozí port 22)
+
+	TLS                string `json:"tls"`                // Šifrování spojení: "" (žádné), "explicit" nebo "implicit"
+	CAFile             string `json:"caFile"`             // PEM soubor s certifikační autoritou, které se má důvěřovat
+	CertFingerprint    string `json:"certFingerprint"`    // SHA-256 otisk očekávaného certifikátu serveru
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"` // Vypne ověřování certifikátu (jen pro nouzové situace)
+
+	KeyFile    string `json:"keyFile"`    // Soukromý klíč SSH pro přihlášení k SFTP (s heslem nebo místo něj)
+	KnownHosts string `json:"knownHosts"` // Soubor known_hosts, podle kterého se ověří klíč SFTP serveru (výchozí ~/.ssh/known_hosts)
+	HostKey    string `json:"hostKey"`    // Očekávaný klíč SFTP serveru: otisk "SHA256:..." nebo řádek "ssh-ed25519 AAAA..."
+
+	SpaceProbe string `json:"spaceProbe"` // Příkaz, kterým server sdělí volné místo v bajtech (např. "AVBL")
+	QuotaBytes int64  `json:"quotaBytes"` // Kvóta účtu v bajtech, pokud server volné místo sdělit neumí
+
+	Quirks Quirks `json:"quirks"` // Obejití nestandardního chování serveru
+}
+
+// Quirks obsahuje obejití nestandardního chování některých FTP serverů,
+// typicky levných hostingů za NATem.
+type Quirks struct {
+	PasvHost    string `json:"pasvHost"`    // Adresa pro datová spojení místo adresy z odpovědi PASV ("control" = adresa serveru)
+	DisableEPSV bool   `json:"disableEpsv"` // Nepoužívat EPSV a rovnou žádat PASV
+	DisableMLSD bool   `json:"disableMlsd"` // Vypisovat adresáře příkazem LIST, pokud server vrací v MLSD nestandardní údaje
+
+	// NoSize je zastaralé a nemá žádný účinek. Nasazení se na velikost
+	// souboru na serveru nikdy neptá (příkaz SIZE neposílá), takže není co
+	// obcházet. Klíč zůstává jen proto, aby starší konfigurace prošly kontrolou.
+	NoSize bool `json:"noSize"`
+}
+
+// PermissionRule přiřazuje souborům odpovídajícím vzoru práva na serveru.
+// Práva se nastavují po nahrání příkazem SITE CHMOD, pokud ho server podporuje.
+type PermissionRule struct {
+	Pattern string `json:"pattern"` // Vzor cesty, např. "cgi-bin/**"
+	Mode    string `json:"mode"`    // Oktalová práva, např. "755"
+}
+
+// Load načte konfigurační soubor a ověří nastavení všech fází.
+// Formát se určí podle přípony: .yaml a .yml pro YAML, .toml pro TOML, jinak JSON.
+// Neprázdný profile vybere profil ze sekce "profiles" (viz applyProfile)
+// a zápisy ${PROMENNA} v hodnotách se nahradí proměnnými prostředí (viz expandEnv).
+// Zašifrované hodnoty i soubory zašifrované nástrojem SOPS se dešifrují (viz decryptSecrets).
+// Pokud soubor chybí, je poškozen nebo neprojde kontrolou, vrací chybu
+// se všemi nalezenými problémy najednou.
+func Load(filePath, profile string) (*Config, error) {
+	values, err := read(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return resolve(filePath, profile, values, nil)
+}
+
+// Check načte konfigurační soubor stejně jako Load, ale navíc ho před dosazením
+// profilu ověří proti Schema, takže odhalí i neznámé klíče a překlepy v profilech.
+// Problémy ze schématu i z kontroly hodnot vrací najednou.
+func Check(filePath, profile string) (*Config, error) {
+	values, err := read(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return resolve(filePath, profile, values, validateSchema(values))
+}
+
+// read načte konfigurační soubor do obecné struktury a případně ho dešifruje nástrojem SOPS.
+func read(filePath string) (map[string]any, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při otevírání souboru konfigurace '%s': %w", filePath, err)
+	}
+	data, err = toJSON(filePath, data)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při dekódování konfigurace '%s': %w", filePath, err)
+	}
+
+	// Profil, proměnné prostředí a tajné hodnoty se dosazují do obecné struktury,
+	// aby fungovaly pro libovolnou hodnotu bez ohledu na její typ v Config.
+	var values map[string]any
+	if err := jsonDecoder(data).Decode(&values); err != nil {
+		return nil, i18n.Errorf("chyba při dekódování konfigurace '%s': %w", filePath, err)
+	}
+	if values, err = decryptSOPS(filePath, values); err != nil {
+		return nil, i18n.Errorf("chyba při dešifrování konfigurace '%s': %w", filePath, err)
+	}
+	return values, nil
+}
+
+// resolve dosadí do načtených hodnot profil, proměnné prostředí a tajné hodnoty
+// a převede je na Config. Chyby schemaErr se hlásí společně s chybami kontroly hodnot.
+func resolve(filePath, profile string, values map[string]any, schemaErr error) (*Config, error) {
+	if err := applyProfile(values, profile); err != nil {
+		return nil, i18n.Errorf("chyba v konfiguraci '%s': %w", filePath, err)
+	}
+	if err := expandEnv(values); err != nil {
+		return nil, i18n.Errorf("chyba v konfiguraci '%s':\n%w", filePath, err)
+	}
+	if err := decryptSecrets(values); err != nil {
+		return nil, i18n.Errorf("chyba při dešifrování konfigurace '%s':\n%w", filePath, err)
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při dekódování konfigurace '%s': %w", filePath, err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		// Hodnotu nesprávného typu popisuje chyba schématu přesněji i s cestou.
+		if schemaErr != nil {
+			err = schemaErr
+		}
+		return nil, i18n.Errorf("chyba při dekódování konfigurace '%s':\n%w", filePath, err)
+	}
+	if err := joinProblems(schemaErr, config.Validate()); err != nil {
+		if profile != "" {
+			return nil, i18n.Errorf("konfigurace '%s' (profil %s) obsahuje chyby:\n%w", filePath, profile, err)
+		}
+		return nil, i18n.Errorf("konfigurace '%s' obsahuje chyby:\n%w", filePath, err)
+	}
+	return &config, nil
+}
+
+// jsonDecoder vrací dekodér, který čísla ponechá beze změny přesnosti.
+func jsonDecoder(data []byte) *json.Decoder {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"hugo72/internal/i18n"
+)
+
+// DefaultEnvFile je soubor s proměnnými prostředí načítaný z pracovního adresáře.
+const DefaultEnvFile = ".env"
+
+// LoadEnvFile nastaví proměnné prostředí ze souboru ve formátu .env:
+//
+//	# komentář
+//	FTP_PASSWORD=tajne
+//	export FTP_USER="uzivatel"
+//	WEBHOOK_URL='https://n8n.example.com/webhook/deploy'
+//
+// Proměnné, které už v prostředí nastavené jsou, se nepřepisují, takže hodnoty
+// z CI nebo z příkazové řádky mají přednost. Chybějící soubor je chyba jen tehdy,
+// když je required.
+func LoadEnvFile(path string, required bool) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return i18n.Errorf("chyba při otevírání souboru '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return i18n.Errorf("%s:%d: očekáván zápis PROMENNA=hodnota", path, line)
+		}
+		value, err := unquote(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+
+		if _, set := os.LookupEnv(key); !set {
+			os.Setenv(key, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return i18n.Errorf("chyba při čtení souboru '%s': %w", path, err)
+	}
+	return nil
+}
+
+// unquote odstraní z hodnoty uvozovky. V dvojitých uvozovkách se rozpoznají
+// sekvence \n, \" a \\, jednoduché uvozovky hodnotu nemění. Hodnota bez uvozovek
+// končí případným komentářem " #".
+func unquote(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	switch quote := value[0]; quote {
+	case '"', '\'':
+		end := strings.LastIndexByte(value, quote)
+		if end == 0 {
+			return "", errors.New(i18n.T("chybí uzavírací uvozovka"))
+		}
+		value = value[1:end]
+		if quote == '"' {
+			value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value)
+		}
+		return value, nil
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"regexp"
+)
+
+// envRe odpovídá zápisu ${PROMENNA} a také zdvojenému $${, které zapisuje samotné ${.
+var envRe = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv nahradí ve všech textových hodnotách konfigurace zápisy ${PROMENNA}
+// hodnotou proměnné prostředí. Nenastavená proměnná je chyba; hlásí se všechny
+// najednou i s cestou k hodnotě, aby se heslo nebo adresa nedosadily prázdné.
+func expandEnv(values map[string]any) error {
+	var p problems
+	walkStrings("", values, func(path, value string) string {
+		return envRe.ReplaceAllStringFunc(value, func(match string) string {
+			if match == "$${" {
+				return "${"
+			}
+			name := match[2 : len(match)-1]
+			env, ok := os.LookupEnv(name)
+			if !ok {
+				p.add(path, "proměnná prostředí %s není nastavena", name)
+			}
+			return env
+		})
+	})
+	return errors.Join(p...)
+}
+
+// joinPath připojí klíč k cestě v konfiguraci.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
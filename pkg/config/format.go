@@ -0,0 +1,48 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"hugo72/internal/i18n"
+)
+
+// defaultPaths jsou konfigurační soubory hledané v pracovním adresáři, pokud
+// cesta není zadána. Použije se první existující.
+var defaultPaths = []string{"config.json", "config.yaml", "config.yml", "config.toml"}
+
+// DefaultPath vrací cestu k výchozímu konfiguračnímu souboru v pracovním adresáři.
+// Pokud žádný z podporovaných souborů neexistuje, vrací "config.json".
+func DefaultPath() string {
+	for _, path := range defaultPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return defaultPaths[0]
+}
+
+// toJSON převede obsah konfigurace ve formátu YAML nebo TOML (podle přípony souboru)
+// na JSON. Struktura konfigurace tak má jediný popis názvů klíčů, a to ve značkách json.
+// Soubory s jinou příponou se považují za JSON a vrací se beze změny.
+func toJSON(filePath string, data []byte) ([]byte, error) {
+	var values map[string]any
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, i18n.Errorf("chyba při čtení YAML: %w", err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return nil, i18n.Errorf("chyba při čtení TOML: %w", err)
+		}
+	default:
+		return data, nil
+	}
+	return json.Marshal(values)
+}
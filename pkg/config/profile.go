@@ -0,0 +1,51 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"hugo72/internal/i18n"
+)
+
+// applyProfile použije na konfiguraci pojmenovaný profil ze sekce "profiles".
+// Profil obsahuje jen hodnoty, kterými se liší od základní konfigurace, např.
+//
+//	"profiles": {
+//	  "dev":  {"phase3": {"remoteDir": "/dev", "retries": 0}},
+//	  "prod": {"phase3": {"ftpHost": "ftp.example.com", "tls": "explicit"}}
+//	}
+//
+// Objekty se slučují do hloubky, ostatní hodnoty (včetně seznamů) profil nahradí.
+// Sekce "profiles" se z konfigurace vždy odstraní, i když se žádný profil nepoužije.
+func applyProfile(values map[string]any, profile string) error {
+	profiles, _ := values["profiles"].(map[string]any)
+	delete(values, "profiles")
+	if profile == "" {
+		return nil
+	}
+
+	overlay, ok := profiles[profile].(map[string]any)
+	if !ok {
+		if len(profiles) == 0 {
+			return i18n.Errorf("profil '%s' není definován, konfigurace nemá sekci profiles", profile)
+		}
+		return i18n.Errorf("profil '%s' není definován (dostupné profily: %s)",
+			profile, strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+	}
+	merge(values, overlay)
+	return nil
+}
+
+// merge sloučí hodnoty overlay do base. Vnořené objekty se slučují rekurzivně.
+func merge(base, overlay map[string]any) {
+	for key, value := range overlay {
+		if sub, ok := value.(map[string]any); ok {
+			if baseSub, ok := base[key].(map[string]any); ok {
+				merge(baseSub, sub)
+				continue
+			}
+		}
+		base[key] = value
+	}
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"hugo72/internal/i18n"
+)
+
+// Schema je JSON Schema konfiguračního souboru. Lze ho vypsat příkazem
+// "hugo72 config schema" a odkázat na něj z konfigurace klíčem "$schema",
+// aby editor nabízel názvy klíčů a upozorňoval na překlepy.
+//
+//go:embed schema.json
+var Schema []byte
+
+// schemaNode je jeden uzel JSON Schema. Kontrola podporuje jen klíčová slova,
+// která schema.json používá.
+type schemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Required             []string               `json:"required"`
+	Items                *schemaNode            `json:"items"`
+	Enum                 []string               `json:"enum"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+	Description          string                 `json:"description"`
+	Defs                 map[string]*schemaNode `json:"$defs"`
+}
+
+// validateSchema ověří hodnoty konfigurace proti Schema. Hlásí neznámé klíče
+// (typicky překlepy), hodnoty nesprávného typu a hodnoty mimo povolený rozsah.
+func validateSchema(values map[string]any) error {
+	var root schemaNode
+	if err := json.Unmarshal(Schema, &root); err != nil {
+		return i18n.Errorf("chyba při čtení schématu konfigurace: %w", err)
+	}
+	var p problems
+	root.check(&p, &root, "", values)
+	return errors.Join(p...)
+}
+
+// check ověří hodnotu na cestě path proti uzlu schématu.
+func (s *schemaNode) check(p *problems, root *schemaNode, path string, value any) {
+	if s.Ref != "" {
+		name, _ := strings.CutPrefix(s.Ref, "#/$defs/")
+		s = root.Defs[name]
+	}
+
+	if s.Enum != nil {
+		if v, ok := value.(string); !ok || !slices.Contains(s.Enum, v) {
+			p.add(path, "neplatná hodnota %s, povoleno je %s", describe(value), quoteList(s.Enum))
+		}
+		return
+	}
+	if s.Type != "" && !hasType(value, s.Type) {
+		p.add(path, "hodnota má být %s, nalezeno %s", typeNames[s.Type], describe(value))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				p.add(joinPath(path, key), "hodnota je povinná")
+			}
+		}
+		var additional *schemaNode
+		if len(s.AdditionalProperties) > 0 && string(s.AdditionalProperties) != "false" {
+			json.Unmarshal(s.AdditionalProperties, &additional)
+		}
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			keyPath := joinPath(path, key)
+			switch {
+			case s.Properties[key] != nil:
+				s.Properties[key].check(p, root, keyPath, v[key])
+			case additional != nil:
+				additional.check(p, root, keyPath, v[key])
+			case string(s.AdditionalProperties) == "false":
+				p.add(keyPath, "neznámý klíč%s", suggest(key, slices.Collect(maps.Keys(s.Properties))))
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.check(p, root, fmt.Sprintf("%s[%d]", path, i), item)
+			}
+		}
+	case string:
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(v) {
+			p.add(path, "hodnota '%s' nemá očekávaný tvar (%s)", v, s.Description)
+		}
+	case json.Number:
+		if f, err := v.Float64(); err == nil && s.Minimum != nil && f < *s.Minimum {
+			p.add(path, "hodnota musí být alespoň %v", *s.Minimum)
+		}
+	}
+}
+
+// joinProblems spojí chyby schématu s chybami kontroly hodnot. Hodnotu, kterou
+// už nahlásilo schéma, kontrola hodnot nehlásí podruhé.
+func joinProblems(schemaErr, err error) error {
+	var all []error
+	reported := map[string]bool{}
+	for _, e := range unwrapJoined(schemaErr) {
+		path, _, _ := strings.Cut(e.Error(), ": ")
+		reported[path] = true
+		all = append(all, e)
+	}
+	for _, e := range unwrapJoined(err) {
+		if path, _, _ := strings.Cut(e.Error(), ": "); !reported[path] {
+			all = append(all, e)
+		}
+	}
+	return errors.Join(all...)
+}
+
+// unwrapJoined vrací jednotlivé chyby spojené funkcí errors.Join.
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// typeNames jsou české názvy typů JSON Schema pro chybová hlášení.
+var typeNames = map[string]string{
+	"object":  "objekt",
+	"array":   "seznam",
+	"string":  "text",
+	"integer": "celé číslo",
+	"number":  "číslo",
+	"boolean": "true nebo false",
+}
+
+// hasType vrací true, pokud hodnota odpovídá typu JSON Schema.
+func hasType(value any, typ string) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		return typ == "object"
+	case []any:
+		return typ == "array"
+	case string:
+		return typ == "string"
+	case bool:
+		return typ == "boolean"
+	case json.Number:
+		if typ == "integer" {
+			_, err := v.Int64()
+			return err == nil
+		}
+		return typ == "number"
+	}
+	return false
+}
+
+// describe popíše nalezenou hodnotu v chybovém hlášení.
+func describe(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		return "objekt"
+	case []any:
+		return "seznam"
+	case string:
+		return fmt.Sprintf("'%s'", v)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
+
+// quoteList vypíše povolené hodnoty v uvozovkách, prázdnou hodnotu vynechá.
+func quoteList(values []string) string {
+	var quoted []string
+	for _, v := range values {
+		if v != "" {
+			quoted = append(quoted, fmt.Sprintf("%q", v))
+		}
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// suggest navrhne známý klíč, od kterého se neznámý klíč liší jen velikostí
+// písmen, podtržítkem nebo pomlčkou, např. "ftphost" nebo "remote_dir".
+func suggest(key string, known []string) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(s))
+	}
+	for _, k := range slices.Sorted(slices.Values(known)) {
+		if normalize(k) == normalize(key) {
+			return fmt.Sprintf(", nemá být \"%s\"?", k)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"filippo.io/age"
+
+	"hugo72/internal/i18n"
+)
+
+// secretPrefix označuje hodnotu zašifrovanou nástrojem age. Za předponou
+// následuje šifrovaný text v kódování base64, např. z příkazu
+//
+//	hugo72 secrets encrypt --recipient age1...
+const secretPrefix = "age:"
+
+// Proměnné prostředí s klíčem pro dešifrování hodnot.
+const (
+	ageKeyEnv     = "HUGO72_AGE_KEY"      // Soukromý klíč AGE-SECRET-KEY-1...
+	ageKeyFileEnv = "HUGO72_AGE_KEY_FILE" // Soubor s jedním nebo více soukromými klíči
+)
+
+// decryptSecrets nahradí zašifrované hodnoty (s předponou "age:") jejich obsahem.
+// Klíč se načte z prostředí, jen pokud konfigurace nějakou zašifrovanou hodnotu obsahuje.
+func decryptSecrets(values map[string]any) error {
+	var identities []age.Identity
+	var keyErr error
+	var p problems
+	walkStrings("", values, func(path, value string) string {
+		encoded, ok := strings.CutPrefix(value, secretPrefix)
+		if !ok {
+			return value
+		}
+		if identities == nil && keyErr == nil {
+			identities, keyErr = loadIdentities()
+			if keyErr != nil {
+				p = append(p, keyErr)
+			}
+		}
+		if keyErr != nil {
+			return ""
+		}
+
+		plain, err := decryptValue(encoded, identities)
+		if err != nil {
+			p.add(path, "hodnotu nelze dešifrovat: %v", err)
+			return ""
+		}
+		return plain
+	})
+	return errors.Join(p...)
+}
+
+// loadIdentities načte soukromé klíče age z proměnných prostředí.
+func loadIdentities() ([]age.Identity, error) {
+	if key := os.Getenv(ageKeyEnv); key != "" {
+		return age.ParseIdentities(strings.NewReader(key))
+	}
+	if path := os.Getenv(ageKeyFileEnv); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, i18n.Errorf("chyba při otevírání klíče '%s': %w", path, err)
+		}
+		defer file.Close()
+		return age.ParseIdentities(file)
+	}
+	return nil, i18n.Errorf("konfigurace obsahuje zašifrované hodnoty, ale není nastaven klíč %s ani %s", ageKeyEnv, ageKeyFileEnv)
+}
+
+// decryptValue dešifruje jednu hodnotu zakódovanou v base64.
+func decryptValue(encoded string, identities []age.Identity) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), identities...)
+	if err != nil {
+		return "", err
+	}
+	plain, err := io.ReadAll(r)
+	return string(plain), err
+}
+
+// EncryptSecret zašifruje hodnotu pro zadané příjemce (veřejné klíče age1...)
+// a vrátí ji ve tvaru, který lze vložit do konfigurace.
+func EncryptSecret(value string, recipients []string) (string, error) {
+	var rs []age.Recipient
+	for _, r := range recipients {
+		recipient, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return "", i18n.Errorf("neplatný příjemce '%s': %w", r, err)
+		}
+		rs = append(rs, recipient)
+	}
+	if len(rs) == 0 {
+		return "", errors.New(i18n.T("chybí příjemce (veřejný klíč age1...)"))
+	}
+
+	var b bytes.Buffer
+	w, err := age.Encrypt(&b, rs...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, value); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.StdEncoding.EncodeToString(b.Bytes()), nil
+}
+
+// GenerateKey vytvoří nový klíč age. Vrací soukromý klíč pro HUGO72_AGE_KEY
+// a veřejný klíč, pro který se hodnoty šifrují.
+func GenerateKey() (secret, public string, err error) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return "", "", err
+	}
+	return identity.String(), identity.Recipient().String(), nil
+}
+
+// decryptSOPS dešifruje soubor zašifrovaný nástrojem SOPS (pozná se podle sekce "sops")
+// voláním programu sops, který použije vlastní nastavení klíčů (např. SOPS_AGE_KEY_FILE).
+// Soubory bez sekce "sops" vrací beze změny.
+func decryptSOPS(filePath string, values map[string]any) (map[string]any, error) {
+	if _, ok := values["sops"]; !ok {
+		return values, nil
+	}
+
+	out, err := exec.Command("sops", "--decrypt", "--output-type", "json", filePath).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, i18n.Errorf("sops soubor nedešifroval: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, i18n.Errorf("soubor je zašifrovaný nástrojem SOPS, ale program sops nelze spustit: %w", err)
+	}
+
+	var decrypted map[string]any
+	decoder := jsonDecoder(out)
+	if err := decoder.Decode(&decrypted); err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// walkStrings projde hodnotu do hloubky a každou textovou hodnotu nahradí výsledkem fn.
+// Cesta k hodnotě má tvar "phase3.targets.staging.ftpPassword".
+func walkStrings(path string, value any, fn func(path, value string) string) any {
+	switch v := value.(type) {
+	case string:
+		return fn(path, v)
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			v[key] = walkStrings(joinPath(path, key), v[key], fn)
+		}
+	case []any:
+		for i := range v {
+			v[i] = walkStrings(fmt.Sprintf("%s[%d]", path, i), v[i], fn)
+		}
+	}
+	return value
+}
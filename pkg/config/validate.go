@@ -0,0 +1,376 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"hugo72/internal/cron"
+	"hugo72/internal/i18n"
+)
+
+// modeRe odpovídá oktalovému zápisu práv o třech nebo čtyřech číslicích.
+var modeRe = regexp.MustCompile(`^[0-7]{3,4}$`)
+
+// hostKeyFingerprintRe odpovídá otisku klíče SSH serveru, jak ho vypisuje ssh-keygen -l.
+var hostKeyFingerprintRe = regexp.MustCompile(`^SHA256:[A-Za-z0-9+/]{43}$`)
+
+// problems sbírá chyby konfigurace. Každá chyba začíná cestou k hodnotě v souboru,
+// např. "phase3.targets.staging.ftpHost".
+type problems []error
+
+// add zaznamená chybu hodnoty na zadané cestě. Text chyby se přeloží (viz i18n.T).
+func (p *problems) add(path, format string, args ...any) {
+	*p = append(*p, fmt.Errorf("%s: %s", path, fmt.Sprintf(i18n.T(format), args...)))
+}
+
+// Validate ověří nastavení všech fází přítomných v konfiguraci.
+// Vrací všechny nalezené problémy najednou.
+func (c *Config) Validate() error {
+	var p problems
+	if c.Phase1 == nil && c.Phase2 == nil && c.Phase3 == nil {
+		p.add("$", "konfigurace neobsahuje žádnou sekci (phase1, phase2, phase3)")
+	}
+	if c.Language != "" && !slices.Contains(i18n.Languages, c.Language) {
+		p.add("language", "neznámý jazyk '%s', povoleno je \"cs\" nebo \"en\"", c.Language)
+	}
+	if c.Phase1 != nil {
+		c.Phase1.validate(&p, "phase1")
+	}
+	if c.Phase2 != nil && c.Phase2.SiteDir != "" && !isDir(c.Phase2.SiteDir) {
+		p.add("phase2.siteDir", "adresář '%s' neexistuje", c.Phase2.SiteDir)
+	}
+	if c.Phase2 != nil && c.Phase2.Badges != "" && c.Phase1 == nil {
+		p.add("phase2.badges", "jmenovky vyžadují sekci phase1, ze které se čtou účastníci")
+	}
+	if c.Phase3 != nil {
+		c.Phase3.validate(&p, "phase3")
+	}
+	if c.Watch != nil {
+		for i, expr := range c.Watch.Schedule {
+			if _, err := cron.Parse(expr); err != nil {
+				p.add(fmt.Sprintf("watch.schedule[%d]", i), "%v", err)
+			}
+		}
+	}
+	if c.Server != nil {
+		c.Server.validate(&p, "server")
+	}
+	if c.Notify != nil {
+		c.Notify.validate(&p, "notify")
+	}
+	if c.Mailer != nil {
+		c.Mailer.validate(&p, "mailer")
+	}
+	if c.Backup != nil {
+		if c.Backup.Keep < 0 {
+			p.add("backup.keep", "hodnota nesmí být záporná")
+		}
+		validateDuration(&p, "backup.maxAge", c.Backup.MaxAge)
+	}
+	for _, phase := range slices.Sorted(maps.Keys(c.Hooks)) {
+		path := "hooks." + phase
+		if !slices.Contains(hookPhases, phase) {
+			p.add(path, "neznámá fáze, povoleno je %s", quoteList(hookPhases))
+			continue
+		}
+		for i, hook := range c.Hooks[phase].Pre {
+			hook.validate(&p, fmt.Sprintf("%s.pre[%d]", path, i))
+		}
+		for i, hook := range c.Hooks[phase].Post {
+			hook.validate(&p, fmt.Sprintf("%s.post[%d]", path, i))
+		}
+	}
+	return errors.Join(p...)
+}
+
+// hookPhases jsou fáze, ke kterým lze připojit háčky.
+var hookPhases = []string{"convert", "build", "deploy"}
+
+// validate ověří nastavení háčku: právě jeden z příkazu a adresy, dobu a chování při selhání.
+func (c *Hook) validate(p *problems, path string) {
+	switch {
+	case len(c.Command) == 0 && c.URL == "":
+		p.add(path, "háček musí mít command nebo url")
+	case len(c.Command) > 0 && c.URL != "":
+		p.add(path, "háček může mít jen jedno z command a url")
+	case len(c.Command) > 0 && c.Command[0] == "":
+		p.add(path+".command", "název programu je prázdný")
+	case c.URL != "":
+		if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			p.add(path+".url", "'%s' není platná adresa http(s)", c.URL)
+		}
+	}
+	validateDuration(p, path+".timeout", c.Timeout)
+	if c.OnFailure != "" && c.OnFailure != "abort" && c.OnFailure != "continue" {
+		p.add(path+".onFailure", "neznámá hodnota '%s', povoleno je \"abort\" nebo \"continue\"", c.OnFailure)
+	}
+}
+
+// validate ověří nastavení rozesílání e-mailů.
+func (c *Mailer) validate(p *problems, path string) {
+	if c.Host == "" {
+		p.add(path+".host", "hodnota je povinná")
+	}
+	if c.From == "" {
+		p.add(path+".from", "hodnota je povinná")
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		p.add(path+".port", "port %d je mimo rozsah 1-65535", c.Port)
+	}
+	if c.RSVPURL != "" {
+		if u, err := url.Parse(c.RSVPURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			p.add(path+".rsvpUrl", "'%s' není platná adresa http(s)", c.RSVPURL)
+		}
+	}
+	if c.TokenSecret != "" && len(c.TokenSecret) < 16 {
+		p.add(path+".tokenSecret", "klíč je příliš krátký, použijte aspoň 16 znaků")
+	}
+	if c.BatchSize < 0 {
+		p.add(path+".batchSize", "hodnota nesmí být záporná")
+	}
+	validateDuration(p, path+".batchPause", c.BatchPause)
+	validateDuration(p, path+".interval", c.Interval)
+}
+
+// notifyEvents jsou události, o kterých lze posílat oznámení.
+var notifyEvents = []string{"runFailure", "deploySuccess", "newRegistrations"}
+
+// validate ověří nastavení oznámení a zkusí přeložit šablony zpráv.
+func (c *Notify) validate(p *problems, path string) {
+	validateEvents(p, path+".on", c.On)
+	for _, event := range slices.Sorted(maps.Keys(c.Templates)) {
+		if !slices.Contains(notifyEvents, event) {
+			p.add(path+".templates."+event, "neznámá událost, povoleno je %s", quoteList(notifyEvents))
+		} else if _, err := template.New(event).Parse(c.Templates[event]); err != nil {
+			p.add(path+".templates."+event, "neplatná šablona: %v", err)
+		}
+	}
+
+	for i, ch := range c.Channels {
+		chPath := fmt.Sprintf("%s.channels[%d]", path, i)
+		validateEvents(p, chPath+".on", ch.On)
+		required := map[string]string{}
+		switch ch.Type {
+		case "smtp":
+			required["host"], required["from"] = ch.Host, ch.From
+			if len(ch.To) == 0 {
+				p.add(chPath+".to", "hodnota je povinná")
+			}
+			if ch.Port < 0 || ch.Port > 65535 {
+				p.add(chPath+".port", "port %d je mimo rozsah 1-65535", ch.Port)
+			}
+		case "slack", "discord", "webhook":
+			if ch.URL == "" {
+				required["url"] = ""
+			} else if u, err := url.Parse(ch.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				p.add(chPath+".url", "'%s' není platná adresa http(s)", ch.URL)
+			}
+		case "telegram":
+			required["botToken"], required["chatId"] = ch.BotToken, ch.ChatID
+		default:
+			p.add(chPath+".type", "neznámý typ '%s', povoleno je \"smtp\", \"slack\", \"telegram\", \"discord\" nebo \"webhook\"", ch.Type)
+		}
+		for _, key := range slices.Sorted(maps.Keys(required)) {
+			if required[key] == "" {
+				p.add(chPath+"."+key, "hodnota je povinná")
+			}
+		}
+	}
+}
+
+// validateEvents ověří, že seznam obsahuje jen známé události oznámení.
+func validateEvents(p *problems, path string, events []string) {
+	for i, event := range events {
+		if !slices.Contains(notifyEvents, event) {
+			p.add(fmt.Sprintf("%s[%d]", path, i), "neznámá událost '%s', povoleno je %s", event, quoteList(notifyEvents))
+		}
+	}
+}
+
+// validate ověří nastavení HTTP serveru.
+func (c *Server) validate(p *problems, path string) {
+	if c.Token == "" {
+		p.add(path+".token", "hodnota je povinná")
+	} else if len(c.Token) < 16 {
+		p.add(path+".token", "token je příliš krátký, použijte aspoň 16 znaků")
+	}
+	if c.Listen != "" {
+		if _, _, err := net.SplitHostPort(c.Listen); err != nil {
+			p.add(path+".listen", "'%s' není adresa ve tvaru \"host:port\"", c.Listen)
+		}
+	}
+	validateDuration(p, path+".staleAfter", c.StaleAfter)
+	if c.AdminPassword != "" && len(c.AdminPassword) < 12 {
+		p.add(path+".adminPassword", "heslo je příliš krátké, použijte aspoň 12 znaků")
+	}
+}
+
+// validate ověří nastavení převodu Excel souboru.
+func (c *Phase1) validate(p *problems, path string) {
+	if c.InputFile == "" {
+		p.add(path+".inputFile", "hodnota je povinná")
+	} else if _, err := os.Stat(c.InputFile); err != nil {
+		p.add(path+".inputFile", "soubor '%s' nelze použít: %v", c.InputFile, err)
+	}
+
+	if c.OutputFile == "" {
+		p.add(path+".outputFile", "hodnota je povinná")
+	} else if dir := filepath.Dir(c.OutputFile); !isDir(dir) {
+		p.add(path+".outputFile", "adresář '%s' neexistuje", dir)
+	}
+}
+
+// validate ověří nastavení nasazení včetně všech cílů.
+func (c *Phase3) validate(p *problems, path string) {
+	// Výchozí cíl je povinný, jen pokud nejsou definované pojmenované cíle.
+	if len(c.Targets) == 0 || c.FtpHost != "" {
+		c.Target.validate(p, path)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Targets)) {
+		target := c.Targets[name]
+		target.validate(p, path+".targets."+name)
+	}
+
+	if c.WebhookURL != "" {
+		if u, err := url.Parse(c.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			p.add(path+".webhookUrl", "'%s' není platná adresa http(s)", c.WebhookURL)
+		}
+	}
+	validateDuration(p, path+".lockTimeout", c.LockTimeout)
+	validateDuration(p, path+".commandDelay", c.CommandDelay)
+
+	for _, field := range []struct {
+		name  string
+		value int64
+	}{
+		{"retries", int64(c.Retries)},
+		{"maxConnections", int64(c.MaxConnections)},
+		{"maxParallelTargets", int64(c.MaxParallelTargets)},
+		{"maxErrors", int64(c.MaxErrors)},
+		{"minTotalSize", c.MinTotalSize},
+	} {
+		if field.value < 0 {
+			p.add(path+"."+field.name, "hodnota nesmí být záporná")
+		}
+	}
+
+	switch c.FailurePolicy {
+	case "", "continue", "abort":
+	case "abort-after":
+		if c.MaxErrors < 1 {
+			p.add(path+".maxErrors", "failurePolicy \"abort-after\" vyžaduje maxErrors alespoň 1")
+		}
+	default:
+		p.add(path+".failurePolicy", "neznámá hodnota '%s', povoleno je \"continue\", \"abort\" nebo \"abort-after\"", c.FailurePolicy)
+	}
+
+	for i, rule := range c.Permissions {
+		rulePath := fmt.Sprintf("%s.permissions[%d]", path, i)
+		if rule.Pattern == "" {
+			p.add(rulePath+".pattern", "hodnota je povinná")
+		}
+		if !modeRe.MatchString(rule.Mode) {
+			p.add(rulePath+".mode", "neplatná práva '%s', očekáván oktalový zápis jako \"755\"", rule.Mode)
+		}
+	}
+}
+
+// validate ověří jeden cíl nasazení.
+func (t *Target) validate(p *problems, path string) {
+	switch {
+	case t.FtpHost == "":
+		p.add(path+".ftpHost", "hodnota je povinná")
+	case strings.Contains(t.FtpHost, "://"):
+		p.add(path+".ftpHost", "'%s' má obsahovat jen adresu serveru a případně port, bez ftp://", t.FtpHost)
+	default:
+		if _, port, err := net.SplitHostPort(t.FtpHost); err == nil && port == "" {
+			p.add(path+".ftpHost", "'%s' nemá za dvojtečkou číslo portu", t.FtpHost)
+		}
+	}
+	if t.FtpUser == "" {
+		p.add(path+".ftpUser", "hodnota je povinná")
+	}
+	if t.RemoteDir == "" {
+		p.add(path+".remoteDir", "hodnota je povinná")
+	}
+
+	switch t.Protocol {
+	case "", "ftp":
+		if t.KeyFile != "" || t.KnownHosts != "" || t.HostKey != "" {
+			p.add(path+".protocol", "keyFile, knownHosts a hostKey platí jen pro protokol \"sftp\"")
+		}
+	case "sftp":
+		t.validateSFTP(p, path)
+	default:
+		p.add(path+".protocol", "neznámý protokol '%s', povoleno je \"ftp\" nebo \"sftp\"", t.Protocol)
+	}
+
+	switch t.TLS {
+	case "", "explicit", "implicit":
+	default:
+		p.add(path+".tls", "neznámý režim '%s', povoleno je \"explicit\" nebo \"implicit\"", t.TLS)
+	}
+	if t.CAFile != "" {
+		if _, err := os.Stat(t.CAFile); err != nil {
+			p.add(path+".caFile", "soubor '%s' nelze použít: %v", t.CAFile, err)
+		}
+	}
+	if t.QuotaBytes < 0 {
+		p.add(path+".quotaBytes", "hodnota nesmí být záporná")
+	}
+}
+
+// validateSFTP ověří nastavení cíle s protokolem sftp. Spojení šifruje SSH,
+// nastavení FTPS ani příkazy FTP serveru se u něj nepoužijí.
+func (t *Target) validateSFTP(p *problems, path string) {
+	if t.TLS != "" || t.CAFile != "" || t.CertFingerprint != "" || t.InsecureSkipVerify {
+		p.add(path+".tls", "u protokolu \"sftp\" spojení šifruje SSH, tls, caFile, certFingerprint ani insecureSkipVerify se nenastavují")
+	}
+	if t.SpaceProbe != "" {
+		p.add(path+".spaceProbe", "příkaz serveru lze poslat jen přes FTP, u protokolu \"sftp\" použijte quotaBytes")
+	}
+	if t.Quirks != (Quirks{}) {
+		p.add(path+".quirks", "obejití chyb FTP serverů se u protokolu \"sftp\" nepoužijí")
+	}
+	if t.FtpPassword == "" && t.KeyFile == "" {
+		p.add(path+".keyFile", "zadejte keyFile nebo ftpPassword")
+	}
+	if t.KeyFile != "" {
+		if _, err := os.Stat(t.KeyFile); err != nil {
+			p.add(path+".keyFile", "soubor '%s' nelze použít: %v", t.KeyFile, err)
+		}
+	}
+	if t.HostKey != "" && !hostKeyFingerprintRe.MatchString(t.HostKey) {
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(t.HostKey)); err != nil {
+			p.add(path+".hostKey", "'%s' není otisk \"SHA256:...\" ani veřejný klíč jako \"ssh-ed25519 AAAA...\"", t.HostKey)
+		}
+	}
+}
+
+// validateDuration ověří dobu trvání ve tvaru "30m" nebo "200ms". Prázdná hodnota je povolena.
+func validateDuration(p *problems, path, value string) {
+	if value == "" {
+		return
+	}
+	if d, err := time.ParseDuration(value); err != nil || d < 0 {
+		p.add(path, "neplatná doba trvání '%s', očekáván zápis jako \"30m\" nebo \"200ms\"", value)
+	}
+}
+
+// isDir vrací true, pokud cesta existuje a je adresář.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
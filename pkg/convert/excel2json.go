@@ -0,0 +1,233 @@
+// Package convert převádí Excel soubor se seznamem přihlášek na JSON pro web
+// (fáze 1). Kromě převodu (Convert) spravuje texty webu upravené v administraci
+// (Messages) a odpovědi z formuláře přihlášky (Response), které převod použije.
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/profile"
+	"hugo72/pkg/config"
+)
+
+type Data72 struct {
+	Info  Info   `json:"info"`
+	Users []User `json:"users"`
+}
+
+type Info struct {
+	LastUpdate   string `json:"lastUpdate"`
+	Nadpis       string `json:"nadpis"`
+	Zprava       string `json:"zprava"`
+	PocetZaznamu int64  `json:"pocetZaznamu"`
+	PocetAno     int64  `json:"pocetAno"`
+}
+
+type User struct {
+	Jmeno  string `json:"Jmeno"`
+	Email  string `json:"email"`
+	Prijde Prijde `json:"Prijde"`
+}
+
+type Prijde string
+
+const (
+	Ano   Prijde = "Ano"
+	Empty Prijde = ""
+	Ne    Prijde = "Ne"
+)
+
+// Options určuje vstupní a výstupní soubory převodu.
+type Options struct {
+	InputFile     string // Excel soubor se seznamem přihlášek
+	OutputFile    string // JSON soubor pro web
+	MessagesFile  string // Nadpis a zpráva upravené v administraci; prázdný = texty z Excel souboru
+	ResponsesFile string // Odpovědi z formuláře přihlášky; prázdný = bez odpovědí
+	DryRun        bool   // Excel soubor jen přečíst, JSON nezapisovat
+}
+
+// Result shrnuje výsledek převodu.
+type Result struct {
+	OutputFile string `json:"outputFile"`
+	Records    int64  `json:"records"`   // Počet přihlášek
+	Attending  int64  `json:"attending"` // Z toho účastníků, kteří přijdou
+	DryRun     bool   `json:"dryRun,omitempty"`
+}
+
+// OptionsFromConfig vrací volby převodu ze sekce phase1 konfigurace.
+func OptionsFromConfig(config *config.Config) (Options, error) {
+	if config.Phase1 == nil {
+		return Options{}, exitcode.With(exitcode.Config, errors.New(i18n.T("konfigurace neobsahuje sekci phase1")))
+	}
+	return Options{
+		InputFile:     config.Phase1.InputFile,
+		OutputFile:    config.Phase1.OutputFile,
+		MessagesFile:  MessagesFile(config),
+		ResponsesFile: ResponsesFile(config),
+	}, nil
+}
+
+// Convert převede Excel soubor opts.InputFile na JSON soubor opts.OutputFile.
+// Nadpis a zprávu upravené v administraci (opts.MessagesFile) použije místo textů
+// z hlavičky Excel souboru a do seznamu doplní odpovědi z formuláře přihlášky
+// (opts.ResponsesFile). Při zkušebním běhu Excel soubor jen přečte a vrátí,
+// co by zapsal.
+func Convert(ctx context.Context, opts Options) (*Result, error) {
+	stop := profile.Track("convert", "excel")
+	rows, err := readSheet(opts.InputFile)
+	stop()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, i18n.Errorf("převod přerušen: %w", err)
+	}
+	stop = profile.Track("convert", "rows")
+	data := processRows(rows)
+	stop()
+	if opts.MessagesFile != "" {
+		stop := profile.Track("convert", "messages")
+		messages, err := ReadMessages(opts.MessagesFile)
+		stop()
+		if err != nil {
+			return nil, exitcode.With(exitcode.Data, err)
+		}
+		messages.apply(&data.Info)
+	}
+	if opts.ResponsesFile != "" {
+		stop := profile.Track("convert", "responses")
+		responses, err := ReadResponses(opts.ResponsesFile)
+		if err != nil {
+			return nil, exitcode.With(exitcode.Data, err)
+		}
+		if len(responses) > 0 {
+			if rejected := mergeResponses(&data, responses); rejected > 0 {
+				logging.Phase("convert").Warn("Neověřené odpovědi z formuláře nemohou změnit účastníka, který už v seznamu je.",
+					"file", opts.ResponsesFile, "count", rejected)
+			}
+		}
+		stop()
+	}
+	result := &Result{OutputFile: opts.OutputFile, Records: data.Info.PocetZaznamu, Attending: data.Info.PocetAno}
+	if opts.DryRun {
+		_, statErr := os.Stat(opts.OutputFile)
+		logging.Phase("convert").Info("Zkušební běh, soubor se nezapíše.", "file", opts.OutputFile,
+			"records", data.Info.PocetZaznamu, "overwrite", statErr == nil)
+		result.DryRun = true
+		return result, nil
+	}
+	stop = profile.Track("convert", "json")
+	err = writeJSONFile(opts.OutputFile, data)
+	stop()
+	if err != nil {
+		return nil, i18n.Errorf("chyba při zápisu JSON souboru: %w", err)
+	}
+
+	logging.Phase("convert").Info("Soubor byl úspěšně vytvořen.", "file", opts.OutputFile,
+		"records", data.Info.PocetZaznamu)
+	return result, nil
+}
+
+// Check ověří, že Excel soubor jde otevřít a přečíst, a vrátí počet přihlášek
+// v něm. Nic nezapisuje.
+func Check(inputFile string) (int64, error) {
+	rows, err := readSheet(inputFile)
+	if err != nil {
+		return 0, err
+	}
+	return processRows(rows).Info.PocetZaznamu, nil
+}
+
+// readSheet načte řádky prvního listu Excel souboru.
+func readSheet(filePath string) ([][]string, error) {
+	excelFile, err := openExcelFile(filePath)
+	if err != nil {
+		return nil, exitcode.With(exitcode.Data, i18n.Errorf("chyba při otevírání Excel souboru: %w", err))
+	}
+	defer excelFile.Close()
+
+	sheetName := excelFile.GetSheetName(0)
+	rows, err := excelFile.GetRows(sheetName)
+	if err != nil {
+		return nil, exitcode.With(exitcode.Data, i18n.Errorf("chyba při čtení řádků ze souboru: %w", err))
+	}
+	logging.Phase("convert").Debug("Načten list Excel souboru.", "file", filePath, "sheet", sheetName, "rows", len(rows))
+	return rows, nil
+}
+
+func openExcelFile(filePath string) (*excelize.File, error) {
+	return excelize.OpenFile(filePath)
+}
+
+func processRows(rows [][]string) Data72 {
+	var data Data72
+
+	if len(rows) > 1 {
+		data.Info.Nadpis = rows[0][1]
+		data.Info.Zprava = rows[1][1]
+	}
+	totalRecords := 0
+	totalAno := 0
+
+	for i, row := range rows {
+		if i < 3 { // Přeskočení hlavičky
+			continue
+		}
+		if len(row) < 6 || row[1] == "" { // Konec dat (prázdný řádek)
+			break
+		}
+
+		user := User{
+			Jmeno:  row[1],
+			Email:  row[5],
+			Prijde: Prijde(row[0]),
+		}
+		if user.Prijde == Ano {
+			totalAno++
+		}
+		data.Users = append(data.Users, user)
+		totalRecords++
+	}
+
+	data.Info.LastUpdate = time.Now().Format("2.1.2006 15.04.05")
+	data.Info.PocetZaznamu = int64(totalRecords)
+	data.Info.PocetAno = int64(totalAno)
+
+	return data
+}
+
+// writeJSONFile zapíše data nejdřív do dočasného souboru a ten pak přejmenuje,
+// takže přerušený zápis nezanechá v adresáři webu neúplný JSON.
+func writeJSONFile(filePath string, data Data72) error {
+	jsonFile, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(jsonFile.Name())
+	if err := jsonFile.Chmod(0o644); err != nil {
+		jsonFile.Close()
+		return err
+	}
+
+	encoder := json.NewEncoder(jsonFile)
+	encoder.SetIndent("", "  ") // Pro lepší čitelnost JSON souboru
+	if err := encoder.Encode(data); err != nil {
+		jsonFile.Close()
+		return err
+	}
+	if err := jsonFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(jsonFile.Name(), filePath)
+}
@@ -0,0 +1,80 @@
+package convert
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/pkg/config"
+)
+
+// defaultMessagesFile je výchozí cesta k textům upraveným v administraci.
+const defaultMessagesFile = ".hugo72/messages.json"
+
+// Messages jsou texty webu upravené v administraci (příkaz serve). Neprázdná
+// hodnota při převodu nahradí text z hlavičky Excel souboru.
+type Messages struct {
+	Nadpis    string    `json:"nadpis"`
+	Zprava    string    `json:"zprava"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	UpdatedBy string    `json:"updatedBy"`
+}
+
+// MessagesFile vrací cestu k textům z administrace podle phase1.messagesFile, nebo výchozí.
+func MessagesFile(config *config.Config) string {
+	if config.Phase1 != nil && config.Phase1.MessagesFile != "" {
+		return config.Phase1.MessagesFile
+	}
+	return defaultMessagesFile
+}
+
+// ReadMessages načte texty z administrace. Chybějící soubor znamená, že se
+// používají texty z Excel souboru.
+func ReadMessages(filePath string) (Messages, error) {
+	var messages Messages
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return messages, nil
+	}
+	if err != nil {
+		return messages, i18n.Errorf("chyba při čtení textů webu '%s': %w", filePath, err)
+	}
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return messages, i18n.Errorf("chyba v souboru s texty webu '%s': %w", filePath, err)
+	}
+	return messages, nil
+}
+
+// WriteMessages uloží texty z administrace. Soubor se zapíše nejdřív pod
+// dočasným názvem a pak přejmenuje, aby ho převod nenačetl rozepsaný.
+func WriteMessages(filePath string, messages Messages) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře '%s': %w", filepath.Dir(filePath), err)
+	}
+	data, err := json.MarshalIndent(messages, "", "  ")
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci textů webu: %w", err)
+	}
+	tmp := filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return i18n.Errorf("chyba při zápisu textů webu '%s': %w", filePath, err)
+	}
+	if err := os.Rename(tmp, filePath); err != nil {
+		os.Remove(tmp)
+		return i18n.Errorf("chyba při zápisu textů webu '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// apply nahradí texty z Excel souboru neprázdnými texty z administrace.
+func (m Messages) apply(info *Info) {
+	if m.Nadpis != "" {
+		info.Nadpis = m.Nadpis
+	}
+	if m.Zprava != "" {
+		info.Zprava = m.Zprava
+	}
+}
@@ -0,0 +1,120 @@
+package convert
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/pkg/config"
+)
+
+// defaultResponsesFile je výchozí cesta k odpovědím z formuláře přihlášky.
+const defaultResponsesFile = ".hugo72/responses.jsonl"
+
+// Response je jedna odpověď odeslaná formulářem přihlášky (příkaz serve).
+// Odpovědi se ukládají jako JSON Lines a jen se připisují. Jen ověřená
+// odpověď smí změnit účastníka, který už v seznamu je (viz mergeResponses).
+type Response struct {
+	Jmeno       string    `json:"jmeno"`
+	Email       string    `json:"email"`
+	Prijde      Prijde    `json:"prijde"`
+	SubmittedAt time.Time `json:"submittedAt"`
+	Verified    bool      `json:"verified,omitempty"` // Odesláno z podepsaného odkazu v pozvánce (příkaz mail)
+}
+
+// ResponsesFile vrací cestu k odpovědím z formuláře podle phase1.responsesFile, nebo výchozí.
+func ResponsesFile(config *config.Config) string {
+	if config.Phase1 != nil && config.Phase1.ResponsesFile != "" {
+		return config.Phase1.ResponsesFile
+	}
+	return defaultResponsesFile
+}
+
+// AppendResponse připíše odpověď na konec souboru s odpověďmi.
+func AppendResponse(filePath string, response Response) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře '%s': %w", filepath.Dir(filePath), err)
+	}
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return i18n.Errorf("chyba při otevírání odpovědí '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(response); err != nil {
+		return i18n.Errorf("chyba při zápisu odpovědi '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// ReadResponses načte všechny odpovědi v pořadí, v jakém přišly.
+// Chybějící soubor znamená, že formulářem zatím nikdo neodpověděl.
+func ReadResponses(filePath string) ([]Response, error) {
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba při otevírání odpovědí '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	var responses []Response
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var response Response
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			return nil, i18n.Errorf("chyba v odpovědích '%s' na řádku %d: %w", filePath, line, err)
+		}
+		responses = append(responses, response)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.Errorf("chyba při čtení odpovědí '%s': %w", filePath, err)
+	}
+	return responses, nil
+}
+
+// mergeResponses doplní odpovědi z formuláře do seznamu z Excel souboru.
+// Účastník se pozná podle e-mailu (bez ohledu na velikost písmen). Formulář
+// je veřejný a e-maily účastníků jsou na webu vidět, proto existující záznam
+// (z Excel souboru i z dřívější odpovědi) změní jen ověřená odpověď z odkazu
+// v pozvánce; novější ověřená odpověď přepíše starší. Neověřená odpověď jen
+// přidá na konec seznamu toho, kdo v něm ještě není. Vrací počet neověřených
+// odpovědí, které se kvůli tomu nepoužily. Počty v Info se přepočítají.
+func mergeResponses(data *Data72, responses []Response) (rejected int) {
+	byEmail := map[string]int{}
+	for i, user := range data.Users {
+		if user.Email != "" {
+			byEmail[strings.ToLower(user.Email)] = i
+		}
+	}
+	for _, response := range responses {
+		key := strings.ToLower(response.Email)
+		if i, ok := byEmail[key]; ok {
+			if !response.Verified {
+				rejected++
+				continue
+			}
+			data.Users[i].Prijde = response.Prijde
+			continue
+		}
+		byEmail[key] = len(data.Users)
+		data.Users = append(data.Users, User{Jmeno: response.Jmeno, Email: response.Email, Prijde: response.Prijde})
+	}
+
+	data.Info.PocetZaznamu, data.Info.PocetAno = int64(len(data.Users)), 0
+	for _, user := range data.Users {
+		if user.Prijde == Ano {
+			data.Info.PocetAno++
+		}
+	}
+	return rejected
+}
@@ -0,0 +1,236 @@
+package deploy
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+)
+
+// defaultArtifactDir je výchozí adresář, do kterého se ukládají nasazené artefakty.
+const defaultArtifactDir = ".hugo72/artifacts"
+
+// artifactManifestFile je název souboru s manifestem uvnitř uloženého artefaktu.
+const artifactManifestFile = "manifest.json"
+
+// artifactDir vrací adresář s artefakty z konfigurace nebo výchozí adresář.
+func artifactDir(config *Config) string {
+	if config.Phase3.ArtifactDir != "" {
+		return config.Phase3.ArtifactDir
+	}
+	return defaultArtifactDir
+}
+
+// storeArtifact uloží kopii nasazovaných souborů spolu s manifestem do adresáře
+// pojmenovaného podle hashe manifestu. Stejný obsah se ukládá jen jednou.
+//
+// Artefakt se nejprve připraví v dočasném adresáři a teprve poté se přejmenuje,
+// aby v úložišti nikdy nezůstal neúplný artefakt.
+func storeArtifact(baseDir string, files []deployFile, m manifest) error {
+	dir := filepath.Join(baseDir, m.hash())
+	if _, err := os.Stat(dir); err == nil {
+		return nil // Artefakt se stejným obsahem již existuje
+	}
+
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře artefaktů '%s': %w", baseDir, err)
+	}
+	tmp, err := os.MkdirTemp(baseDir, "tmp-")
+	if err != nil {
+		return i18n.Errorf("chyba při vytváření dočasného adresáře: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, f := range files {
+		dst, err := artifactPath(tmp, f.Remote)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(f.Local, dst); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci manifestu: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, artifactManifestFile), data, 0o644); err != nil {
+		return i18n.Errorf("chyba při zápisu manifestu: %w", err)
+	}
+
+	if err := os.Rename(tmp, dir); err != nil {
+		return i18n.Errorf("chyba při ukládání artefaktu '%s': %w", dir, err)
+	}
+	return nil
+}
+
+// loadArtifact načte uložený artefakt podle hashe manifestu a ověří,
+// že soubory od uložení nikdo nezměnil. Vrací seznam souborů připravených k nahrání.
+func loadArtifact(ctx context.Context, baseDir, hash string) ([]deployFile, error) {
+	dir := filepath.Join(baseDir, hash)
+	data, err := os.ReadFile(filepath.Join(dir, artifactManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, i18n.Errorf("artefakt '%s' nebyl v '%s' nalezen", short(hash), baseDir)
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba při čtení manifestu artefaktu: %w", err)
+	}
+
+	var stored manifest
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, i18n.Errorf("chyba při dekódování manifestu artefaktu: %w", err)
+	}
+
+	files := make([]deployFile, 0, len(stored))
+	for _, e := range stored {
+		local, err := artifactPath(dir, e.Path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, deployFile{Local: local, Remote: e.Path})
+	}
+
+	// Kontrola, že obsah artefaktu stále odpovídá hashi z historie nasazení.
+	// Mezipaměť se záměrně nepoužije, obsah se vždy přepočítá.
+	actual, err := buildManifest(ctx, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	if actual.hash() != hash {
+		return nil, i18n.Errorf("artefakt '%s' byl po uložení změněn", short(hash))
+	}
+	return files, nil
+}
+
+// artifactPath vrací lokální cestu souboru uvnitř artefaktu.
+// Odmítne cesty, které by vedly mimo adresář artefaktu.
+func artifactPath(dir, remote string) (string, error) {
+	rel := filepath.FromSlash(remote)
+	if !filepath.IsLocal(rel) {
+		return "", i18n.Errorf("cestu '%s' nelze uložit do artefaktu", remote)
+	}
+	return filepath.Join(dir, rel), nil
+}
+
+// copyFile zkopíruje soubor včetně vytvoření chybějících adresářů.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return i18n.Errorf("chyba při otevření souboru '%s': %w", src, err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře pro '%s': %w", dst, err)
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return i18n.Errorf("chyba při vytváření souboru '%s': %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return i18n.Errorf("chyba při kopírování souboru '%s': %w", src, err)
+	}
+	return out.Close()
+}
+
+// runPromote znovu nasadí poslední úspěšně nasazený artefakt cíle from na cíl to.
+// Nic se znovu nesestavuje; nahrává se přesně ten obsah, který byl na cíli from ověřen.
+func runPromote(ctx context.Context, config *Config, from, to string, opts deployOptions) (*Result, error) {
+	entries, err := readHistory(historyFile(config))
+	if err != nil {
+		return nil, err
+	}
+
+	staged := lastSuccessful(entries, from)
+	if staged == nil {
+		return nil, exitcode.With(exitcode.NothingToDo, i18n.Errorf("cíl '%s' nemá žádné úspěšné nasazení, není co povýšit", from))
+	}
+
+	files, err := loadArtifact(ctx, artifactDir(config), staged.ManifestHash)
+	if err != nil {
+		return nil, exitcode.With(exitcode.Data, err)
+	}
+
+	logging.Phase("deploy").Info("Povyšuji artefakt na další cíl.", "artifact", short(staged.ManifestHash),
+		"commit", short(staged.Commit), "from", from, "to", to)
+	return runDeploy(ctx, config, []string{to}, files, opts)
+}
+
+// extractArchive rozbalí sestavený artefakt ve formátu tar.gz (viz build --artifact)
+// do dočasného adresáře a vrátí seznam souborů k nahrání. Vzdálené cesty odpovídají
+// cestám v archivu. Dočasný adresář je po nasazení potřeba smazat.
+func extractArchive(archive string) (string, []deployFile, error) {
+	in, err := os.Open(archive)
+	if err != nil {
+		return "", nil, i18n.Errorf("chyba při otevření artefaktu '%s': %w", archive, err)
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return "", nil, i18n.Errorf("artefakt '%s' není platný gzip: %w", archive, err)
+	}
+	defer gz.Close()
+
+	dir, err := os.MkdirTemp("", "hugo72-artifact-")
+	if err != nil {
+		return "", nil, i18n.Errorf("chyba při vytváření dočasného adresáře: %w", err)
+	}
+
+	var files []deployFile
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", nil, i18n.Errorf("chyba při čtení artefaktu '%s': %w", archive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue // Adresáře vznikají podle cest souborů, jiné typy se nenasazují
+		}
+
+		remote := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		local, err := artifactPath(dir, remote)
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", nil, err
+		}
+		if err := writeEntry(tr, local); err != nil {
+			os.RemoveAll(dir)
+			return "", nil, err
+		}
+		files = append(files, deployFile{Local: local, Remote: remote})
+	}
+	return dir, files, nil
+}
+
+// writeEntry zapíše obsah položky archivu do souboru.
+func writeEntry(r io.Reader, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře pro '%s': %w", dst, err)
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return i18n.Errorf("chyba při vytváření souboru '%s': %w", dst, err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return i18n.Errorf("chyba při rozbalování souboru '%s': %w", dst, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,21 @@
+package deploy
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader přestane číst, jakmile je kontext zrušen.
+// Umožňuje přerušit probíhající přenos souboru na server.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read čte z podkladového readeru, dokud není kontext zrušen.
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
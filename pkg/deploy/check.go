@@ -0,0 +1,98 @@
+package deploy
+
+import (
+	"cmp"
+	"context"
+	"net"
+	"path/filepath"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+)
+
+// TargetCheck je výsledek kontroly cíle nasazení (CheckTarget). Kontrola
+// postupuje po krocích a první neúspěšný krok určuje, co je potřeba opravit.
+type TargetCheck struct {
+	Target    string `json:"target"`
+	Host      string `json:"host"`
+	Protocol  string `json:"protocol"` // ftp nebo sftp
+	RemoteDir string `json:"remoteDir"`
+	Reachable bool   `json:"reachable"`         // Server přijal spojení na adrese ftpHost
+	LoggedIn  bool   `json:"loggedIn"`          // Navázání šifrovaného spojení (u SFTP včetně ověření klíče serveru) a přihlášení proběhlo
+	DirExists bool   `json:"dirExists"`         // Vzdálený adresář existuje a jde vypsat
+	Lock      string `json:"lock,omitempty"`    // Vlastník zámku nasazení, pokud je adresář zamčený
+	Entries   int    `json:"entries,omitempty"` // Počet položek ve vzdáleném adresáři
+}
+
+// CheckTarget ověří, že se na cíl nasazení name dá nasadit: server je dosažitelný,
+// přihlášení projde a vzdálený adresář jde vypsat. Na server nic nezapisuje.
+// Chybějící vzdálený adresář chybou není, nasazení ho vytvoří. Spojení otevírá
+// funkcí dial, bez ní přes FTP nebo SFTP podle protocol cíle; dosažitelnost
+// adresy se ověřuje jen bez funkce dial. Klíč SFTP serveru, který ještě není
+// v known_hosts, kontrola neuloží.
+func CheckTarget(ctx context.Context, config *Config, name string, dial Dialer) (*TargetCheck, error) {
+	if config.Phase3 == nil {
+		return nil, errNoSection()
+	}
+	target, err := lookupTarget(config, name)
+	if err != nil {
+		return nil, err
+	}
+	remoteDir, err := expandRemoteDir(target.RemoteDir, gitCommit(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	check := &TargetCheck{Target: name, Host: target.FtpHost, Protocol: cmp.Or(target.Protocol, "ftp"), RemoteDir: remoteDir}
+	logger := logging.Phase("deploy").With("target", name)
+
+	if dial == nil {
+		address := target.FtpHost
+		if target.Protocol == protocolSFTP {
+			address, dial = sftpAddress(target.FtpHost), sftpDialer(false, logger)
+		}
+		d := net.Dialer{Timeout: 5 * time.Second}
+		tcp, err := d.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return check, i18n.Errorf("server %s není dosažitelný: %w", target.FtpHost, err)
+		}
+		tcp.Close()
+	}
+	check.Reachable = true
+
+	conn, err := connect(ctx, target, nil, logger, dial)
+	if err != nil {
+		return check, err
+	}
+	defer conn.Quit()
+	check.LoggedIn = true
+
+	entries, err := conn.List(remoteDir)
+	if err != nil {
+		return check, nil
+	}
+	check.DirExists, check.Entries = true, len(entries)
+
+	lock, err := readLock(conn, remoteDir)
+	if err != nil {
+		return check, err
+	}
+	if lock != nil {
+		check.Lock = lock.Owner
+	}
+	return check, nil
+}
+
+// LocalDirs vrací lokální adresáře, do kterých nasazení zapisuje: adresář
+// reportu, historie nasazení, mezipaměti kontrolních součtů a artefaktů.
+func LocalDirs(config *Config) []string {
+	if config.Phase3 == nil {
+		return nil
+	}
+	return []string{
+		filepath.Dir(cmp.Or(config.Phase3.ReportFile, defaultReportFile)),
+		filepath.Dir(historyFile(config)),
+		filepath.Dir(cmp.Or(config.Phase3.ChecksumCache, defaultChecksumCache)),
+		artifactDir(config),
+	}
+}
@@ -0,0 +1,109 @@
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"hugo72/internal/i18n"
+)
+
+// defaultChecksumCache je výchozí cesta k mezipaměti kontrolních součtů.
+const defaultChecksumCache = ".hugo72/checksums.json"
+
+// checksumEntry je uložený kontrolní součet souboru spolu s údaji,
+// podle kterých se pozná, že se soubor od výpočtu nezměnil.
+type checksumEntry struct {
+	Size    int64  `json:"size"`    // Velikost souboru v bajtech
+	ModTime int64  `json:"modTime"` // Čas poslední změny v nanosekundách od epochy
+	SHA256  string `json:"sha256"`  // Kontrolní součet obsahu
+}
+
+// checksumCache uchovává kontrolní součty souborů mezi nasazeními.
+// Soubor se znovu počítá jen tehdy, když se změnila jeho velikost nebo čas změny.
+type checksumCache struct {
+	mu      sync.Mutex
+	path    string                   // Soubor, do kterého se mezipaměť ukládá
+	entries map[string]checksumEntry // Záznamy podle absolutní cesty souboru
+	dirty   bool                     // Mezipaměť obsahuje neuložené změny
+}
+
+// loadChecksumCache načte mezipaměť ze souboru. Chybějící nebo poškozený soubor
+// znamená prázdnou mezipaměť, protože ji lze kdykoli znovu spočítat.
+func loadChecksumCache(filePath string) *checksumCache {
+	cache := &checksumCache{path: filePath, entries: map[string]checksumEntry{}}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		cache.entries = map[string]checksumEntry{}
+	}
+	return cache
+}
+
+// hash vrací SHA-256 a velikost souboru. Použije uložený součet, pokud soubor
+// od jeho výpočtu nezměnil velikost ani čas změny, jinak soubor přepočítá.
+func (c *checksumCache) hash(filePath string) (string, int64, error) {
+	if c == nil {
+		return hashFile(filePath)
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", 0, i18n.Errorf("chyba při čtení souboru '%s': %w", filePath, err)
+	}
+	key, err := filepath.Abs(filePath)
+	if err != nil {
+		key = filePath
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano() {
+		return entry.SHA256, entry.Size, nil
+	}
+
+	sum, size, err := hashFile(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = checksumEntry{Size: size, ModTime: info.ModTime().UnixNano(), SHA256: sum}
+	c.dirty = true
+	c.mu.Unlock()
+	return sum, size, nil
+}
+
+// save uloží mezipaměť, pokud se od načtení změnila.
+// Záznamy souborů, které už neexistují, se při uložení vynechají.
+func (c *checksumCache) save() error {
+	if c == nil || !c.dirty {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if _, err := os.Stat(key); errors.Is(err, os.ErrNotExist) {
+			delete(c.entries, key)
+		}
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci mezipaměti kontrolních součtů: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return i18n.Errorf("chyba při vytváření adresáře pro '%s': %w", c.path, err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return i18n.Errorf("chyba při ukládání mezipaměti kontrolních součtů '%s': %w", c.path, err)
+	}
+	c.dirty = false
+	return nil
+}
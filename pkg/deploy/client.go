@@ -0,0 +1,147 @@
+package deploy
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// throttle zajišťuje minimální prodlevu mezi příkazy posílanými na server.
+// Sdílí ho všechna spojení k jednomu cíli, takže prodleva platí pro server jako celek.
+type throttle struct {
+	mu    sync.Mutex
+	delay time.Duration // Minimální odstup dvou příkazů
+	last  time.Time     // Okamžik odeslání posledního příkazu
+}
+
+// wait počká, dokud od posledního příkazu neuplyne nastavená prodleva.
+func (t *throttle) wait() {
+	if t == nil || t.delay <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if d := t.delay - time.Since(t.last); d > 0 {
+		time.Sleep(d)
+	}
+	t.last = time.Now()
+}
+
+// client obaluje spojení s cílem nasazení (Transport) a před každým příkazem
+// dodrží prodlevu podle sdíleného throttle. Nese také log cíle a kontext
+// nasazení, které spojení samo nezná.
+type client struct {
+	conn     Transport
+	throttle *throttle
+	logger   *slog.Logger    // Log cíle, ke kterému spojení patří
+	ctx      context.Context // Po zrušení kontextu se přeruší probíhající nahrávání souboru
+}
+
+// Stor nahraje obsah readeru do souboru na serveru.
+func (c *client) Stor(remote string, r io.Reader) error {
+	c.throttle.wait()
+	return c.conn.Stor(remote, r)
+}
+
+// Retr otevře soubor na serveru ke čtení.
+func (c *client) Retr(remote string) (io.ReadCloser, error) {
+	c.throttle.wait()
+	return c.conn.Retr(remote)
+}
+
+// Delete smaže soubor na serveru.
+func (c *client) Delete(remote string) error {
+	c.throttle.wait()
+	return c.conn.Delete(remote)
+}
+
+// List vypíše obsah adresáře na serveru.
+func (c *client) List(dir string) ([]Entry, error) {
+	c.throttle.wait()
+	return c.conn.List(dir)
+}
+
+// MakeDir vytvoří adresář na serveru.
+func (c *client) MakeDir(dir string) error {
+	c.throttle.wait()
+	return c.conn.MakeDir(dir)
+}
+
+// Quit ukončí spojení se serverem.
+func (c *client) Quit() error {
+	return c.conn.Quit()
+}
+
+// ensureDirs vytvoří na serveru podadresáře, do kterých se budou soubory nahrávat.
+// Knihovna neumí rozlišit již existující adresář od jiné chyby, proto se chyby
+// vytváření ignorují; skutečný problém se projeví až při nahrání souboru.
+func ensureDirs(conn *client, remoteDir string, files []deployFile) {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, f := range files {
+		for dir := path.Dir(path.Clean(f.Remote)); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	// Řazení zajistí, že nadřazený adresář vznikne dříve než podadresář.
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		conn.MakeDir(path.Join(remoteDir, dir))
+	}
+}
+
+// makeDirAll vytvoří na serveru adresář včetně nadřazených adresářů, pokud ještě neexistují.
+// Chyby se ignorují ze stejného důvodu jako v ensureDirs.
+func makeDirAll(conn *client, dir string) {
+	var dirs []string
+	for d := path.Clean(dir); d != "." && d != "/"; d = path.Dir(d) {
+		dirs = append(dirs, d)
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		conn.MakeDir(dirs[i])
+	}
+}
+
+// uploadAll nahraje soubory po skupinách určených funkcí uploadRank.
+// Soubory jedné skupiny se rozdělí mezi všechna spojení; další skupina začne
+// až po dokončení předchozí, takže pořadí skupin zůstává zachováno.
+//
+// Jakmile počet chyb překročí hranici politiky, další soubory se už nenahrávají
+// a v reportu jsou vedeny jako přeskočené.
+func uploadAll(conns []*client, config *Config, target Target, files []deployFile, policy failurePolicy, result *deployResult) {
+	for _, group := range groupByRank(files) {
+		jobs := make(chan deployFile)
+		var wg sync.WaitGroup
+		for _, conn := range conns {
+			wg.Add(1)
+			go func(conn *client) {
+				defer wg.Done()
+				for file := range jobs {
+					if result.abort(policy) {
+						skipFile(file, result)
+						continue
+					}
+					uploadInto(conn, config, target, file, result)
+				}
+			}(conn)
+		}
+		for _, file := range group {
+			jobs <- file
+		}
+		close(jobs)
+		wg.Wait()
+	}
+}
+
+// skipFile zaznamená soubor, který se kvůli přerušení nasazení nenahrál.
+func skipFile(file deployFile, result *deployResult) {
+	result.add(fileResult{Path: file.Remote, Status: statusSkipped, Error: "nasazení bylo přerušeno"})
+}
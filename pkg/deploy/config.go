@@ -0,0 +1,11 @@
+package deploy
+
+import "hugo72/pkg/config"
+
+// Typy konfigurace jsou společné pro všechny fáze, viz balíček config.
+type (
+	Config         = config.Config
+	Target         = config.Target
+	Quirks         = config.Quirks
+	PermissionRule = config.PermissionRule
+)
@@ -0,0 +1,119 @@
+// Package deploy nasazuje sestavený web na FTP/FTPS a SFTP servery (fáze 3).
+//
+// Deploy nahraje soubory ze sekce phase3 konfigurace nebo ze sestaveného
+// artefaktu na jeden či více cílů, Promote znovu nasadí artefakt ověřený
+// na jiném cíli a History vrací záznamy lokální historie nasazení.
+// Průběh se zapisuje do logu balíčku logging, výsledek vrací funkce jako
+// Result a zároveň ho ukládá do reportu a historie nasazení.
+package deploy
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+)
+
+// errDeployFailed vrací funkce, jejichž nasazení skončilo s chybou.
+// Podrobnosti už jsou v té chvíli vypsané v logu a v reportu. Chyba se
+// vytváří až při použití, aby byla v jazyce zvoleném za běhu.
+func errDeployFailed() error {
+	return exitcode.With(exitcode.Deploy, errors.New(i18n.T("nasazení se nezdařilo")))
+}
+
+// errNoSection vrací funkce volané s konfigurací bez sekce phase3.
+func errNoSection() error {
+	return exitcode.With(exitcode.Config, errors.New(i18n.T("konfigurace neobsahuje sekci phase3")))
+}
+
+// Options určuje, co a kam Deploy nasadí.
+type Options struct {
+	Targets  []string // Názvy cílů z konfigurace; prázdné = výchozí cíl "default"
+	All      bool     // Nasadit na všechny cíle z konfigurace, Targets se nepoužije
+	Force    bool     // Přepsat existující zámek nasazení na serveru
+	Artifact string   // Artefakt tar.gz, jehož obsah se nasadí místo souborů z konfigurace
+	Only     []string // Vzory souborů (např. "data/"); neprázdné = nasadit jen odpovídající soubory
+	DryRun   bool     // Jen sestavit plán nasazení, k serveru se nepřipojovat
+	RunID    string   // Identifikátor běhu, pod kterým se nasazení zaznamená (viz runid)
+	Dial     Dialer   // Otevře spojení k cíli (např. MemoryServer.Dial v testech); nil = FTP, nebo SFTP podle protocol cíle
+
+	// AcceptNewHostKey při prvním připojení k SFTP serveru uloží jeho klíč do
+	// known_hosts. Klíč, který se od uloženého liší, se nepřijme nikdy.
+	AcceptNewHostKey bool
+}
+
+// PromoteOptions určuje, který artefakt Promote povýší a kam.
+type PromoteOptions struct {
+	From   string // Cíl, jehož poslední úspěšné úplné nasazení se povýší
+	To     string // Cíl, na který se artefakt nasadí
+	Force  bool   // Přepsat existující zámek nasazení na serveru
+	DryRun bool   // Jen sestavit plán nasazení, k serveru se nepřipojovat
+	RunID  string // Identifikátor běhu, pod kterým se nasazení zaznamená (viz runid)
+	Dial   Dialer // Otevře spojení k cíli; nil = FTP, nebo SFTP podle protocol cíle
+
+	AcceptNewHostKey bool // Uložit do known_hosts klíč SFTP serveru, který tam ještě není (viz Options)
+}
+
+// Result je výsledek nasazení. Při zkušebním běhu obsahuje jen Plan, jinak Report.
+type Result struct {
+	Report *Report
+	Plan   *Plan
+}
+
+// Value vrací report, nebo při zkušebním běhu plán nasazení.
+func (r *Result) Value() any {
+	if r.Plan != nil {
+		return r.Plan
+	}
+	return r.Report
+}
+
+// Deploy nasadí soubory ze sekce phase3 konfigurace, nebo s opts.Artifact obsah
+// sestaveného artefaktu. Na více cílů nasazuje souběžně a selhání jednoho cíle
+// nepřeruší ostatní. Pokud nasazení na některý cíl skončí s chybou, vrací chybu
+// i výsledek s reportem všech cílů. Zrušení ctx nahrávání řádně ukončí.
+func Deploy(ctx context.Context, config *Config, opts Options) (*Result, error) {
+	if config.Phase3 == nil {
+		return nil, errNoSection()
+	}
+
+	// Soubory pochází buď z konfigurace, nebo z rozbaleného artefaktu.
+	files := filesToUpload(config)
+	if opts.Artifact != "" {
+		dir, extracted, err := extractArchive(opts.Artifact)
+		if err != nil {
+			return nil, exitcode.With(exitcode.Data, err)
+		}
+		defer os.RemoveAll(dir)
+		files = extracted
+	}
+
+	files = filterFiles(files, opts.Only)
+	if len(files) == 0 && len(opts.Only) > 0 {
+		return nil, exitcode.With(exitcode.NothingToDo, errors.New(i18n.T("zadaným vzorům neodpovídá žádný soubor, není co nasadit")))
+	}
+	do := deployOptions{Force: opts.Force, Partial: len(opts.Only) > 0, DryRun: opts.DryRun, RunID: opts.RunID, Dial: opts.Dial,
+		AcceptNewHostKey: opts.AcceptNewHostKey}
+	return runDeploy(ctx, config, selectTargets(config, opts.Targets, opts.All), files, do)
+}
+
+// Promote znovu nasadí artefakt posledního úspěšného úplného nasazení na cíl
+// opts.From na cíl opts.To. Nic se znovu nesestavuje, nahrává se přesně obsah
+// ověřený na cíli opts.From.
+func Promote(ctx context.Context, config *Config, opts PromoteOptions) (*Result, error) {
+	if config.Phase3 == nil {
+		return nil, errNoSection()
+	}
+	return runPromote(ctx, config, opts.From, opts.To, deployOptions{Force: opts.Force, DryRun: opts.DryRun, RunID: opts.RunID, Dial: opts.Dial,
+		AcceptNewHostKey: opts.AcceptNewHostKey})
+}
+
+// History vrací záznamy historie nasazení v pořadí, v jakém byly zapsány.
+func History(config *Config) ([]HistoryEntry, error) {
+	if config.Phase3 == nil {
+		return nil, errNoSection()
+	}
+	return readHistory(historyFile(config))
+}
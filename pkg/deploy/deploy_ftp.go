@@ -0,0 +1,648 @@
+package deploy
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+	"golang.org/x/sync/errgroup"
+
+	"hugo72/internal/exitcode"
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+	"hugo72/internal/problems"
+	"hugo72/internal/profile"
+)
+
+// defaultTargetName je název výchozího cíle definovaného přímo v sekci phase3.
+const defaultTargetName = "default"
+
+// lookupTarget vrací cíl nasazení podle názvu.
+// Prázdný název nebo "default" označuje výchozí cíl ze sekce phase3.
+func lookupTarget(config *Config, name string) (Target, error) {
+	if name == "" || name == defaultTargetName {
+		return config.Phase3.Target, nil
+	}
+	target, ok := config.Phase3.Targets[name]
+	if !ok {
+		return Target{}, i18n.Errorf("cíl nasazení '%s' není v konfiguraci definován", name)
+	}
+	return target, nil
+}
+
+// deployFile je soubor určený k nahrání.
+// Lokální cesta se může lišit od vzdálené, například při nasazení z uloženého artefaktu.
+type deployFile struct {
+	Local  string // Cesta k souboru v lokálním souborovém systému
+	Remote string // Cesta k souboru relativně k cílovému adresáři na serveru
+}
+
+// connectToFtp se připojí k FTP serveru cíle pomocí jeho přihlašovacích údajů.
+// Vrací připojení k serveru nebo chybu, pokud se připojení nezdaří.
+// Všechny příkazy nového spojení dodržují prodlevu podle th.
+func connectToFtp(ctx context.Context, target Target, th *throttle, logger *slog.Logger) (*client, error) {
+	// Pokus o připojení k FTP serveru s nastavením timeoutu 5 sekund.
+	options := []ftp.DialOption{ftp.DialWithTimeout(5 * time.Second)}
+
+	// Šifrované spojení (FTPS) podle nastavení cíle.
+	var tc *tls.Config
+	switch target.TLS {
+	case tlsNone:
+	case tlsExplicit, tlsImplicit:
+		var err error
+		if tc, err = tlsConfig(target, logger); err != nil {
+			return nil, err
+		}
+		if target.TLS == tlsExplicit {
+			options = append(options, ftp.DialWithExplicitTLS(tc))
+		} else {
+			options = append(options, ftp.DialWithTLS(tc))
+		}
+	default:
+		return nil, i18n.Errorf("neznámý režim tls '%s', povoleno je \"explicit\" nebo \"implicit\"", target.TLS)
+	}
+
+	// Obejití chyb serveru, např. nesprávné adresy v odpovědi PASV.
+	options = append(options, quirkDialOptions(target, tc)...)
+
+	th.wait()
+	conn, err := ftp.Dial(target.FtpHost, options...)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při připojování k FTP serveru: %w", err)
+	}
+
+	// Přihlášení na FTP server pomocí poskytnutých přihlašovacích údajů.
+	th.wait()
+	if err := conn.Login(target.FtpUser, target.FtpPassword); err != nil {
+		conn.Quit()
+		return nil, i18n.Errorf("chyba při přihlášení na FTP server: %w", err)
+	}
+
+	logger.Info("Úspěšně připojeno k FTP serveru.", "host", target.FtpHost)
+	return &client{conn: &ftpTransport{conn: conn}, throttle: th, logger: logger, ctx: ctx}, nil
+}
+
+// ftpTransport je spojení s FTP serverem.
+type ftpTransport struct {
+	conn *ftp.ServerConn
+}
+
+// Stor nahraje obsah readeru do souboru na serveru.
+func (t *ftpTransport) Stor(remote string, r io.Reader) error {
+	return t.conn.Stor(remote, r)
+}
+
+// Retr otevře soubor na serveru ke čtení.
+func (t *ftpTransport) Retr(remote string) (io.ReadCloser, error) {
+	return t.conn.Retr(remote)
+}
+
+// Delete smaže soubor na serveru.
+func (t *ftpTransport) Delete(remote string) error {
+	return t.conn.Delete(remote)
+}
+
+// List vypíše obsah adresáře na serveru. Odkazy a jiné zvláštní položky vynechá.
+func (t *ftpTransport) List(dir string) ([]Entry, error) {
+	list, err := t.conn.List(dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, e := range list {
+		if e.Type != ftp.EntryTypeFile && e.Type != ftp.EntryTypeFolder {
+			continue
+		}
+		entries = append(entries, Entry{Name: e.Name, Size: int64(e.Size), Dir: e.Type == ftp.EntryTypeFolder, ModTime: e.Time})
+	}
+	return entries, nil
+}
+
+// MakeDir vytvoří adresář na serveru.
+func (t *ftpTransport) MakeDir(dir string) error {
+	return t.conn.MakeDir(dir)
+}
+
+// Quit ukončí spojení se serverem.
+func (t *ftpTransport) Quit() error {
+	return t.conn.Quit()
+}
+
+// uploadFile nahraje jeden soubor na server do zadaného adresáře.
+//
+// Očekávaný formát souborů je takový, že každý soubor musí být dostupný
+// v lokálním souborovém systému. Tato funkce otevře lokální soubor a nahraje
+// ho do cílového adresáře na serveru pod jeho vzdáleným názvem.
+func uploadFile(conn *client, remoteDir string, f deployFile) error {
+	conn.logger.Debug("Nahrávám soubor.", "file", f.Remote, "local", f.Local)
+
+	// Otevření lokálního souboru k nahrání.
+	file, err := os.Open(f.Local)
+	if err != nil {
+		return i18n.Errorf("chyba při otevření lokálního souboru '%s': %w", f.Local, err)
+	}
+	defer file.Close()
+
+	// Nahrání souboru na server. Zrušení nasazení přenos přeruší.
+	if err := conn.Stor(path.Join(remoteDir, f.Remote), contextReader{ctx: conn.ctx, r: file}); err != nil {
+		return i18n.Errorf("chyba při nahrávání souboru '%s' na server: %w", f.Remote, err)
+	}
+
+	conn.logger.Info("Soubor byl úspěšně nahrán na server.", "file", f.Remote)
+	return nil
+}
+
+// uploadWithRetry nahraje soubor a při chybě pokus až retries-krát zopakuje.
+// Vrací záznam pro report nasazení včetně velikosti, doby trvání a počtu opakování.
+func uploadWithRetry(conn *client, remoteDir string, f deployFile, retries int) fileResult {
+	fr := fileResult{Path: f.Remote}
+	if info, err := os.Stat(f.Local); err == nil {
+		fr.Size = info.Size()
+	}
+
+	start := time.Now()
+	err := uploadFile(conn, remoteDir, f)
+	for err != nil && fr.Retries < retries && conn.ctx.Err() == nil {
+		fr.Retries++
+		// Krátká prodleva před dalším pokusem, prodlužovaná s každým opakováním.
+		select {
+		case <-time.After(time.Duration(fr.Retries) * 2 * time.Second):
+		case <-conn.ctx.Done():
+		}
+		conn.logger.Warn("Opakuji nahrání souboru.", "file", f.Remote, "attempt", fr.Retries, "retries", retries, "error", err)
+		err = uploadFile(conn, remoteDir, f)
+	}
+	fr.Duration = time.Since(start)
+
+	// Přenos přerušený zrušením nasazení nechal na serveru nedokončený soubor.
+	if err != nil && conn.ctx.Err() != nil {
+		fr.Status = statusSkipped
+		fr.Error = "nasazení bylo zrušeno během přenosu"
+		if err := conn.Delete(path.Join(remoteDir, f.Remote)); err != nil {
+			conn.logger.Warn("Nedokončený soubor se nepodařilo smazat.", "file", f.Remote, "error", err)
+		} else {
+			conn.logger.Info("Nedokončený soubor byl ze serveru smazán.", "file", f.Remote)
+		}
+		return fr
+	}
+
+	if err != nil {
+		fr.Status = statusFailed
+		fr.Error = err.Error()
+		return fr
+	}
+	fr.Status = statusUploaded
+	return fr
+}
+
+// runDeploy nasadí soubory na jeden nebo více pojmenovaných cílů a zpracuje výsledky:
+// uloží artefakt, zapíše společný report, doplní historii nasazení a případně oznámí
+// výsledek webhookem. Na více cílů se nasazuje souběžně (počet současně nasazovaných
+// cílů omezuje maxParallelTargets) a selhání jednoho cíle nepřeruší ostatní. Vrací
+// errDeployFailed spolu s výsledkem, pokud nasazení na některý cíl skončilo s chybou.
+// Při zkušebním běhu vrací jen plán nasazení.
+func runDeploy(ctx context.Context, config *Config, targetNames []string, files []deployFile, opts deployOptions) (*Result, error) {
+	logger := logging.Phase("deploy")
+	commit := gitCommit()
+	targets := make([]Target, len(targetNames))
+	for i, name := range targetNames {
+		target, err := lookupTarget(config, name)
+		if err == nil {
+			target.RemoteDir, err = expandRemoteDir(target.RemoteDir, commit, time.Now())
+		}
+		if err != nil {
+			return nil, exitcode.With(exitcode.Config, err)
+		}
+		targets[i] = target
+	}
+
+	// Kontrola obsahu před připojením: rozbité sestavení se na server vůbec nedostane.
+	if err := validateFiles(files, config.Phase3.MinTotalSize, opts.Partial); err != nil {
+		return nil, exitcode.With(exitcode.Data, i18n.Errorf("nasazení odmítnuto, obsah neprošel kontrolou:\n%w", err))
+	}
+
+	// Zkušební běh jen vypíše plán, nic neukládá a k serveru se nepřipojí.
+	if opts.DryRun {
+		plan := planDeploy(config, targetNames, targets, files)
+		logPlan(plan)
+		return &Result{Plan: &plan}, nil
+	}
+
+	// Manifest identifikuje nasazovaný obsah v historii nasazení.
+	// Artefakt se uloží, aby ho šlo později beze změny povýšit na jiný cíl.
+	cachePath := config.Phase3.ChecksumCache
+	if cachePath == "" {
+		cachePath = defaultChecksumCache
+	}
+	cache := loadChecksumCache(cachePath)
+	stop := profile.Track("deploy", "manifest")
+	m, err := buildManifest(ctx, files, cache)
+	stop()
+	if err := cache.save(); err != nil {
+		logger.Warn("Mezipaměť kontrolních součtů nelze uložit.", "error", err)
+		problems.Warn("deploy", cachePath, err)
+	}
+
+	// Na uložení artefaktu nasazení nezávisí, proto běží souběžně s ním.
+	var artifact errgroup.Group
+	if err != nil {
+		logger.Error("Chyba při sestavování manifestu.", "error", err)
+		problems.Add("deploy", "manifest", err)
+	} else {
+		artifact.Go(func() error {
+			defer profile.Track("deploy", "artifact")()
+			return storeArtifact(artifactDir(config), files, m)
+		})
+	}
+
+	opts.commit, opts.manifestHash = commit, m.hash()
+
+	// Nasazení na jednotlivé cíle běží souběžně, nejvýše maxParallelTargets najednou;
+	// záznamy v logu rozlišuje pole target. Cíl, na který po zrušení ještě nedošla
+	// řada, se už nezačne nasazovat.
+	results := make([]*deployResult, len(targetNames))
+	var g errgroup.Group
+	if config.Phase3.MaxParallelTargets > 0 {
+		g.SetLimit(config.Phase3.MaxParallelTargets)
+	}
+	for i, name := range targetNames {
+		g.Go(func() error {
+			results[i] = deploy(ctx, config, name, targets[i], files, opts, logger.With("target", name))
+			logSummary(results[i])
+			return nil
+		})
+	}
+	g.Wait()
+	if err := artifact.Wait(); err != nil {
+		logger.Error("Chyba při ukládání artefaktu.", "error", err)
+		problems.Add("deploy", artifactDir(config), err)
+	}
+
+	// Zápis strojově čitelného reportu vedle běžného logu.
+	reportFile := config.Phase3.ReportFile
+	if reportFile == "" {
+		reportFile = defaultReportFile
+	}
+	report := newReport(results...)
+	report.RunID, report.Commit = opts.RunID, commit
+	stop = profile.Track("deploy", "report")
+	err = writeReport(reportFile, report)
+	stop()
+	if err != nil {
+		logger.Error("Chyba při zápisu reportu.", "file", reportFile, "error", err)
+		problems.Add("deploy", reportFile, err)
+	}
+
+	success := true
+	for i, result := range results {
+		// Záznam do historie slouží pro audit a jako podklad pro povýšení či návrat k dřívější verzi.
+		entry := HistoryEntry{
+			RunID:        opts.RunID,
+			Name:         result.Name,
+			Target:       result.Target,
+			Partial:      opts.Partial,
+			Timestamp:    result.Start,
+			Commit:       commit,
+			ManifestHash: m.hash(),
+			RemoteDir:    targets[i].RemoteDir,
+			Files:        len(m),
+			Success:      len(result.Errors) == 0,
+		}
+		stop := profile.Track("deploy", "history")
+		err := appendHistory(historyFile(config), entry)
+		stop()
+		if err != nil {
+			logger.Error("Chyba při zápisu historie nasazení.", "target", result.Name, "error", err)
+			problems.Add("deploy", historyFile(config), err)
+		}
+
+		// Oznámení výsledku nasazení externí automatizaci (např. n8n).
+		if config.Phase3.WebhookURL != "" {
+			stop := profile.Track("deploy", "webhook")
+			err := sendWebhook(config.Phase3.WebhookURL, opts.RunID, result)
+			stop()
+			if err != nil {
+				logger.Error("Chyba při odesílání webhooku.", "target", result.Name, "error", err)
+				problems.Add("deploy", result.Name+": webhook", err)
+			}
+		}
+		success = success && len(result.Errors) == 0
+	}
+
+	if len(results) > 1 {
+		logTargetsSummary(results)
+	}
+	if !success {
+		return &Result{Report: &report}, errDeployFailed()
+	}
+	return &Result{Report: &report}, nil
+}
+
+// selectTargets vrací názvy cílů, na které se má nasadit. S volbou all jsou to
+// všechny cíle z konfigurace, jinak zadané názvy (bez opakování) nebo výchozí cíl.
+func selectTargets(config *Config, names []string, all bool) []string {
+	if all {
+		if len(config.Phase3.Targets) == 0 {
+			return []string{defaultTargetName}
+		}
+		names = slices.Sorted(maps.Keys(config.Phase3.Targets))
+	}
+	if len(names) == 0 {
+		return []string{defaultTargetName}
+	}
+
+	var selected []string
+	for _, name := range names {
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// historyFile vrací cestu k historii nasazení z konfigurace nebo výchozí cestu.
+func historyFile(config *Config) string {
+	if config.Phase3.HistoryFile != "" {
+		return config.Phase3.HistoryFile
+	}
+	return defaultHistoryFile
+}
+
+// filesToUpload vrací seznam souborů k nahrání z konfigurace.
+// Každý soubor je nejprve očištěn od mezer na začátku a na konci názvu.
+// Pokud je jméno prázdné (například z neplatného záznamu), přeskočíme ho.
+func filesToUpload(config *Config) []deployFile {
+	var files []deployFile
+	for _, file := range config.Phase3.FilesToUpload {
+		file = strings.TrimSpace(file) // Odstranění mezer okolo názvu souboru
+		if file == "" {
+			continue // Přeskočení prázdných položek v seznamu
+		}
+		files = append(files, deployFile{Local: file, Remote: file})
+	}
+	return files
+}
+
+// filterFiles vrací jen soubory, jejichž vzdálená cesta odpovídá některému ze vzorů.
+// Bez vzorů vrací všechny soubory.
+func filterFiles(files []deployFile, patterns []string) []deployFile {
+	if len(patterns) == 0 {
+		return files
+	}
+	var selected []deployFile
+	for _, f := range files {
+		for _, p := range patterns {
+			if matchPattern(p, f.Remote) {
+				selected = append(selected, f)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// deployOptions obsahuje volby jednoho nasazení odvozené z Options.
+type deployOptions struct {
+	Force            bool   // Přepsat existující zámek nasazení
+	Partial          bool   // Nasazuje se jen část webu vybraná vzory
+	DryRun           bool   // Jen sestavit plán, k serveru se nepřipojovat
+	RunID            string // Identifikátor běhu pro report, historii, webhook a deployed.json
+	Dial             Dialer // Otevře spojení k cíli; nil = FTP, nebo SFTP podle protocol cíle
+	AcceptNewHostKey bool   // Uložit do known_hosts klíč SFTP serveru, který tam ještě není
+
+	// Doplní runDeploy před nasazením na cíle pro soubor deployed.json.
+	commit       string
+	manifestHash string
+}
+
+// deploy se připojí k serveru cíle, uzamkne cílový adresář a nahraje zadané soubory.
+// Chyby nepřeruší nasazení, ale jsou zaznamenány do výsledku. Průběh se vypisuje do logu logger,
+// jehož záznamy nesou název cíle.
+// Po zrušení ctx se další soubory už nenahrávají a nasazení se řádně ukončí včetně uvolnění zámku.
+func deploy(ctx context.Context, config *Config, name string, target Target, files []deployFile, opts deployOptions, logger *slog.Logger) *deployResult {
+	result := &deployResult{
+		logger: logger,
+		ctx:    ctx,
+		Name:   name,
+		Target: target.FtpHost + target.RemoteDir,
+		Start:  time.Now(),
+	}
+	defer func() { result.Duration = time.Since(result.Start) }()
+
+	lockTimeout, err := parseDuration("lockTimeout", config.Phase3.LockTimeout, defaultLockTimeout)
+	if err != nil {
+		result.addError(err)
+		return result
+	}
+	commandDelay, err := parseDuration("commandDelay", config.Phase3.CommandDelay, 0)
+	if err != nil {
+		result.addError(err)
+		return result
+	}
+	th := &throttle{delay: commandDelay}
+	policy, err := newFailurePolicy(config.Phase3.FailurePolicy, config.Phase3.MaxErrors)
+	if err != nil {
+		result.addError(err)
+		return result
+	}
+	// Nasazení zrušené ještě před začátkem se k serveru vůbec nepřipojí.
+	if result.abort(policy) {
+		return result
+	}
+
+	// Připojení k serveru s využitím údajů z konfigurace, bez jiné funkce dial přes FTP.
+	dial := opts.Dial
+	if dial == nil && target.Protocol == protocolSFTP {
+		dial = sftpDialer(opts.AcceptNewHostKey, logger)
+	}
+	stop := profile.Track("deploy", "connect")
+	conn, err := connect(ctx, target, th, logger, dial)
+	stop()
+	if err != nil {
+		result.addError(err)
+		return result
+	}
+	defer conn.Quit()
+
+	// Kontrola volného místa ještě před zahájením nahrávání.
+	isFTP := dial == nil
+	if spaceCheckEnabled(target) && (isFTP || target.SpaceProbe == "") {
+		stop := profile.Track("deploy", "space")
+		err := checkFreeSpace(conn, target, th, files)
+		stop()
+		if err != nil {
+			result.addError(err)
+			return result
+		}
+	}
+
+	// Adresář ze šablony (např. pro nové vydání) na serveru ještě nemusí existovat.
+	makeDirAll(conn, target.RemoteDir)
+
+	// Zamčení cílového adresáře, aby dvě souběžná nasazení nepoškodila web.
+	stop = profile.Track("deploy", "lock")
+	err = acquireLock(conn, target.RemoteDir, lockTimeout, opts.Force)
+	stop()
+	if err != nil {
+		result.addError(err)
+		return result
+	}
+	defer func() {
+		if err := releaseLock(conn, target.RemoteDir); err != nil {
+			logger.Error("Zámek nasazení se nepodařilo uvolnit.", "error", err)
+			problems.Add("deploy", name+": "+lockFileName, err)
+		}
+	}()
+
+	// Soubory se nahrávají v pevném pořadí: nejprve styly a obrázky, potom HTML, data nakonec.
+	files = orderFiles(files)
+
+	// Během nasazení mohou návštěvníci vidět napůl nahraný web. Pokud je nastavena
+	// stránka údržby, nahraje se nejprve místo index.html a skutečný index se vrátí až nakonec.
+	var index *deployFile
+	if config.Phase3.MaintenancePage != "" {
+		files, index = splitIndex(files)
+		if index == nil {
+			logger.Warn("Stránka údržby se nepoužije, mezi soubory chybí index.html.")
+		} else {
+			maintenance := deployFile{Local: config.Phase3.MaintenancePage, Remote: index.Remote}
+			stop := profile.Track("deploy", "maintenance")
+			err := uploadFile(conn, target.RemoteDir, maintenance)
+			stop()
+			if err != nil {
+				logger.Error("Chyba při nahrávání stránky údržby.", "file", maintenance.Local, "error", err)
+				problems.Warn("deploy", name+": "+maintenance.Local, err)
+			} else {
+				logger.Info("Stránka údržby je aktivní.")
+			}
+		}
+	}
+
+	// Další spojení pro souběžné nahrávání, pokud je konfigurace povoluje.
+	conns := []*client{conn}
+	for len(conns) < config.Phase3.MaxConnections {
+		stop := profile.Track("deploy", "connect")
+		extra, err := connect(ctx, target, th, logger, dial)
+		stop()
+		if err != nil {
+			logger.Warn("Další spojení se nepodařilo otevřít.", "connections", len(conns), "error", err)
+			break
+		}
+		defer extra.Quit()
+		conns = append(conns, extra)
+	}
+
+	// Nahrání souborů po skupinách, které rozdělí mezi otevřená spojení.
+	stop = profile.Track("deploy", "mkdir")
+	ensureDirs(conn, target.RemoteDir, files)
+	stop()
+	uploadAll(conns, config, target, files, policy, result)
+
+	// Obnovení skutečného index.html jako úplně poslední krok.
+	// Po přerušení nasazení zůstane raději viditelná stránka údržby než nekonzistentní web.
+	if index != nil {
+		if result.abort(policy) {
+			logger.Warn("Nasazení bylo přerušeno, stránka údržby zůstává aktivní.")
+			skipFile(*index, result)
+		} else {
+			uploadInto(conn, config, target, *index, result)
+		}
+	}
+
+	// Nastavení práv nahraným souborům, např. spustitelné skripty v cgi-bin.
+	if len(config.Phase3.Permissions) > 0 {
+		stop := profile.Track("deploy", "permissions")
+		if sftpConn, ok := conn.conn.(*sftpTransport); ok {
+			applySFTPPermissions(sftpConn, target.RemoteDir, config.Phase3.Permissions, result)
+		} else if isFTP {
+			applyPermissions(target, th, config.Phase3.Permissions, result)
+		}
+		stop()
+	}
+
+	// Údaje o nasazení na serveru propojí živý web s během, který ho nasadil.
+	// Jejich chyba nasazení nezhorší, soubory webu jsou už nahrané.
+	if !config.Phase3.DisableDeployInfo && len(result.Errors) == 0 && !result.abort(policy) {
+		info := deployInfo{
+			RunID:        opts.RunID,
+			Target:       name,
+			Commit:       opts.commit,
+			ManifestHash: opts.manifestHash,
+			DeployedAt:   time.Now(),
+			Files:        len(files),
+			Partial:      opts.Partial,
+		}
+		stop := profile.Track("deploy", deployInfoFileName)
+		err := writeDeployInfo(conn, target.RemoteDir, info)
+		stop()
+		if err != nil {
+			logger.Warn("Údaje o nasazení se nepodařilo nahrát.", "error", err)
+			problems.Warn("deploy", name+": "+deployInfoFileName, err)
+		}
+	}
+	return result
+}
+
+// uploadInto nahraje soubor na cíl a výsledek zapíše do výsledku nasazení.
+// Funkci lze volat souběžně z více spojení.
+func uploadInto(conn *client, config *Config, target Target, file deployFile, result *deployResult) {
+	// Pokus o nahrání souboru na FTP server
+	fr := uploadWithRetry(conn, target.RemoteDir, file, config.Phase3.Retries)
+	profile.Observe("deploy", uploadBucket(fr.Size), fr.Duration, fr.Size)
+	result.add(fr)
+	if fr.Status == statusFailed {
+		conn.logger.Error("Chyba při nahrávání souboru.", "file", file.Remote, "error", fr.Error)
+	}
+}
+
+// parseDuration převede dobu trvání z konfigurace. Prázdná hodnota znamená výchozí dobu.
+func parseDuration(name, value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, i18n.Errorf("neplatná hodnota %s '%s': %w", name, value, err)
+	}
+	return d, nil
+}
+
+// splitIndex vyjme ze seznamu kořenový index.html.
+// Vrací zbylé soubory a index, případně nil, pokud v seznamu není.
+func splitIndex(files []deployFile) ([]deployFile, *deployFile) {
+	rest := make([]deployFile, 0, len(files))
+	var index *deployFile
+	for i := range files {
+		if index == nil && path.Clean(files[i].Remote) == "index.html" {
+			index = &files[i]
+			continue
+		}
+		rest = append(rest, files[i])
+	}
+	return rest, index
+}
+
+// uploadBucket vrací krok profilu běhu (viz profile), do kterého se započítá
+// nahrání souboru dané velikosti. Podle skupin je vidět, zda nahrávání
+// zdržuje množství malých souborů (prodleva příkazů), nebo velké soubory
+// (přenosová rychlost).
+func uploadBucket(size int64) string {
+	switch {
+	case size < 10<<10:
+		return "upload <10kB"
+	case size < 100<<10:
+		return "upload 10-100kB"
+	case size < 1<<20:
+		return "upload 100kB-1MB"
+	default:
+		return "upload >=1MB"
+	}
+}
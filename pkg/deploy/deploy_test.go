@@ -0,0 +1,420 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hugo72/pkg/config"
+)
+
+// testSite jsou soubory webu, které testy nasazují. Pořadí nahrávání určuje
+// orderFiles: styly první, HTML potom, data nakonec.
+var testSite = map[string]string{
+	"index.html":     "<h1>sraz</h1>",
+	"about.html":     "<p>o nás</p>",
+	"style.css":      "h1 { color: red }",
+	"data/data.json": `{"users": []}`,
+	"udrzba.html":    "<p>probíhá údržba</p>",
+}
+
+// opLog zaznamenává operace, které nasazení provedlo na MemoryServer.
+type opLog struct {
+	mu  sync.Mutex
+	ops []string
+}
+
+// record přidá operaci ve tvaru "op cesta".
+func (l *opLog) record(op, remote string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ops = append(l.ops, op+" "+remote)
+}
+
+// stors vrací cesty nahraných souborů v pořadí nahrávání, bez zámku a deployed.json.
+func (l *opLog) stors() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var paths []string
+	for _, op := range l.ops {
+		remote, ok := strings.CutPrefix(op, "stor ")
+		if ok && !strings.HasSuffix(remote, lockFileName) && !strings.HasSuffix(remote, deployInfoFileName) {
+			paths = append(paths, remote)
+		}
+	}
+	return paths
+}
+
+// setupDeploy připraví v dočasném adresáři soubory webu, přepne se do něj
+// (cesty souborů v konfiguraci jsou relativní) a vrací konfiguraci s cílem
+// na MemoryServer do adresáře /www.
+func setupDeploy(t *testing.T) *Config {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range testSite {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	cfg := &Config{Phase3: &config.Phase3{
+		Target:        Target{FtpHost: "memory", RemoteDir: "/www"},
+		FilesToUpload: []string{"index.html", "about.html", "style.css", "data/data.json"},
+	}}
+	return cfg
+}
+
+// failOnce vrací funkci pro MemoryServer.Fail, která nechá selhat první
+// times nahrání souboru remote.
+func failOnce(remote string, times int) func(op, path string) error {
+	var mu sync.Mutex
+	return func(op, path string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if op == "stor" && path == remote && times > 0 {
+			times--
+			return errors.New("simulovaná chyba serveru")
+		}
+		return nil
+	}
+}
+
+// targetReport vrací report jediného cíle nasazení.
+func targetReport(t *testing.T, result *Result) TargetReport {
+	t.Helper()
+	if result == nil || result.Report == nil || len(result.Report.Targets) != 1 {
+		t.Fatalf("nasazení nevrátilo report jednoho cíle: %+v", result)
+	}
+	return result.Report.Targets[0]
+}
+
+// fileStatus vrací stav souboru v reportu cíle.
+func fileStatus(tr TargetReport, path string) FileReport {
+	for _, f := range tr.Files {
+		if f.Path == path {
+			return f
+		}
+	}
+	return FileReport{}
+}
+
+func TestDeployUploadsFilesInOrder(t *testing.T) {
+	cfg := setupDeploy(t)
+	srv := NewMemoryServer()
+	var log opLog
+	srv.Fail = func(op, remote string) error { log.record(op, remote); return nil }
+
+	result, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial})
+	if err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+	if tr := targetReport(t, result); !tr.Success || tr.Summary.Uploaded != 4 {
+		t.Errorf("report = %+v, chci 4 nahrané soubory bez chyb", tr.Summary)
+	}
+	for name := range testSite {
+		if name == "udrzba.html" {
+			continue
+		}
+		data, ok := srv.File("/www/" + name)
+		if !ok || string(data) != testSite[name] {
+			t.Errorf("soubor %s na serveru = %q, %v; chci %q", name, data, ok, testSite[name])
+		}
+	}
+	want := []string{"/www/style.css", "/www/about.html", "/www/index.html", "/www/data/data.json"}
+	if got := log.stors(); !slices.Equal(got, want) && !slices.Equal(got, []string{want[0], want[2], want[1], want[3]}) {
+		t.Errorf("pořadí nahrávání = %v, chci styly, HTML a data nakonec", got)
+	}
+}
+
+func TestDeployRetriesFailedUpload(t *testing.T) {
+	cfg := setupDeploy(t)
+	cfg.Phase3.Retries = 1
+	srv := NewMemoryServer()
+	srv.Fail = failOnce("/www/style.css", 1)
+
+	result, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial})
+	if err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+	tr := targetReport(t, result)
+	if f := fileStatus(tr, "style.css"); f.Status != statusUploaded || f.Retries != 1 {
+		t.Errorf("style.css = %+v, chci nahraný po jednom opakování", f)
+	}
+	if tr.Summary.Retries != 1 {
+		t.Errorf("Summary.Retries = %d, chci 1", tr.Summary.Retries)
+	}
+}
+
+func TestDeployFailurePolicy(t *testing.T) {
+	tests := []struct {
+		policy       string
+		wantUploaded []string // Soubory, které musí být na serveru
+		wantSkipped  int
+		wantAborted  bool
+	}{
+		{policy: "continue", wantUploaded: []string{"/www/about.html", "/www/data/data.json", "/www/index.html"}},
+		{policy: "abort", wantSkipped: 3, wantAborted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			cfg := setupDeploy(t)
+			cfg.Phase3.FailurePolicy = tt.policy
+			srv := NewMemoryServer()
+			srv.Fail = failOnce("/www/style.css", 1)
+
+			result, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial})
+			if err == nil {
+				t.Fatal("Deploy s chybou souboru nevrátil chybu")
+			}
+			tr := targetReport(t, result)
+			if tr.Summary.Failed != 1 || tr.Summary.Skipped != tt.wantSkipped || tr.Aborted != tt.wantAborted {
+				t.Errorf("report = %+v (aborted %v), chci 1 chybu, %d přeskočených (aborted %v)", tr.Summary, tr.Aborted, tt.wantSkipped, tt.wantAborted)
+			}
+			var uploaded []string
+			for _, p := range srv.Files() {
+				if p != "/www/"+lockFileName {
+					uploaded = append(uploaded, p)
+				}
+			}
+			if !slices.Equal(uploaded, tt.wantUploaded) {
+				t.Errorf("soubory na serveru = %v, chci %v", uploaded, tt.wantUploaded)
+			}
+			if _, ok := srv.File("/www/" + deployInfoFileName); ok {
+				t.Errorf("nasazení s chybou nahrálo %s", deployInfoFileName)
+			}
+		})
+	}
+}
+
+func TestDeployReleasesLock(t *testing.T) {
+	cfg := setupDeploy(t)
+	srv := NewMemoryServer()
+	var lockedDuringUpload bool
+	srv.Fail = func(op, remote string) error {
+		if op == "stor" && remote == "/www/index.html" {
+			_, lockedDuringUpload = srv.File("/www/" + lockFileName)
+		}
+		return nil
+	}
+
+	if _, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial}); err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+	if !lockedDuringUpload {
+		t.Error("během nahrávání nebyl cílový adresář zamčený")
+	}
+	if _, ok := srv.File("/www/" + lockFileName); ok {
+		t.Error("zámek po nasazení zůstal na serveru")
+	}
+}
+
+func TestDeployRespectsForeignLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		age     time.Duration
+		force   bool
+		wantErr bool
+	}{
+		{name: "fresh", age: time.Minute, wantErr: true},
+		{name: "force", age: time.Minute, force: true},
+		{name: "stale", age: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setupDeploy(t)
+			srv := NewMemoryServer()
+			putLock(t, srv, "/www", remoteLock{Owner: "jiny@pocitac", PID: 1, CreatedAt: time.Now().Add(-tt.age)})
+
+			result, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial, Force: tt.force})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Deploy: chyba %v, chci chybu: %v", err, tt.wantErr)
+			}
+			_, uploaded := srv.File("/www/index.html")
+			_, locked := srv.File("/www/" + lockFileName)
+			if tt.wantErr {
+				if uploaded {
+					t.Error("nasazení přes cizí zámek nahrálo soubory")
+				}
+				if !locked {
+					t.Error("nasazení smazalo cizí zámek")
+				}
+				if tr := targetReport(t, result); !strings.Contains(strings.Join(tr.Errors, "\n"), "jiny@pocitac") {
+					t.Errorf("chyby %v neuvádí vlastníka zámku", tr.Errors)
+				}
+				return
+			}
+			if !uploaded || locked {
+				t.Errorf("nahráno %v, zámek zůstal %v; chci nahráno a zámek uvolněný", uploaded, locked)
+			}
+		})
+	}
+}
+
+func TestDeployLockRace(t *testing.T) {
+	cfg := setupDeploy(t)
+	srv := NewMemoryServer()
+	retrs := 0
+	srv.Fail = func(op, remote string) error {
+		// Druhé čtení zámku ověřuje zápis; jiné nasazení ho mezitím přepsalo.
+		if op == "retr" && remote == lockPath("/www") {
+			if retrs++; retrs == 2 {
+				srv.mu.Lock()
+				srv.files[remote] = memoryFile{data: []byte(`{"owner":"jiny@pocitac","nonce":"cizi"}`), modTime: time.Now()}
+				srv.mu.Unlock()
+			}
+		}
+		return nil
+	}
+
+	result, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial})
+	if err == nil {
+		t.Fatal("Deploy: chci chybu, zámek převzalo jiné nasazení")
+	}
+	if _, ok := srv.File("/www/index.html"); ok {
+		t.Error("nasazení bez zámku nahrálo soubory")
+	}
+	if data, _ := srv.File("/www/" + lockFileName); !strings.Contains(string(data), "cizi") {
+		t.Errorf("zámek jiného nasazení byl přepsán nebo smazán: %s", data)
+	}
+	if tr := targetReport(t, result); !strings.Contains(strings.Join(tr.Errors, "\n"), "jiny@pocitac") {
+		t.Errorf("chyby %v neuvádí vlastníka zámku", tr.Errors)
+	}
+}
+
+func TestDeployLockReadError(t *testing.T) {
+	cfg := setupDeploy(t)
+	srv := NewMemoryServer()
+	srv.Fail = func(op, remote string) error {
+		if op == "retr" && remote == lockPath("/www") {
+			return errors.New("421 příliš mnoho spojení")
+		}
+		return nil
+	}
+
+	if _, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial}); err == nil {
+		t.Fatal("Deploy: chci chybu, zámek nešlo přečíst")
+	}
+	if _, ok := srv.File("/www/index.html"); ok {
+		t.Error("nasazení s nepřečteným zámkem nahrálo soubory")
+	}
+}
+
+// putLock uloží na server zámek jiného nasazení.
+func putLock(t *testing.T, srv *MemoryServer, remoteDir string, lock remoteLock) {
+	t.Helper()
+	conn, err := srv.Dial(context.Background(), Target{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Quit()
+	data, err := json.Marshal(lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.MakeDir(remoteDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Stor(lockPath(remoteDir), strings.NewReader(string(data))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeployMaintenancePage(t *testing.T) {
+	cfg := setupDeploy(t)
+	cfg.Phase3.MaintenancePage = "udrzba.html"
+	srv := NewMemoryServer()
+	var log opLog
+	var indexDuringUpload string
+	srv.Fail = func(op, remote string) error {
+		log.record(op, remote)
+		if op == "stor" && remote == "/www/data/data.json" {
+			data, _ := srv.File("/www/index.html")
+			indexDuringUpload = string(data)
+		}
+		return nil
+	}
+
+	if _, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial}); err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+	stors := log.stors()
+	if len(stors) < 2 || stors[0] != "/www/index.html" || stors[len(stors)-1] != "/www/index.html" {
+		t.Errorf("pořadí nahrávání = %v, chci stránku údržby první a index.html poslední", stors)
+	}
+	if indexDuringUpload != testSite["udrzba.html"] {
+		t.Errorf("index.html během nahrávání = %q, chci stránku údržby", indexDuringUpload)
+	}
+	if data, _ := srv.File("/www/index.html"); string(data) != testSite["index.html"] {
+		t.Errorf("index.html po nasazení = %q, chci skutečný index", data)
+	}
+}
+
+func TestDeployKeepsMaintenancePageAfterAbort(t *testing.T) {
+	cfg := setupDeploy(t)
+	cfg.Phase3.MaintenancePage = "udrzba.html"
+	cfg.Phase3.FailurePolicy = "abort"
+	srv := NewMemoryServer()
+	srv.Fail = failOnce("/www/about.html", 1)
+
+	result, err := Deploy(context.Background(), cfg, Options{Dial: srv.Dial})
+	if err == nil {
+		t.Fatal("Deploy s chybou souboru nevrátil chybu")
+	}
+	if data, _ := srv.File("/www/index.html"); string(data) != testSite["udrzba.html"] {
+		t.Errorf("index.html po přerušení = %q, chci stránku údržby", data)
+	}
+	if f := fileStatus(targetReport(t, result), "index.html"); f.Status != statusSkipped {
+		t.Errorf("index.html = %+v, chci přeskočený", f)
+	}
+}
+
+func TestDeployDeletesPartialFileOnCancel(t *testing.T) {
+	cfg := setupDeploy(t)
+	srv := NewMemoryServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Zrušení těsně před nahráním: přenos se přeruší a na serveru zůstane neúplný soubor.
+	srv.Fail = func(op, remote string) error {
+		if op == "stor" && remote == "/www/about.html" {
+			cancel()
+		}
+		return nil
+	}
+
+	result, err := Deploy(ctx, cfg, Options{Dial: srv.Dial})
+	if err == nil {
+		t.Fatal("zrušené nasazení nevrátilo chybu")
+	}
+	tr := targetReport(t, result)
+	if !tr.Cancelled {
+		t.Error("report neoznačil nasazení jako zrušené")
+	}
+	if f := fileStatus(tr, "about.html"); f.Status != statusSkipped {
+		t.Errorf("about.html = %+v, chci přeskočený", f)
+	}
+	if _, ok := srv.File("/www/about.html"); ok {
+		t.Error("neúplný soubor po zrušení zůstal na serveru")
+	}
+	if _, ok := srv.File("/www/" + lockFileName); ok {
+		t.Error("zámek po zrušení zůstal na serveru")
+	}
+}
@@ -0,0 +1,50 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// deployInfoFileName je název souboru s údaji o nasazení, který se po úspěšném
+// nasazení nahraje do vzdáleného adresáře.
+const deployInfoFileName = "deployed.json"
+
+// deployInfo je obsah souboru deployed.json na serveru. Podle identifikátoru
+// běhu lze obsah živého webu dohledat v historii nasazení a v logu běhu,
+// který ho nasadil.
+//
+// Příklad:
+//
+//	{
+//	  "runId": "20250601-100000-3f9a2c1b",
+//	  "target": "production",
+//	  "commit": "9f1c...",
+//	  "manifestHash": "5d2a...",
+//	  "deployedAt": "2025-06-01T10:00:03+02:00",
+//	  "files": 42
+//	}
+type deployInfo struct {
+	RunID        string    `json:"runId,omitempty"`   // Identifikátor běhu, který nasazení provedl
+	Target       string    `json:"target"`            // Název cíle nasazení z konfigurace
+	Commit       string    `json:"commit,omitempty"`  // Commit, ze kterého nasazovaný obsah pochází
+	ManifestHash string    `json:"manifestHash"`      // Hash manifestu nasazených souborů
+	DeployedAt   time.Time `json:"deployedAt"`        // Okamžik dokončení nasazení
+	Files        int       `json:"files"`             // Počet nasazených souborů
+	Partial      bool      `json:"partial,omitempty"` // Nasazena byla jen část webu (deploy --only)
+}
+
+// writeDeployInfo nahraje do vzdáleného adresáře soubor deployed.json.
+func writeDeployInfo(conn *client, remoteDir string, info deployInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci údajů o nasazení: %w", err)
+	}
+	if err := conn.Stor(path.Join(remoteDir, deployInfoFileName), bytes.NewReader(data)); err != nil {
+		return i18n.Errorf("chyba při nahrávání souboru %s: %w", deployInfoFileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package deploy
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// defaultHistoryFile je výchozí cesta k lokální historii nasazení.
+const defaultHistoryFile = "deploy-history.jsonl"
+
+// HistoryEntry je jeden záznam v historii nasazení.
+// Historie je uložena jako JSON Lines, každý řádek odpovídá jednomu nasazení.
+type HistoryEntry struct {
+	RunID        string    `json:"runId,omitempty"` // Identifikátor běhu, který nasazení provedl
+	Name         string    `json:"name"`            // Název cíle nasazení z konfigurace
+	Target       string    `json:"target"`          // Identifikace cíle nasazení
+	Timestamp    time.Time `json:"timestamp"`       // Okamžik zahájení nasazení
+	Commit       string    `json:"commit"`          // Git commit, ze kterého nasazení proběhlo
+	ManifestHash string    `json:"manifestHash"`    // Hash manifestu nasazených souborů
+	RemoteDir    string    `json:"remoteDir"`       // Vzdálený adresář, do kterého se nasazovalo
+	Files        int       `json:"files"`           // Počet souborů v manifestu
+	Success      bool      `json:"success"`         // Zda nasazení proběhlo bez chyb
+	Partial      bool      `json:"partial"`         // Nasazena byla jen část webu (deploy --only)
+}
+
+// gitCommit vrací hash aktuálního git commitu, nebo prázdný řetězec,
+// pokud program neběží v git repozitáři.
+func gitCommit() string {
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// appendHistory připíše záznam na konec souboru s historií.
+func appendHistory(filePath string, entry HistoryEntry) error {
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return i18n.Errorf("chyba při otevírání historie nasazení '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return i18n.Errorf("chyba při zápisu historie nasazení '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// readHistory načte všechny záznamy historie v pořadí, v jakém byly zapsány.
+// Chybějící soubor znamená prázdnou historii.
+func readHistory(filePath string) ([]HistoryEntry, error) {
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba při otevírání historie nasazení '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, i18n.Errorf("chyba v historii nasazení '%s' na řádku %d: %w", filePath, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.Errorf("chyba při čtení historie nasazení '%s': %w", filePath, err)
+	}
+	return entries, nil
+}
+
+// lastSuccessful vrací poslední úspěšné úplné nasazení na zadaný cíl, nebo nil.
+// Částečná nasazení se přeskakují, protože jejich artefakt neobsahuje celý web.
+func lastSuccessful(entries []HistoryEntry, name string) *HistoryEntry {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Name == name && entries[i].Success && !entries[i].Partial {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// WriteHistory vypíše historii nasazení do w jako tabulku, nejnovější nasazení nahoře.
+func WriteHistory(w io.Writer, entries []HistoryEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T("ČAS\tNÁZEV\tCÍL\tVÝSLEDEK\tCOMMIT\tMANIFEST\tSOUBORY\tADRESÁŘ"))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		status := "ok"
+		if !e.Success {
+			status = i18n.T("chyba")
+		}
+		if e.Partial {
+			status += i18n.T(" (část)")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			e.Timestamp.Format(time.DateTime), e.Name, e.Target, status, short(e.Commit), short(e.ManifestHash), e.Files, e.RemoteDir)
+	}
+	tw.Flush()
+}
+
+// short zkrátí hash na prvních 12 znaků pro přehledný výpis.
+func short(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
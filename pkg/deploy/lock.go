@@ -0,0 +1,153 @@
+package deploy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"os/user"
+	"path"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// lockFileName je název zámku, který se během nasazení vytvoří ve vzdáleném adresáři.
+const lockFileName = ".hugo72-deploy.lock"
+
+// defaultLockTimeout určuje, po jaké době je cizí zámek považován za opuštěný,
+// pokud konfigurace neuvádí jinou hodnotu.
+const defaultLockTimeout = 30 * time.Minute
+
+// remoteLock je obsah zámku uloženého na serveru.
+// Z údajů je vidět, kdo a odkud nasazení spustil a kdy.
+type remoteLock struct {
+	Owner     string    `json:"owner"`     // Uživatel a počítač, který zámek vytvořil
+	PID       int       `json:"pid"`       // Číslo procesu, který zámek drží
+	CreatedAt time.Time `json:"createdAt"` // Okamžik vytvoření zámku
+	Nonce     string    `json:"nonce"`     // Náhodný údaj, podle kterého nasazení pozná svůj zámek
+}
+
+// lockPath vrací cestu k zámku ve vzdáleném adresáři.
+func lockPath(remoteDir string) string {
+	return path.Join(remoteDir, lockFileName)
+}
+
+// lockOwner sestaví identifikaci vlastníka zámku ve tvaru "uzivatel@pocitac".
+func lockOwner() string {
+	name := "neznamy"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "neznamy"
+	}
+	return name + "@" + host
+}
+
+// acquireLock vytvoří zámek na serveru a zabrání tak souběžnému nasazení.
+// Pokud na serveru již existuje čerstvý zámek (mladší než timeout), vrátí chybu,
+// ledaže je nastaveno force. Zastaralý zámek je přepsán s varováním.
+//
+// FTP neumí vytvořit soubor jen tehdy, když ještě neexistuje, a dvě nasazení
+// tak mohou zámek zapsat zároveň. Zámek proto nese náhodný údaj (nonce) a po
+// zápisu se přečte zpět: pokračuje jen nasazení, jehož zámek na serveru zůstal.
+func acquireLock(conn *client, remoteDir string, timeout time.Duration, force bool) error {
+	existing, err := readLock(conn, remoteDir)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		age := time.Since(existing.CreatedAt)
+		switch {
+		case force:
+			conn.logger.Warn("Přepisuji zámek jiného nasazení (--force).", "owner", existing.Owner, "created", existing.CreatedAt.Format(time.DateTime))
+		case age > timeout:
+			conn.logger.Warn("Zámek je starší než lockTimeout, považuji ho za opuštěný.", "owner", existing.Owner,
+				"created", existing.CreatedAt.Format(time.DateTime), "lockTimeout", timeout.String())
+		default:
+			return i18n.Errorf("nasazení již probíhá: zámek drží %s od %s (použijte --force, pokud jde o opuštěný zámek)", existing.Owner, existing.CreatedAt.Format(time.DateTime))
+		}
+	}
+
+	nonce := make([]byte, 8)
+	rand.Read(nonce)
+	lock := remoteLock{
+		Owner:     lockOwner(),
+		PID:       os.Getpid(),
+		CreatedAt: time.Now(),
+		Nonce:     hex.EncodeToString(nonce),
+	}
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci zámku: %w", err)
+	}
+	if err := conn.Stor(lockPath(remoteDir), bytes.NewReader(data)); err != nil {
+		return i18n.Errorf("chyba při vytváření zámku na serveru: %w", err)
+	}
+
+	stored, err := readLock(conn, remoteDir)
+	if err != nil {
+		return err
+	}
+	if stored == nil || stored.Nonce != lock.Nonce {
+		owner := "neznámý"
+		if stored != nil {
+			owner = stored.Owner
+		}
+		return i18n.Errorf("zámek současně vytvořilo jiné nasazení (%s), nasazení přerušeno", owner)
+	}
+	return nil
+}
+
+// readLock načte zámek ze serveru. Pokud zámek neexistuje, vrací nil bez chyby;
+// jinou chybu serveru vrací, aby nedostupný zámek nevedl k souběžnému nasazení.
+// Nečitelný zámek je považován za zámek neznámého vlastníka vytvořený v čase
+// poslední změny souboru na serveru. Pokud ho server neuvede, je čas nulový
+// a zámek se považuje za opuštěný.
+func readLock(conn *client, remoteDir string) (*remoteLock, error) {
+	resp, err := conn.Retr(lockPath(remoteDir))
+	if err != nil {
+		if isNotExist(err) {
+			return nil, nil
+		}
+		return nil, i18n.Errorf("chyba při čtení zámku ze serveru: %w", err)
+	}
+	data, err := io.ReadAll(resp)
+	resp.Close()
+	if err != nil {
+		return nil, i18n.Errorf("chyba při čtení zámku ze serveru: %w", err)
+	}
+
+	var lock remoteLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return &remoteLock{Owner: "neznámý", CreatedAt: lockModTime(conn, remoteDir)}, nil
+	}
+	return &lock, nil
+}
+
+// lockModTime vrací čas poslední změny zámku podle výpisu vzdáleného adresáře,
+// nebo nulový čas, pokud ho nelze zjistit.
+func lockModTime(conn *client, remoteDir string) time.Time {
+	entries, err := conn.List(remoteDir)
+	if err != nil {
+		return time.Time{}
+	}
+	for _, e := range entries {
+		if e.Name == lockFileName {
+			return e.ModTime
+		}
+	}
+	return time.Time{}
+}
+
+// releaseLock odstraní zámek ze serveru po dokončení nasazení.
+func releaseLock(conn *client, remoteDir string) error {
+	if err := conn.Delete(lockPath(remoteDir)); err != nil {
+		return i18n.Errorf("chyba při odstraňování zámku ze serveru: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+
+	"hugo72/internal/i18n"
+)
+
+// manifestEntry popisuje jeden soubor nasazovaného artefaktu.
+type manifestEntry struct {
+	Path   string `json:"path"`   // Cesta k souboru
+	Size   int64  `json:"size"`   // Velikost v bajtech
+	SHA256 string `json:"sha256"` // Kontrolní součet obsahu
+}
+
+// manifest je seznam souborů artefaktu seřazený podle cesty.
+// Jeho hash jednoznačně identifikuje obsah nasazení.
+type manifest []manifestEntry
+
+// buildManifest spočítá kontrolní součty zadaných souborů a sestaví manifest.
+// Do manifestu se zapisují vzdálené cesty, obsah se čte z lokálních souborů.
+// Je-li zadána mezipaměť, přepočítávají se jen změněné soubory.
+//
+// Soubory se počítají souběžně, nejvýše tolik najednou, kolik je procesorů.
+// První chyba nebo zrušení kontextu výpočet zbývajících souborů přeruší.
+func buildManifest(ctx context.Context, files []deployFile, cache *checksumCache) (manifest, error) {
+	m := make(manifest, len(files))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, f := range files {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			sum, size, err := cache.hash(f.Local)
+			if err != nil {
+				return err
+			}
+			m[i] = manifestEntry{Path: f.Remote, Size: size, SHA256: sum}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	sort.Slice(m, func(i, j int) bool { return m[i].Path < m[j].Path })
+	return m, nil
+}
+
+// hash vrací kontrolní součet celého manifestu.
+func (m manifest) hash() string {
+	h := sha256.New()
+	for _, e := range m {
+		fmt.Fprintf(h, "%s\x00%d\x00%s\n", e.Path, e.Size, e.SHA256)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashFile vrací SHA-256 obsahu souboru a jeho velikost.
+func hashFile(filePath string) (string, int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, i18n.Errorf("chyba při otevření souboru '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return "", 0, i18n.Errorf("chyba při čtení souboru '%s': %w", filePath, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
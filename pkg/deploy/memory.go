@@ -0,0 +1,214 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// MemoryServer je server nasazení v paměti. Jeho spojení (Dial) implementují
+// Transport, takže nasazení včetně opakování nahrávání, zámku a stránky údržby
+// lze vyzkoušet bez FTP serveru:
+//
+//	srv := deploy.NewMemoryServer()
+//	srv.Fail = func(op, remote string) error { ... } // Simulace chyb serveru
+//	result, err := deploy.Deploy(ctx, cfg, deploy.Options{Dial: srv.Dial})
+//	data, ok := srv.File("/www/index.html")
+//
+// Server se chová jako FTP server: soubor lze nahrát jen do existujícího
+// adresáře, existující adresář nelze vytvořit znovu a přerušené nahrávání
+// zanechá neúplný soubor. Lze ho používat souběžně z více spojení.
+type MemoryServer struct {
+	// Fail, pokud je nastavená, se zavolá před každou operací spojení ("stor",
+	// "retr", "delete", "list", "mkdir") a její chyba se vrátí místo provedení
+	// operace. Dial ji volá s operací "dial" a názvem serveru cíle.
+	Fail func(op, remote string) error
+
+	mu    sync.Mutex
+	files map[string]memoryFile
+	dirs  map[string]bool
+}
+
+// memoryFile je soubor uložený na MemoryServer.
+type memoryFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// NewMemoryServer vrací prázdný server s kořenovým adresářem.
+func NewMemoryServer() *MemoryServer {
+	return &MemoryServer{files: map[string]memoryFile{}, dirs: map[string]bool{"/": true}}
+}
+
+// Dial otevře nové spojení k serveru. Má tvar Dialer, lze ho tedy předat v Options.Dial.
+func (s *MemoryServer) Dial(_ context.Context, target Target) (Transport, error) {
+	if err := s.fail("dial", target.FtpHost); err != nil {
+		return nil, err
+	}
+	return &memoryConn{server: s}, nil
+}
+
+// File vrací obsah souboru na serveru.
+func (s *MemoryServer) File(remote string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[cleanRemote(remote)]
+	return bytes.Clone(f.data), ok
+}
+
+// Files vrací seřazené cesty všech souborů na serveru.
+func (s *MemoryServer) Files() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var paths []string
+	for p := range s.files {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// fail vrací chybu simulovanou funkcí Fail.
+func (s *MemoryServer) fail(op, remote string) error {
+	if s.Fail == nil {
+		return nil
+	}
+	return s.Fail(op, remote)
+}
+
+// cleanRemote převede cestu na serveru na absolutní tvar bez nadbytečných částí.
+func cleanRemote(remote string) string {
+	return path.Clean("/" + remote)
+}
+
+// notExistError je chyba chybějícího souboru. Stejně jako chyba SFTP klienta
+// odpovídá fs.ErrNotExist, takže ji isNotExist odliší od jiných chyb.
+type notExistError struct{ error }
+
+// Is hlásí shodu s fs.ErrNotExist.
+func (notExistError) Is(target error) bool { return target == fs.ErrNotExist }
+
+// memoryConn je spojení k MemoryServer.
+type memoryConn struct {
+	server *MemoryServer
+	closed bool
+}
+
+// check ověří, že je spojení otevřené a operace se nemá simulovaně nezdařit.
+func (c *memoryConn) check(op, remote string) error {
+	if c.closed {
+		return errors.New(i18n.T("spojení se serverem je ukončené"))
+	}
+	return c.server.fail(op, remote)
+}
+
+// Stor nahraje obsah readeru do souboru. Při chybě čtení zůstane na serveru
+// to, co se stihlo přenést.
+func (c *memoryConn) Stor(remote string, r io.Reader) error {
+	if err := c.check("stor", remote); err != nil {
+		return err
+	}
+	p := cleanRemote(remote)
+	data, readErr := io.ReadAll(r)
+
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirs[path.Dir(p)] {
+		return i18n.Errorf("adresář '%s' na serveru neexistuje", path.Dir(p))
+	}
+	s.files[p] = memoryFile{data: data, modTime: time.Now()}
+	return readErr
+}
+
+// Retr otevře soubor ke čtení.
+func (c *memoryConn) Retr(remote string) (io.ReadCloser, error) {
+	if err := c.check("retr", remote); err != nil {
+		return nil, err
+	}
+	data, ok := c.server.File(remote)
+	if !ok {
+		return nil, notExistError{i18n.Errorf("soubor '%s' na serveru neexistuje", remote)}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Delete smaže soubor.
+func (c *memoryConn) Delete(remote string) error {
+	if err := c.check("delete", remote); err != nil {
+		return err
+	}
+	p := cleanRemote(remote)
+
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[p]; !ok {
+		return notExistError{i18n.Errorf("soubor '%s' na serveru neexistuje", remote)}
+	}
+	delete(s.files, p)
+	return nil
+}
+
+// List vypíše soubory a podadresáře adresáře seřazené podle názvu.
+func (c *memoryConn) List(dir string) ([]Entry, error) {
+	if err := c.check("list", dir); err != nil {
+		return nil, err
+	}
+	d := cleanRemote(dir)
+
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirs[d] {
+		return nil, i18n.Errorf("adresář '%s' na serveru neexistuje", dir)
+	}
+	var entries []Entry
+	for p, f := range s.files {
+		if path.Dir(p) == d {
+			entries = append(entries, Entry{Name: path.Base(p), Size: int64(len(f.data)), ModTime: f.modTime})
+		}
+	}
+	for p := range s.dirs {
+		if p != "/" && path.Dir(p) == d {
+			entries = append(entries, Entry{Name: path.Base(p), Dir: true})
+		}
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Name, b.Name) })
+	return entries, nil
+}
+
+// MakeDir vytvoří adresář v existujícím nadřazeném adresáři.
+func (c *memoryConn) MakeDir(dir string) error {
+	if err := c.check("mkdir", dir); err != nil {
+		return err
+	}
+	d := cleanRemote(dir)
+
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.dirs[d]:
+		return i18n.Errorf("adresář '%s' na serveru již existuje", dir)
+	case !s.dirs[path.Dir(d)]:
+		return i18n.Errorf("adresář '%s' na serveru neexistuje", path.Dir(d))
+	}
+	s.dirs[d] = true
+	return nil
+}
+
+// Quit ukončí spojení.
+func (c *memoryConn) Quit() error {
+	c.closed = true
+	return nil
+}
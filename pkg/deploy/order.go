@@ -0,0 +1,68 @@
+package deploy
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Pořadí skupin souborů při nahrávání. Nižší hodnota se nahrává dříve.
+//
+// FTP cíle nejsou atomické, nový obsah je proto vidět hned po nahrání každého souboru.
+// Styly, skripty a obrázky jdou první, aby na ně nová HTML stránka nikdy neodkazovala
+// dříve, než jsou na serveru. Datové JSON soubory jdou až na konec.
+const (
+	rankAsset = iota // CSS, JavaScript, obrázky, fonty
+	rankOther        // Ostatní soubory
+	rankHTML         // HTML stránky
+	rankData         // Datové JSON soubory
+)
+
+// assetExtensions jsou přípony souborů, na které odkazují HTML stránky.
+var assetExtensions = map[string]bool{
+	".css": true, ".js": true, ".mjs": true, ".map": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".ico": true, ".avif": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+}
+
+// uploadRank určí skupinu souboru podle jeho přípony.
+func uploadRank(remote string) int {
+	ext := strings.ToLower(path.Ext(remote))
+	switch {
+	case assetExtensions[ext]:
+		return rankAsset
+	case ext == ".html" || ext == ".htm":
+		return rankHTML
+	case ext == ".json":
+		return rankData
+	default:
+		return rankOther
+	}
+}
+
+// orderFiles vrací soubory seřazené pro nahrávání: nejprve podle skupiny,
+// v rámci skupiny podle vzdálené cesty. Pořadí je tak při každém nasazení stejné.
+func orderFiles(files []deployFile) []deployFile {
+	ordered := make([]deployFile, len(files))
+	copy(ordered, files)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri, rj := uploadRank(ordered[i].Remote), uploadRank(ordered[j].Remote)
+		if ri != rj {
+			return ri < rj
+		}
+		return ordered[i].Remote < ordered[j].Remote
+	})
+	return ordered
+}
+
+// groupByRank rozdělí seřazené soubory na po sobě jdoucí skupiny se stejným pořadím.
+func groupByRank(files []deployFile) [][]deployFile {
+	var groups [][]deployFile
+	for i, f := range files {
+		if i == 0 || uploadRank(f.Remote) != uploadRank(files[i-1].Remote) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], f)
+	}
+	return groups
+}
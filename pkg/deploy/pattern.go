@@ -0,0 +1,52 @@
+package deploy
+
+import (
+	"regexp"
+	"strings"
+)
+
+// matchPattern porovná cestu se vzorem ve stylu gitignore:
+//   - "*" odpovídá libovolné části názvu bez lomítka,
+//   - "?" odpovídá jednomu znaku kromě lomítka,
+//   - "**" odpovídá libovolnému počtu adresářů (i žádnému).
+//
+// Vzor končící lomítkem (např. "data/") odpovídá všemu uvnitř adresáře.
+func matchPattern(pattern, name string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	re, err := regexp.Compile(patternToRegexp(pattern))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(strings.TrimPrefix(name, "/"))
+}
+
+// patternToRegexp převede vzor na ekvivalentní regulární výraz.
+func patternToRegexp(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	pattern = strings.TrimPrefix(pattern, "/")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			i++
+			// "**/" odpovídá i žádnému adresáři, takže "a/**/b" pokryje i "a/b".
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				i++
+				b.WriteString("(?:.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package deploy
+
+import (
+	"os"
+	"path"
+	"strconv"
+
+	"hugo72/internal/i18n"
+)
+
+// modeFor vrací práva podle prvního pravidla, kterému soubor odpovídá.
+func modeFor(rules []PermissionRule, remote string) (string, bool) {
+	for _, rule := range rules {
+		if matchPattern(rule.Pattern, remote) {
+			return rule.Mode, true
+		}
+	}
+	return "", false
+}
+
+// pendingPermissions vrací nahrané soubory, kterým některé pravidlo přiřazuje práva.
+func pendingPermissions(rules []PermissionRule, result *deployResult) []string {
+	var pending []string
+	for _, remote := range result.filesWithStatus(statusUploaded) {
+		if _, ok := modeFor(rules, remote); ok {
+			pending = append(pending, remote)
+		}
+	}
+	return pending
+}
+
+// applyPermissions nastaví práva nahraným souborům podle pravidel.
+// Používá samostatné řídicí spojení, protože knihovna ftp příkaz SITE neumí.
+// Pokud server SITE CHMOD nepodporuje, zaznamená se jediná chyba a další soubory se přeskočí.
+func applyPermissions(target Target, th *throttle, rules []PermissionRule, result *deployResult) {
+	pending := pendingPermissions(rules, result)
+	if len(pending) == 0 {
+		return
+	}
+
+	conn, err := dialRaw(target, th, result.logger)
+	if err != nil {
+		result.addError(i18n.Errorf("práva souborů nelze nastavit: %w", err))
+		return
+	}
+	defer conn.Close()
+
+	for _, remote := range pending {
+		mode, _ := modeFor(rules, remote)
+		code, msg, err := conn.cmd(-1, "SITE CHMOD %s %s", mode, path.Join(target.RemoteDir, remote))
+		if err != nil {
+			result.addError(i18n.Errorf("chyba při nastavování práv souboru '%s': %w", remote, err))
+			return
+		}
+		switch {
+		case code >= 200 && code < 300:
+			result.logger.Info("Souboru nastavena práva.", "file", remote, "mode", mode)
+		case code == 500 || code == 502 || code == 504:
+			result.addError(i18n.Errorf("server nepodporuje SITE CHMOD, práva souborů nebyla nastavena (%d %s)", code, msg))
+			return
+		default:
+			result.addError(i18n.Errorf("server odmítl nastavit práva souboru '%s': %d %s", remote, code, msg))
+		}
+	}
+}
+
+// applySFTPPermissions nastaví práva nahraným souborům podle pravidel přes
+// spojení SFTP, které změnu práv umí samo.
+func applySFTPPermissions(conn *sftpTransport, remoteDir string, rules []PermissionRule, result *deployResult) {
+	for _, remote := range pendingPermissions(rules, result) {
+		mode, _ := modeFor(rules, remote)
+		perm, err := strconv.ParseUint(mode, 8, 32)
+		if err == nil {
+			err = conn.sftp.Chmod(path.Join(remoteDir, remote), os.FileMode(perm))
+		}
+		if err != nil {
+			result.addError(i18n.Errorf("chyba při nastavování práv souboru '%s': %w", remote, err))
+			continue
+		}
+		result.logger.Info("Souboru nastavena práva.", "file", remote, "mode", mode)
+	}
+}
@@ -0,0 +1,88 @@
+package deploy
+
+import (
+	"os"
+	"path"
+
+	"hugo72/internal/logging"
+)
+
+// Plan popisuje, co by nasazení udělalo. Vypisuje se při zkušebním běhu
+// (--dry-run) místo nasazení, k serveru se přitom nepřipojuje.
+type Plan struct {
+	DryRun  bool         `json:"dryRun"`
+	Targets []TargetPlan `json:"targets"`
+}
+
+// TargetPlan je plán nasazení na jeden cíl.
+type TargetPlan struct {
+	Name            string        `json:"name"`
+	Target          string        `json:"target"`
+	TLS             string        `json:"tls,omitempty"`
+	MaintenancePage string        `json:"maintenancePage,omitempty"`
+	Files           []PlannedFile `json:"files"`
+	TotalSize       int64         `json:"totalSize"`
+}
+
+// PlannedFile je soubor, který by se nahrál, v pořadí nahrávání.
+type PlannedFile struct {
+	Local  string `json:"local"`
+	Remote string `json:"remote"`
+	Size   int64  `json:"size"`
+	Mode   string `json:"mode,omitempty"` // Práva, která by se souboru nastavila
+}
+
+// planDeploy sestaví a vypíše plán nasazení souborů na zadané cíle. Pořadí souborů
+// odpovídá skutečnému nasazení včetně přesunu index.html na konec při stránce údržby.
+func planDeploy(config *Config, targetNames []string, targets []Target, files []deployFile) Plan {
+	ordered := orderFiles(files)
+	maintenance := ""
+	if config.Phase3.MaintenancePage != "" {
+		if rest, index := splitIndex(ordered); index != nil {
+			ordered = append(rest, *index)
+			maintenance = config.Phase3.MaintenancePage
+		}
+	}
+
+	plan := Plan{DryRun: true, Targets: []TargetPlan{}}
+	for i, name := range targetNames {
+		target := targets[i]
+		tp := TargetPlan{
+			Name:            name,
+			Target:          target.FtpHost + target.RemoteDir,
+			TLS:             target.TLS,
+			MaintenancePage: maintenance,
+			Files:           []PlannedFile{},
+		}
+		for _, f := range ordered {
+			pf := PlannedFile{Local: f.Local, Remote: path.Join(target.RemoteDir, f.Remote)}
+			if info, err := os.Stat(f.Local); err == nil {
+				pf.Size = info.Size()
+			}
+			pf.Mode, _ = modeFor(config.Phase3.Permissions, f.Remote)
+			tp.Files = append(tp.Files, pf)
+			tp.TotalSize += pf.Size
+		}
+		plan.Targets = append(plan.Targets, tp)
+	}
+	return plan
+}
+
+// logPlan vypíše plán nasazení do logu, u každého cíle všechny soubory v pořadí nahrávání.
+func logPlan(plan Plan) {
+	logger := logging.Phase("deploy")
+	for _, tp := range plan.Targets {
+		tl := logger.With("target", tp.Name)
+		tl.Info("Zkušební běh, na server se nic nenahraje.", "destination", tp.Target, "files", len(tp.Files), "bytes", tp.TotalSize)
+		if tp.MaintenancePage != "" {
+			tl.Info("Během nasazení by se zobrazila stránka údržby.", "file", tp.MaintenancePage)
+		}
+		for _, f := range tp.Files {
+			if f.Mode != "" {
+				tl.Info("Soubor by se nahrál.", "file", f.Remote, "size", f.Size, "mode", f.Mode)
+			} else {
+				tl.Info("Soubor by se nahrál.", "file", f.Remote, "size", f.Size)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package deploy
+
+import (
+	"fmt"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/logging"
+)
+
+// Chování nasazení při selhání nahrání souboru.
+const (
+	policyContinue   = "continue"    // Pokračovat a na konci vypsat souhrn chyb (výchozí)
+	policyAbort      = "abort"       // Přerušit nasazení při první chybě
+	policyAbortAfter = "abort-after" // Přerušit nasazení po dosažení maxErrors chyb
+)
+
+// failurePolicy určuje, kdy se má nasazení kvůli chybám souborů přerušit.
+// Nasazení s chybou vždy končí nenulovým návratovým kódem, politika rozhoduje
+// jen o tom, zda se po chybě pokračuje s dalšími soubory.
+type failurePolicy struct {
+	Mode      string // Jedna z hodnot policy*
+	MaxErrors int    // Počet chyb, po kterém se nasazení přeruší (jen pro abort-after)
+}
+
+// newFailurePolicy ověří nastavení politiky z konfigurace.
+func newFailurePolicy(mode string, maxErrors int) (failurePolicy, error) {
+	switch mode {
+	case "", policyContinue:
+		return failurePolicy{Mode: policyContinue}, nil
+	case policyAbort:
+		return failurePolicy{Mode: policyAbort, MaxErrors: 1}, nil
+	case policyAbortAfter:
+		if maxErrors < 1 {
+			return failurePolicy{}, i18n.Errorf("failurePolicy \"abort-after\" vyžaduje maxErrors alespoň 1")
+		}
+		return failurePolicy{Mode: policyAbortAfter, MaxErrors: maxErrors}, nil
+	default:
+		return failurePolicy{}, i18n.Errorf("neznámá failurePolicy '%s', povoleno je \"continue\", \"abort\" nebo \"abort-after\"", mode)
+	}
+}
+
+// exceeded vrací true, pokud počet chyb dosáhl hranice pro přerušení nasazení.
+func (p failurePolicy) exceeded(failed int) bool {
+	return p.Mode != policyContinue && failed >= p.MaxErrors
+}
+
+// logSummary vypíše na konci nasazení souhrn neúspěšných a přeskočených souborů.
+func logSummary(result *deployResult) {
+	failed := result.filesWithStatus(statusFailed)
+	if len(result.Errors) == 0 {
+		result.logger.Info("Nasazení proběhlo bez chyb.", "destination", result.Target)
+		return
+	}
+
+	result.logger.Error("Nasazení skončilo s chybami.", "destination", result.Target, "errors", len(result.Errors))
+	for _, f := range result.Files {
+		if f.Status == statusFailed {
+			result.logger.Error("Soubor se nepodařilo nahrát.", "file", f.Path, "error", f.Error)
+		}
+	}
+	if result.Aborted {
+		result.logger.Warn("Nasazení bylo přerušeno.", "skipped", len(result.filesWithStatus(statusSkipped)))
+	}
+	// Chyby, které se netýkají konkrétního souboru (např. selhání připojení).
+	if len(failed) == 0 {
+		for _, e := range result.Errors {
+			result.logger.Error("Chyba nasazení.", "error", e)
+		}
+	}
+}
+
+// logTargetsSummary vypíše po nasazení na více cílů přehled výsledků jednotlivých cílů.
+func logTargetsSummary(results []*deployResult) {
+	logger := logging.Phase("deploy")
+	for _, r := range results {
+		status := "ok"
+		if len(r.Errors) > 0 {
+			status = i18n.T("chyba")
+		}
+		logger.Info("Souhrn nasazení.", "target", r.Name, "status", status,
+			"uploaded", len(r.filesWithStatus(statusUploaded)), "failed", len(r.filesWithStatus(statusFailed)),
+			"seconds", fmt.Sprintf("%.1f", r.Duration.Seconds()))
+	}
+}
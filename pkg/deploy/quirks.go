@@ -0,0 +1,61 @@
+package deploy
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// quirkControlHost je hodnota pasvHost, která datová spojení směruje na adresu řídicího spojení.
+const quirkControlHost = "control"
+
+// quirkDialOptions vrací volby knihovny ftp odpovídající obejitím nastaveným pro cíl.
+// Datová spojení se při šifrování zabalí do TLS zde, protože s vlastní
+// funkcí pro připojení to knihovna sama neudělá.
+func quirkDialOptions(target Target, tc *tls.Config) []ftp.DialOption {
+	q := target.Quirks
+	options := []ftp.DialOption{
+		ftp.DialWithDisabledEPSV(q.DisableEPSV),
+		ftp.DialWithDisabledMLSD(q.DisableMLSD),
+	}
+	if q.PasvHost == "" {
+		return options
+	}
+
+	dataHost := q.PasvHost
+	if dataHost == quirkControlHost {
+		dataHost = target.FtpHost
+		if h, _, err := net.SplitHostPort(target.FtpHost); err == nil {
+			dataHost = h
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	control := true
+	return append(options, ftp.DialWithDialFunc(func(network, address string) (net.Conn, error) {
+		// První spojení je řídicí, následující jsou datová.
+		if control {
+			control = false
+			if target.TLS == tlsImplicit {
+				return tls.DialWithDialer(dialer, network, address, tc)
+			}
+			return dialer.Dial(network, address)
+		}
+
+		// Server za NATem hlásí v odpovědi PASV svou vnitřní adresu, použije se jen port.
+		_, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := dialer.Dial(network, net.JoinHostPort(dataHost, port))
+		if err != nil {
+			return nil, err
+		}
+		if tc != nil {
+			return tls.Client(conn, tc), nil
+		}
+		return conn, nil
+	}))
+}
@@ -0,0 +1,84 @@
+package deploy
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// rawConn je samostatné řídicí spojení k FTP serveru pro příkazy,
+// které knihovna ftp nenabízí (např. SITE CHMOD). Datová spojení nepodporuje.
+type rawConn struct {
+	conn     *textproto.Conn
+	throttle *throttle
+}
+
+// dialRaw otevře řídicí spojení k serveru cíle a přihlásí se.
+// Respektuje nastavení šifrování cíle stejně jako connectToFtp.
+func dialRaw(target Target, th *throttle, logger *slog.Logger) (*rawConn, error) {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+
+	th.wait()
+	var netConn net.Conn
+	var err error
+	var tc *tls.Config
+	if target.TLS != tlsNone {
+		if tc, err = tlsConfig(target, logger); err != nil {
+			return nil, err
+		}
+	}
+	if target.TLS == tlsImplicit {
+		netConn, err = tls.DialWithDialer(dialer, "tcp", target.FtpHost, tc)
+	} else {
+		netConn, err = dialer.Dial("tcp", target.FtpHost)
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba při připojování k FTP serveru: %w", err)
+	}
+
+	r := &rawConn{conn: textproto.NewConn(netConn), throttle: th}
+	if _, _, err := r.conn.ReadResponse(220); err != nil {
+		r.Close()
+		return nil, i18n.Errorf("neočekávaná odpověď FTP serveru: %w", err)
+	}
+
+	if target.TLS == tlsExplicit {
+		if _, _, err := r.cmd(234, "AUTH TLS"); err != nil {
+			r.Close()
+			return nil, i18n.Errorf("server odmítl šifrované spojení: %w", err)
+		}
+		r.conn = textproto.NewConn(tls.Client(netConn, tc))
+	}
+
+	code, _, err := r.cmd(-1, "USER %s", target.FtpUser)
+	if err == nil && code == 331 {
+		_, _, err = r.cmd(230, "PASS %s", target.FtpPassword)
+	} else if err == nil && code != 230 {
+		err = i18n.Errorf("neočekávaná odpověď %d na příkaz USER", code)
+	}
+	if err != nil {
+		r.Close()
+		return nil, i18n.Errorf("chyba při přihlášení na FTP server: %w", err)
+	}
+	return r, nil
+}
+
+// cmd odešle příkaz a přečte odpověď. Hodnota expect -1 přijme jakýkoli kód odpovědi,
+// jinak musí kód začínat stejnými číslicemi jako expect.
+func (r *rawConn) cmd(expect int, format string, args ...any) (int, string, error) {
+	r.throttle.wait()
+	if _, err := r.conn.Cmd(format, args...); err != nil {
+		return 0, "", err
+	}
+	return r.conn.ReadResponse(expect)
+}
+
+// Close ukončí spojení se serverem.
+func (r *rawConn) Close() error {
+	r.conn.Cmd("QUIT")
+	return r.conn.Close()
+}
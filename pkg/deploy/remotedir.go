@@ -0,0 +1,60 @@
+package deploy
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// remoteDirData jsou proměnné dostupné v šabloně remoteDir, např.
+//
+//	"/releases/{{.Date}}_{{.Commit}}"
+//	"/akce/{{.Env.EVENT}}"
+type remoteDirData struct {
+	Date   string            // Datum nasazení ve tvaru 2006-01-02
+	Env    map[string]string // Proměnné prostředí
+	commit string            // Plný hash commitu, ze kterého se nasazuje
+}
+
+// Commit vrací zkrácený hash commitu. Pokud commit nelze zjistit, šablona selže.
+func (d remoteDirData) Commit() (string, error) {
+	if d.commit == "" {
+		return "", errors.New(i18n.T("commit nelze zjistit, nasazuje se mimo git repozitář"))
+	}
+	return d.commit[:min(len(d.commit), 7)], nil
+}
+
+// expandRemoteDir dosadí do šablony remoteDir aktuální datum, commit a proměnné prostředí.
+// Adresář bez šablony vrací beze změny. Chybějící proměnná prostředí je chyba,
+// aby se nenasadilo do nečekaného adresáře.
+func expandRemoteDir(dir, commit string, now time.Time) (string, error) {
+	if !strings.Contains(dir, "{{") {
+		return dir, nil
+	}
+
+	tmpl, err := template.New("remoteDir").Option("missingkey=error").Parse(dir)
+	if err != nil {
+		return "", i18n.Errorf("neplatná šablona remoteDir '%s': %w", dir, err)
+	}
+
+	data := remoteDirData{
+		Date:   now.Format("2006-01-02"),
+		Env:    map[string]string{},
+		commit: commit,
+	}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			data.Env[k] = v
+		}
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", i18n.Errorf("chyba při vyhodnocení šablony remoteDir '%s': %w", dir, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,222 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"hugo72/internal/i18n"
+	"hugo72/internal/problems"
+)
+
+// defaultReportFile je výchozí cesta ke strojově čitelnému reportu nasazení.
+const defaultReportFile = "deploy-report.json"
+
+// Stavy, ve kterých může skončit zpracování jednoho souboru.
+const (
+	statusUploaded = "uploaded" // Soubor byl nahrán na server
+	statusSkipped  = "skipped"  // Soubor nebyl nahrán (nebylo potřeba nebo bylo nasazení přerušeno)
+	statusDeleted  = "deleted"  // Soubor byl smazán ze serveru
+	statusFailed   = "failed"   // Zpracování souboru selhalo
+)
+
+// deployResult shrnuje průběh jednoho nasazení.
+// Slouží jako podklad pro report, webhook i výsledný návratový kód.
+type deployResult struct {
+	mu        sync.Mutex      // Chrání Files a Errors při souběžném nahrávání
+	logger    *slog.Logger    // Log cíle nasazení
+	ctx       context.Context // Zrušení kontextu (např. signálem) nasazení přeruší
+	Name      string          // Název cíle nasazení z konfigurace
+	Target    string          // Identifikace cíle nasazení (server a vzdálený adresář)
+	Start     time.Time       // Okamžik zahájení nasazení
+	Duration  time.Duration   // Celková doba nasazení
+	Files     []fileResult    // Výsledky zpracování jednotlivých souborů
+	Errors    []string        // Chyby, ke kterým během nasazení došlo
+	Aborted   bool            // Nasazení bylo přerušeno dříve, než se zpracovaly všechny soubory
+	Cancelled bool            // Nasazení bylo zrušeno signálem
+	failed    int             // Počet souborů, jejichž nahrání selhalo
+}
+
+// fileResult popisuje výsledek zpracování jednoho souboru.
+type fileResult struct {
+	Path     string        // Cesta k souboru
+	Status   string        // Jeden ze stavů status*
+	Size     int64         // Velikost souboru v bajtech
+	Duration time.Duration // Doba přenosu včetně opakování
+	Retries  int           // Počet opakovaných pokusů
+	Error    string        // Popis chyby u neúspěšného souboru
+}
+
+// add zaznamená výsledek zpracování souboru. Chyba souboru se přidá i mezi chyby nasazení.
+func (r *deployResult) add(fr fileResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Files = append(r.Files, fr)
+	if fr.Status == statusFailed {
+		r.Errors = append(r.Errors, fr.Error)
+		r.failed++
+		problems.Add("deploy", r.Name+": "+fr.Path, errors.New(fr.Error))
+	}
+}
+
+// addError zaznamená chybu, která se netýká nahrání konkrétního souboru, a vypíše ji do logu.
+func (r *deployResult) addError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logger.Error("Chyba při nasazení.", "error", err)
+	r.Errors = append(r.Errors, err.Error())
+	problems.Add("deploy", r.Name, err)
+}
+
+// abort označí nasazení jako přerušené, pokud počet chyb překročil hranici politiky
+// nebo bylo nasazení zrušeno. Vrací true, pokud je nasazení přerušeno.
+func (r *deployResult) abort(policy failurePolicy) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.Cancelled && r.ctx.Err() != nil {
+		r.Cancelled = true
+		r.Aborted = true
+		r.Errors = append(r.Errors, "nasazení bylo zrušeno")
+	}
+	if !r.Aborted && policy.exceeded(r.failed) {
+		r.Aborted = true
+	}
+	return r.Aborted
+}
+
+// filesWithStatus vrací cesty souborů, které skončily v zadaném stavu.
+func (r *deployResult) filesWithStatus(status string) []string {
+	var files []string
+	for _, f := range r.Files {
+		if f.Status == status {
+			files = append(files, f.Path)
+		}
+	}
+	return files
+}
+
+// Report je obsah souboru deploy-report.json.
+// Obsahuje výsledky pro každý cíl nasazení a celkovou dobu běhu. Cíle se nasazují
+// souběžně, celková doba proto odpovídá nejdelšímu nasazení, ne součtu.
+type Report struct {
+	GeneratedAt  time.Time      `json:"generatedAt"`
+	RunID        string         `json:"runId,omitempty"`  // Identifikátor běhu, který nasazení provedl
+	Commit       string         `json:"commit,omitempty"` // Commit, ze kterého nasazovaný obsah pochází
+	TotalSeconds float64        `json:"totalSeconds"`
+	Targets      []TargetReport `json:"targets"`
+}
+
+// TargetReport je část reportu věnovaná jednomu cíli nasazení.
+type TargetReport struct {
+	Name            string       `json:"name"`
+	Target          string       `json:"target"`
+	Success         bool         `json:"success"`
+	Aborted         bool         `json:"aborted"`
+	Cancelled       bool         `json:"cancelled"`
+	StartedAt       time.Time    `json:"startedAt"`
+	DurationSeconds float64      `json:"durationSeconds"`
+	Summary         Summary      `json:"summary"`
+	Files           []FileReport `json:"files"`
+	Errors          []string     `json:"errors"`
+}
+
+// Summary obsahuje souhrnné počty souborů podle stavu.
+type Summary struct {
+	Uploaded      int   `json:"uploaded"`
+	Skipped       int   `json:"skipped"`
+	Deleted       int   `json:"deleted"`
+	Failed        int   `json:"failed"`
+	BytesUploaded int64 `json:"bytesUploaded"`
+	Retries       int   `json:"retries"`
+}
+
+// FileReport je záznam jednoho souboru v reportu.
+type FileReport struct {
+	Path            string  `json:"path"`
+	Status          string  `json:"status"`
+	Size            int64   `json:"size"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Retries         int     `json:"retries"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// newTargetReport převede výsledek nasazení na záznam reportu a spočítá souhrn.
+func newTargetReport(result *deployResult) TargetReport {
+	tr := TargetReport{
+		Name:            result.Name,
+		Target:          result.Target,
+		Success:         len(result.Errors) == 0,
+		Aborted:         result.Aborted,
+		Cancelled:       result.Cancelled,
+		StartedAt:       result.Start,
+		DurationSeconds: result.Duration.Seconds(),
+		Files:           []FileReport{},
+		Errors:          nonNil(result.Errors),
+	}
+	for _, f := range result.Files {
+		switch f.Status {
+		case statusUploaded:
+			tr.Summary.Uploaded++
+			tr.Summary.BytesUploaded += f.Size
+		case statusSkipped:
+			tr.Summary.Skipped++
+		case statusDeleted:
+			tr.Summary.Deleted++
+		case statusFailed:
+			tr.Summary.Failed++
+		}
+		tr.Summary.Retries += f.Retries
+		tr.Files = append(tr.Files, FileReport{
+			Path:            f.Path,
+			Status:          f.Status,
+			Size:            f.Size,
+			DurationSeconds: f.Duration.Seconds(),
+			Retries:         f.Retries,
+			Error:           f.Error,
+		})
+	}
+	return tr
+}
+
+// newReport sestaví report nasazení na jeden nebo více cílů.
+func newReport(results ...*deployResult) Report {
+	report := Report{
+		GeneratedAt: time.Now(),
+		Targets:     []TargetReport{},
+	}
+	var first, last time.Time
+	for _, result := range results {
+		report.Targets = append(report.Targets, newTargetReport(result))
+		if end := result.Start.Add(result.Duration); last.Before(end) {
+			last = end
+		}
+		if first.IsZero() || result.Start.Before(first) {
+			first = result.Start
+		}
+	}
+	report.TotalSeconds = last.Sub(first).Seconds()
+	return report
+}
+
+// writeReport zapíše report nasazení do souboru ve formátu JSON.
+func writeReport(filePath string, report Report) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return i18n.Errorf("chyba při vytváření reportu '%s': %w", filePath, err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return i18n.Errorf("chyba při zápisu reportu '%s': %w", filePath, err)
+	}
+	return nil
+}
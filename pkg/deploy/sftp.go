@@ -0,0 +1,325 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"hugo72/internal/i18n"
+)
+
+// protocolSFTP je hodnota protocol cíle, který se nasazuje přes SFTP.
+const protocolSFTP = "sftp"
+
+// defaultSFTPPort je port SSH serveru, pokud ho ftpHost neuvádí.
+const defaultSFTPPort = "22"
+
+// knownHostsMu brání tomu, aby souběžná spojení při prvním připojení
+// (--accept-new) zapsala klíč serveru do known_hosts vícekrát.
+var knownHostsMu sync.Mutex
+
+// sftpDialer vrací Dialer, který se k cíli připojí přes SFTP. Klíč serveru
+// se ověří podle hostKey cíle, jinak podle souboru known_hosts. S acceptNew
+// se klíč serveru, který v known_hosts ještě není, při prvním připojení do
+// souboru uloží (jako ssh -o StrictHostKeyChecking=accept-new); změněný klíč
+// se nepřijme nikdy.
+func sftpDialer(acceptNew bool, logger *slog.Logger) Dialer {
+	return func(ctx context.Context, target Target) (Transport, error) {
+		return dialSFTP(ctx, target, acceptNew, logger)
+	}
+}
+
+// dialSFTP se připojí k SSH serveru cíle, ověří jeho klíč, přihlásí se
+// a otevře relaci SFTP.
+func dialSFTP(ctx context.Context, target Target, acceptNew bool, logger *slog.Logger) (Transport, error) {
+	address := sftpAddress(target.FtpHost)
+	hostKeyCallback, algorithms, err := hostKeyCheck(target, acceptNew, logger)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := sshAuth(target)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig := &ssh.ClientConfig{
+		User:              target.FtpUser,
+		Auth:              auth,
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: algorithms,
+		Timeout:           5 * time.Second,
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	netConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při připojování k SFTP serveru: %w", err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, address, clientConfig)
+	if err != nil {
+		netConn.Close()
+		return nil, i18n.Errorf("chyba při přihlášení na SFTP server: %w", err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	sftpClient, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, i18n.Errorf("server %s nenabízí SFTP: %w", target.FtpHost, err)
+	}
+
+	logger.Info("Úspěšně připojeno k SFTP serveru.", "host", target.FtpHost)
+	return &sftpTransport{ssh: sshClient, sftp: sftpClient}, nil
+}
+
+// sftpAddress doplní k adrese serveru výchozí port SSH.
+func sftpAddress(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, defaultSFTPPort)
+}
+
+// sshAuth vrací způsoby přihlášení k SSH serveru: klíčem z keyFile a heslem ftpPassword.
+// Je-li klíč zašifrovaný, ftpPassword je jeho heslo a heslem se nepřihlašuje.
+func sshAuth(target Target) ([]ssh.AuthMethod, error) {
+	if target.KeyFile == "" {
+		return []ssh.AuthMethod{ssh.Password(target.FtpPassword)}, nil
+	}
+
+	pem, err := os.ReadFile(target.KeyFile)
+	if err != nil {
+		return nil, i18n.Errorf("chyba při čtení klíče '%s': %w", target.KeyFile, err)
+	}
+	signer, err := ssh.ParsePrivateKey(pem)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(pem, []byte(target.FtpPassword))
+		if err != nil {
+			return nil, i18n.Errorf("klíč '%s' nelze odemknout heslem ftpPassword: %w", target.KeyFile, err)
+		}
+		return []ssh.AuthMethod{ssh.PublicKeys(signer)}, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("chyba v klíči '%s': %w", target.KeyFile, err)
+	}
+
+	methods := []ssh.AuthMethod{ssh.PublicKeys(signer)}
+	if target.FtpPassword != "" {
+		methods = append(methods, ssh.Password(target.FtpPassword))
+	}
+	return methods, nil
+}
+
+// hostKeyCheck vrací funkci, která ověří klíč SSH serveru, a algoritmy klíčů,
+// které má server nabídnout. Algoritmy se omezí na typy očekávaných klíčů, aby
+// server nepředložil klíč jiného typu, než jaký je uložený, a ten se mylně
+// nepovažoval za změněný.
+func hostKeyCheck(target Target, acceptNew bool, logger *slog.Logger) (ssh.HostKeyCallback, []string, error) {
+	if target.HostKey != "" {
+		return pinnedHostKey(target)
+	}
+
+	file := target.KnownHosts
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, i18n.Errorf("nelze určit domovský adresář pro known_hosts, nastavte knownHosts nebo hostKey: %w", err)
+		}
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		if !acceptNew {
+			return nil, nil, i18n.Errorf("soubor known_hosts '%s' neexistuje; ověřte otisk klíče serveru a připojte se s --accept-new, nebo nastavte hostKey", file)
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+			return nil, nil, i18n.Errorf("chyba při vytváření adresáře '%s': %w", filepath.Dir(file), err)
+		}
+		if err := os.WriteFile(file, nil, 0o600); err != nil {
+			return nil, nil, i18n.Errorf("chyba při vytváření souboru known_hosts '%s': %w", file, err)
+		}
+	}
+
+	known, err := knownhosts.New(file)
+	if err != nil {
+		return nil, nil, i18n.Errorf("chyba při čtení known_hosts '%s': %w", file, err)
+	}
+	address := sftpAddress(target.FtpHost)
+
+	callback := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := known(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) > 0 {
+			return i18n.Errorf("klíč serveru %s se změnil (%s %s), neodpovídá known_hosts '%s'; pokud změnu nečekáte, může jít o podvržený server",
+				target.FtpHost, key.Type(), ssh.FingerprintSHA256(key), file)
+		}
+		if !acceptNew {
+			return i18n.Errorf("klíč serveru %s (%s %s) není v known_hosts '%s'; ověřte otisk a připojte se s --accept-new, nebo nastavte hostKey",
+				target.FtpHost, key.Type(), ssh.FingerprintSHA256(key), file)
+		}
+		return acceptHostKey(file, hostname, remote, key, logger)
+	}
+	return callback, knownAlgorithms(known, address), nil
+}
+
+// acceptHostKey uloží klíč serveru, který v known_hosts ještě není, na konec
+// souboru. Souběžné spojení ho mohlo mezitím uložit, proto se soubor před
+// zápisem načte znovu.
+func acceptHostKey(file, hostname string, remote net.Addr, key ssh.PublicKey, logger *slog.Logger) error {
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	if known, err := knownhosts.New(file); err == nil {
+		var keyErr *knownhosts.KeyError
+		if err := known(hostname, remote, key); err == nil || !errors.As(err, &keyErr) || len(keyErr.Want) > 0 {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return i18n.Errorf("chyba při zápisu do known_hosts '%s': %w", file, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key) + "\n"); err != nil {
+		return i18n.Errorf("chyba při zápisu do known_hosts '%s': %w", file, err)
+	}
+	logger.Warn("Klíč serveru uložen do known_hosts při prvním připojení (--accept-new).",
+		"host", hostname, "key", key.Type()+" "+ssh.FingerprintSHA256(key), "file", file)
+	return nil
+}
+
+// pinnedHostKey vrací ověření klíče serveru podle hostKey cíle: otisku
+// "SHA256:..." nebo celého veřejného klíče.
+func pinnedHostKey(target Target) (ssh.HostKeyCallback, []string, error) {
+	mismatch := func(key ssh.PublicKey) error {
+		return i18n.Errorf("klíč serveru %s (%s %s) neodpovídá nastavenému hostKey; pokud změnu nečekáte, může jít o podvržený server",
+			target.FtpHost, key.Type(), ssh.FingerprintSHA256(key))
+	}
+
+	if strings.HasPrefix(target.HostKey, "SHA256:") {
+		return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if ssh.FingerprintSHA256(key) != target.HostKey {
+				return mismatch(key)
+			}
+			return nil
+		}, nil, nil
+	}
+
+	pinned, _, _, _, err := ssh.ParseAuthorizedKey([]byte(target.HostKey))
+	if err != nil {
+		return nil, nil, i18n.Errorf("neplatný hostKey '%s': %w", target.HostKey, err)
+	}
+	return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+		if !bytes.Equal(key.Marshal(), pinned.Marshal()) {
+			return mismatch(key)
+		}
+		return nil
+	}, keyAlgorithms(pinned.Type()), nil
+}
+
+// knownAlgorithms vrací algoritmy klíčů, které jsou pro server uložené
+// v known_hosts, nebo nil, pokud server v souboru ještě není. Uložené klíče
+// vrací chyba ověření klíče, který v souboru být nemůže.
+func knownAlgorithms(known ssh.HostKeyCallback, address string) []string {
+	remote, err := net.ResolveTCPAddr("tcp", address)
+	if err != nil {
+		remote = &net.TCPAddr{}
+	}
+	var keyErr *knownhosts.KeyError
+	if !errors.As(known(address, remote, unknownKey{}), &keyErr) {
+		return nil
+	}
+	var algorithms []string
+	for _, k := range keyErr.Want {
+		algorithms = append(algorithms, keyAlgorithms(k.Key.Type())...)
+	}
+	return algorithms
+}
+
+// keyAlgorithms vrací algoritmy, kterými server prokazuje klíč daného typu.
+// Klíč RSA se podepisuje i novějšími algoritmy SHA-2.
+func keyAlgorithms(keyType string) []string {
+	if keyType == ssh.KeyAlgoRSA {
+		return []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	return []string{keyType}
+}
+
+// unknownKey je klíč, který žádný server nemá; slouží k výpisu uložených klíčů.
+type unknownKey struct{}
+
+func (unknownKey) Type() string                        { return "hugo72-none" }
+func (unknownKey) Marshal() []byte                     { return []byte("hugo72-none") }
+func (unknownKey) Verify([]byte, *ssh.Signature) error { return errors.New("hugo72-none") }
+
+// sftpTransport je spojení se serverem přes SFTP.
+type sftpTransport struct {
+	ssh  *ssh.Client
+	sftp *sftp.Client
+}
+
+// Stor nahraje obsah readeru do souboru na serveru.
+func (t *sftpTransport) Stor(remote string, r io.Reader) error {
+	f, err := t.sftp.Create(remote)
+	if err != nil {
+		return err
+	}
+	if _, err := f.ReadFrom(r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Retr otevře soubor na serveru ke čtení.
+func (t *sftpTransport) Retr(remote string) (io.ReadCloser, error) {
+	return t.sftp.Open(remote)
+}
+
+// Delete smaže soubor na serveru.
+func (t *sftpTransport) Delete(remote string) error {
+	return t.sftp.Remove(remote)
+}
+
+// List vypíše obsah adresáře na serveru. Odkazy a jiné zvláštní položky vynechá.
+func (t *sftpTransport) List(dir string) ([]Entry, error) {
+	infos, err := t.sftp.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, info := range infos {
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			continue
+		}
+		entries = append(entries, Entry{Name: info.Name(), Size: info.Size(), Dir: info.IsDir(), ModTime: info.ModTime()})
+	}
+	return entries, nil
+}
+
+// MakeDir vytvoří adresář na serveru.
+func (t *sftpTransport) MakeDir(dir string) error {
+	return t.sftp.Mkdir(dir)
+}
+
+// Quit ukončí spojení SSH a s ním i relaci SFTP. Relace se nezavírá jako
+// první, protože by čekala, až ji zavře server.
+func (t *sftpTransport) Quit() error {
+	err := t.ssh.Close()
+	t.sftp.Close()
+	return err
+}
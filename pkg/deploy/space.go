@@ -0,0 +1,111 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+
+	"hugo72/internal/i18n"
+)
+
+// numberRe najde v odpovědi serveru první celé číslo.
+var numberRe = regexp.MustCompile(`\d+`)
+
+// spaceCheckEnabled vrací true, pokud má cíl nastavený způsob zjištění volného místa.
+func spaceCheckEnabled(target Target) bool {
+	return target.SpaceProbe != "" || target.QuotaBytes > 0
+}
+
+// checkFreeSpace ověří, že se nasazované soubory na server vejdou.
+// Bez kontroly by nasazení selhalo uprostřed s nesrozumitelnou chybou 552.
+func checkFreeSpace(conn *client, target Target, th *throttle, files []deployFile) error {
+	var needed int64
+	for _, f := range files {
+		if info, err := os.Stat(f.Local); err == nil {
+			needed += info.Size()
+		}
+	}
+
+	available, err := availableSpace(conn, target, th)
+	if err != nil {
+		return i18n.Errorf("volné místo na serveru nelze zjistit: %w", err)
+	}
+	if needed > available {
+		return i18n.Errorf("na serveru není dost místa: nasazení potřebuje %s, volných je jen %s", formatBytes(needed), formatBytes(available))
+	}
+	conn.logger.Info("Na serveru je dost volného místa.", "available", formatBytes(available), "needed", formatBytes(needed))
+	return nil
+}
+
+// availableSpace zjistí volné místo na serveru jedním ze způsobů:
+//   - spaceProbe: příkaz (např. "AVBL" nebo "SITE QUOTA"), v jehož odpovědi
+//     je první číslo za kódem odpovědi počet volných bajtů,
+//   - quotaBytes: známá kvóta účtu, od které se odečte velikost všech souborů na serveru.
+func availableSpace(conn *client, target Target, th *throttle) (int64, error) {
+	if target.SpaceProbe != "" {
+		raw, err := dialRaw(target, th, conn.logger)
+		if err != nil {
+			return 0, err
+		}
+		defer raw.Close()
+
+		code, msg, err := raw.cmd(-1, "%s", target.SpaceProbe)
+		if err != nil {
+			return 0, err
+		}
+		if code < 200 || code > 299 {
+			return 0, i18n.Errorf("server nepodporuje příkaz '%s' (%d %s)", target.SpaceProbe, code, msg)
+		}
+		number := numberRe.FindString(msg)
+		if number == "" {
+			return 0, i18n.Errorf("odpověď '%s' na příkaz '%s' neobsahuje počet bajtů", msg, target.SpaceProbe)
+		}
+		return strconv.ParseInt(number, 10, 64)
+	}
+
+	used, err := remoteUsage(conn, "/")
+	if err != nil {
+		return 0, err
+	}
+	return max(target.QuotaBytes-used, 0), nil
+}
+
+// remoteUsage sečte velikost všech souborů v adresáři na serveru včetně podadresářů.
+func remoteUsage(conn *client, dir string) (int64, error) {
+	entries, err := conn.List(dir)
+	if err != nil {
+		return 0, i18n.Errorf("chyba při výpisu adresáře '%s' na serveru: %w", dir, err)
+	}
+
+	var total int64
+	for _, e := range entries {
+		switch {
+		case e.Name == "." || e.Name == "..":
+		case e.Dir:
+			size, err := remoteUsage(conn, path.Join(dir, e.Name))
+			if err != nil {
+				return 0, err
+			}
+			total += size
+		default:
+			total += e.Size
+		}
+	}
+	return total, nil
+}
+
+// formatBytes převede počet bajtů na čitelný zápis (např. "1.5 MB").
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
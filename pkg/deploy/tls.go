@@ -0,0 +1,87 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+
+	"hugo72/internal/i18n"
+)
+
+// Režimy šifrovaného spojení s FTP serverem (FTPS).
+const (
+	tlsNone     = ""         // Nešifrované FTP
+	tlsExplicit = "explicit" // Šifrování zapnuté příkazem AUTH TLS (obvykle port 21)
+	tlsImplicit = "implicit" // Šifrované spojení od začátku (obvykle port 990)
+)
+
+// tlsConfig sestaví nastavení TLS pro cíl nasazení.
+//
+// Důvěra v certifikát serveru se určuje v tomto pořadí:
+//   - certFingerprint: certifikát serveru musí mít uvedený SHA-256 otisk,
+//     řetězec certifikátů se neověřuje (vhodné pro certifikáty podepsané vlastní autoritou)
+//   - caFile: certifikát musí být podepsán některou autoritou ze zadaného PEM souboru
+//   - jinak se použijí systémové certifikační autority
+//
+// Volba insecureSkipVerify ověřování úplně vypne. Je určena jen pro nouzové situace
+// a při každém použití vypíše varování.
+func tlsConfig(target Target, logger *slog.Logger) (*tls.Config, error) {
+	host := target.FtpHost
+	if h, _, err := net.SplitHostPort(target.FtpHost); err == nil {
+		host = h
+	}
+	config := &tls.Config{ServerName: host}
+
+	switch {
+	case target.InsecureSkipVerify:
+		logger.Warn("Ověřování certifikátu serveru je vypnuto (insecureSkipVerify)! Spojení může odposlouchávat nebo podvrhnout kdokoli po cestě, heslo k FTP není v bezpečí.",
+			"host", target.FtpHost)
+		config.InsecureSkipVerify = true
+	case target.CertFingerprint != "":
+		pinned, err := parseFingerprint(target.CertFingerprint)
+		if err != nil {
+			return nil, err
+		}
+		// Řetězec se neověřuje, rozhoduje výhradně shoda otisku certifikátu serveru.
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return i18n.Errorf("server '%s' nepředložil certifikát", target.FtpHost)
+			}
+			sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+			if hex.EncodeToString(sum[:]) != pinned {
+				return i18n.Errorf("otisk certifikátu serveru '%s' (%s) neodpovídá nastavenému certFingerprint",
+					target.FtpHost, hex.EncodeToString(sum[:]))
+			}
+			return nil
+		}
+	case target.CAFile != "":
+		pem, err := os.ReadFile(target.CAFile)
+		if err != nil {
+			return nil, i18n.Errorf("chyba při čtení certifikační autority '%s': %w", target.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, i18n.Errorf("soubor '%s' neobsahuje žádný platný PEM certifikát", target.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// parseFingerprint převede otisk certifikátu do jednotného tvaru (malá písmena bez dvojteček).
+// Přijímá zápis "AB:CD:..." i "abcd..." s případnou předponou "sha256:".
+func parseFingerprint(value string) (string, error) {
+	fp := strings.ToLower(strings.TrimSpace(value))
+	fp = strings.TrimPrefix(fp, "sha256:")
+	fp = strings.ReplaceAll(fp, ":", "")
+	if b, err := hex.DecodeString(fp); err != nil || len(b) != sha256.Size {
+		return "", i18n.Errorf("neplatný certFingerprint '%s', očekáván SHA-256 otisk v hexadecimálním tvaru", value)
+	}
+	return fp, nil
+}
@@ -0,0 +1,70 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/textproto"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// Transport je spojení s cílem nasazení. Nasazení (nahrávání s opakováním,
+// zámek, stránka údržby, deployed.json) používá jen tyto operace, takže mu
+// nezáleží na protokolu. Výchozí implementace je FTP klient, cíle
+// s protocol "sftp" používají klienta SFTP (viz sftpDialer); MemoryServer
+// nabízí spojení k serveru v paměti pro testy a další protokoly (S3) se
+// připojí implementací stejného rozhraní a Options.Dial.
+//
+// Cesty jsou oddělené lomítkem a začínají vzdáleným adresářem cíle (remoteDir).
+// Jedno spojení používá vždy jen jedna gorutina; souběžné nahrávání
+// (maxConnections) otevře další spojení. Nastavení práv (permissions)
+// a zjištění volného místa příkazem serveru (spaceProbe) používají samostatné
+// řídicí spojení FTP, s jiným spojením se proto nepoužijí.
+type Transport interface {
+	Stor(remote string, r io.Reader) error     // Nahraje obsah readeru do souboru, existující soubor přepíše
+	Retr(remote string) (io.ReadCloser, error) // Otevře soubor ke čtení; chybějící soubor pozná isNotExist
+	Delete(remote string) error                // Smaže soubor
+	List(dir string) ([]Entry, error)          // Vypíše obsah adresáře
+	MakeDir(dir string) error                  // Vytvoří adresář; chybu volající ignoruje, adresář už může existovat
+	Quit() error                               // Ukončí spojení
+}
+
+// Entry je položka výpisu adresáře na cíli.
+type Entry struct {
+	Name    string
+	Size    int64
+	Dir     bool
+	ModTime time.Time
+}
+
+// isNotExist rozliší chybějící soubor na serveru od jiné chyby. FTP server
+// hlásí chybějící soubor odpovědí 550, SFTP klient a MemoryServer chybou,
+// která odpovídá fs.ErrNotExist.
+func isNotExist(err error) bool {
+	var reply *textproto.Error
+	if errors.As(err, &reply) {
+		return reply.Code == ftp.StatusFileUnavailable
+	}
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// Dialer otevře nové spojení k cíli nasazení.
+type Dialer func(ctx context.Context, target Target) (Transport, error)
+
+// connect otevře spojení k cíli funkcí dial, bez ní k FTP serveru podle
+// nastavení cíle. Všechny příkazy nového spojení dodržují prodlevu podle th.
+func connect(ctx context.Context, target Target, th *throttle, logger *slog.Logger, dial Dialer) (*client, error) {
+	if dial == nil {
+		return connectToFtp(ctx, target, th, logger)
+	}
+	th.wait()
+	conn, err := dial(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return &client{conn: conn, throttle: th, logger: logger, ctx: ctx}, nil
+}
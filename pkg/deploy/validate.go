@@ -0,0 +1,76 @@
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"strings"
+
+	"hugo72/internal/i18n"
+)
+
+// validateFiles zkontroluje nasazovaný obsah ještě před připojením k serveru
+// a odmítne zjevně rozbité nebo prázdné sestavení:
+//   - každý soubor musí existovat a být běžným souborem,
+//   - mezi soubory musí být kořenový index.html (neplatí pro částečné nasazení),
+//   - všechny JSON soubory musí jít rozparsovat,
+//   - celková velikost nesmí být nulová ani (u úplného nasazení) menší než minTotalSize.
+//
+// Vrací všechny nalezené problémy najednou.
+func validateFiles(files []deployFile, minTotalSize int64, partial bool) error {
+	if len(files) == 0 {
+		if partial {
+			return errors.New(i18n.T("zadaným vzorům neodpovídá žádný soubor"))
+		}
+		return errors.New(i18n.T("seznam souborů k nasazení je prázdný"))
+	}
+
+	var problems []error
+	var total int64
+	hasIndex := false
+	for _, f := range files {
+		if path.Clean(f.Remote) == "index.html" {
+			hasIndex = true
+		}
+
+		info, err := os.Stat(f.Local)
+		if err != nil {
+			problems = append(problems, i18n.Errorf("soubor '%s' nelze použít: %w", f.Local, err))
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			problems = append(problems, i18n.Errorf("'%s' není běžný soubor", f.Local))
+			continue
+		}
+		total += info.Size()
+
+		if strings.EqualFold(path.Ext(f.Remote), ".json") {
+			if err := validateJSON(f.Local); err != nil {
+				problems = append(problems, err)
+			}
+		}
+	}
+
+	if !hasIndex && !partial {
+		problems = append(problems, errors.New(i18n.T("mezi soubory chybí index.html")))
+	}
+	if total == 0 {
+		problems = append(problems, errors.New(i18n.T("všechny soubory jsou prázdné")))
+	} else if total < minTotalSize && !partial {
+		problems = append(problems, i18n.Errorf("celková velikost %d B je podezřele malá (minTotalSize je %d B)", total, minTotalSize))
+	}
+	return errors.Join(problems...)
+}
+
+// validateJSON ověří, že soubor obsahuje platný JSON.
+func validateJSON(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return i18n.Errorf("chyba při čtení souboru '%s': %w", filePath, err)
+	}
+	if !json.Valid(data) {
+		return i18n.Errorf("soubor '%s' neobsahuje platný JSON", filePath)
+	}
+	return nil
+}
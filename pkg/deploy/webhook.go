@@ -0,0 +1,74 @@
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"hugo72/internal/i18n"
+)
+
+// webhookPayload je JSON zpráva odesílaná na webhook po dokončení nasazení.
+//
+// Příklad:
+//
+//	{
+//	  "runId": "20250601-100000-3f9a2c1b",
+//	  "name": "production",
+//	  "target": "ftp.example.com/www",
+//	  "success": true,
+//	  "startedAt": "2025-06-01T10:00:00+02:00",
+//	  "durationSeconds": 3.2,
+//	  "filesUploaded": ["index.html", "style.css"],
+//	  "errors": []
+//	}
+type webhookPayload struct {
+	RunID           string    `json:"runId,omitempty"`
+	Name            string    `json:"name"`
+	Target          string    `json:"target"`
+	Success         bool      `json:"success"`
+	StartedAt       time.Time `json:"startedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	FilesUploaded   []string  `json:"filesUploaded"`
+	Errors          []string  `json:"errors"`
+}
+
+// sendWebhook odešle výsledek nasazení metodou POST na zadanou adresu.
+// Odpověď mimo rozsah 2xx je považována za chybu.
+func sendWebhook(url, runID string, result *deployResult) error {
+	payload := webhookPayload{
+		RunID:           runID,
+		Name:            result.Name,
+		Target:          result.Target,
+		Success:         len(result.Errors) == 0,
+		StartedAt:       result.Start,
+		DurationSeconds: result.Duration.Seconds(),
+		FilesUploaded:   nonNil(result.filesWithStatus(statusUploaded)),
+		Errors:          nonNil(result.Errors),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return i18n.Errorf("chyba při serializaci webhooku: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return i18n.Errorf("chyba při volání webhooku '%s': %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return i18n.Errorf("webhook '%s' vrátil stav %s", url, resp.Status)
+	}
+	return nil
+}
+
+// nonNil zajistí, že se prázdný seznam v JSON zapíše jako [] místo null.
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}